@@ -14,6 +14,10 @@ CREATE TABLE accounts (
                           name TEXT NOT NULL,                          -- 账户名称
                           initial_balance REAL NOT NULL,               -- 初始余额
                           currency TEXT DEFAULT 'USD',                 -- 账户货币类型
+                          risk_mode TEXT,                              -- 单笔风险方式：percent（余额百分比）/amount（固定金额），为空时取已设置的一项
+                          risk_percent REAL,                           -- 单笔风险占当前余额的百分比
+                          risk_amount REAL,                            -- 单笔固定风险金额
+                          max_position_size REAL,                      -- 单笔最大持仓大小，为空或 0 表示不限制
                           created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- 账户创建时间
                           updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP   -- 账户最后更新时间
 );
@@ -24,6 +28,7 @@ CREATE TABLE strategies (
                             user_id INTEGER NOT NULL,                    -- 关联的用户ID
                             name TEXT NOT NULL,                          -- 策略名称
                             description TEXT,                            -- 策略详细描述
+                            min_reward_risk REAL,                        -- 计划的最小盈亏比，为空或 0 表示不限制
                             created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- 策略创建时间
                             updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- 策略最后更新时间
                             UNIQUE(user_id, name)                       -- 确保用户下策略名称唯一
@@ -36,7 +41,7 @@ CREATE TABLE trades (
                         strategy_id INTEGER,                        -- 关联的策略ID
 
     -- 计划阶段字段
-                        status TEXT NOT NULL DEFAULT 'planned',     -- 交易状态：planned/active/closed
+                        status TEXT NOT NULL DEFAULT 'planned',     -- 交易状态：planned/active/closed/cancelled，只能通过状态流转接口修改
                         symbol TEXT NOT NULL,                       -- 交易品种（如BTC/USD）
                         direction TEXT NOT NULL,                    -- 交易方向：long/short
                         planned_entry_price REAL,                   -- 计划入场价格
@@ -45,21 +50,29 @@ CREATE TABLE trades (
                         position_size REAL,                         -- 计划持仓大小
                         planned_risk_amount REAL,                   -- 计划风险金额
                         plan_notes TEXT,                            -- 交易计划备注
+                        draft INTEGER NOT NULL DEFAULT 0,           -- 草稿标记：1 表示计划未经规则校验，定稿或激活时校验并清除
 
     -- 执行阶段字段
                         actual_entry_time TIMESTAMP,                -- 实际入场时间
                         actual_entry_price REAL,                    -- 实际入场价格
                         actual_exit_time TIMESTAMP,                 -- 实际出场时间
                         actual_exit_price REAL,                     -- 实际出场价格
+                        mae_price REAL,                             -- 最大不利偏移价格（MAE），持仓期间最差价格
+                        mfe_price REAL,                             -- 最大有利偏移价格（MFE），持仓期间最好价格
                         commission REAL,                            -- 交易佣金费用
 
     -- 结果与复盘字段
-                        pnl REAL,                                   -- 盈亏金额（Profit and Loss）
-                        r_multiple REAL,                            -- 风险回报倍数
-                        exit_reason TEXT,                           -- 出场原因
+                        pnl REAL,                                   -- 盈亏金额（Profit and Loss，未扣除佣金），平仓时由服务端根据方向、价格和持仓大小计算
+                        r_multiple REAL,                            -- 风险回报倍数，扣除佣金后的盈亏 / 初始风险
+                        exit_reason TEXT,                           -- 出场原因代码，见 exit_reasons.code
                         execution_score INTEGER,                    -- 执行评分（1-5分）
                         reflection_notes TEXT,                      -- 交易反思笔记
 
+    -- 状态流转时间
+                        activated_at TIMESTAMP,                     -- 变为 active 的时间
+                        closed_at TIMESTAMP,                        -- 变为 closed 的时间
+                        cancelled_at TIMESTAMP,                     -- 变为 cancelled 的时间
+
                         created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- 交易创建时间
                         updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP   -- 交易最后更新时间
 );
@@ -74,6 +87,31 @@ CREATE TABLE snapshots (
                            updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP   -- 快照最后更新时间
 );
 
+-- 成交记录表：交易的每笔成交，交易的平均入场/出场价格、仓位、已实现盈亏和状态由成交推导
+CREATE TABLE executions (
+                            id INTEGER PRIMARY KEY AUTOINCREMENT,        -- 成交唯一ID
+                            trade_id INTEGER NOT NULL,                   -- 关联的交易ID
+                            side TEXT NOT NULL,                          -- 成交方向：buy/sell，与交易方向相同为入场，相反为出场
+                            quantity REAL NOT NULL,                      -- 成交数量
+                            price REAL NOT NULL,                         -- 成交价格
+                            fee REAL DEFAULT 0,                          -- 手续费
+                            executed_at TIMESTAMP NOT NULL,              -- 成交时间
+                            created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- 记录创建时间
+                            updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP   -- 记录最后更新时间
+);
+
+-- 市场时段表：用户定义的交易时段（如亚洲/伦敦/纽约盘），用于按入场时间分析，没有定义时使用默认时段
+CREATE TABLE market_sessions (
+                            id INTEGER PRIMARY KEY AUTOINCREMENT,        -- 时段唯一ID
+                            user_id INTEGER NOT NULL,                    -- 关联的用户ID
+                            name TEXT NOT NULL,                          -- 时段名称（如 London）
+                            timezone TEXT,                               -- 开盘/收盘时间的 IANA 时区，为空表示分析使用的时区
+                            open_time TEXT NOT NULL,                     -- 开盘时间 HH:MM
+                            close_time TEXT NOT NULL,                    -- 收盘时间 HH:MM，早于开盘时间表示跨越午夜
+                            created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- 创建时间
+                            updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP   -- 最后更新时间
+);
+
 -- 标签表：用于交易分类的标签系统
 CREATE TABLE tags (
                       id INTEGER PRIMARY KEY AUTOINCREMENT,        -- 标签唯一ID
@@ -92,4 +130,24 @@ CREATE TABLE trade_tags (
                             created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- 关联创建时间
                             updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- 关联最后更新时间
                             PRIMARY KEY (trade_id, tag_id)               -- 复合主键确保唯一关联
-);
\ No newline at end of file
+);
+
+-- 出场原因词表：用户管理的出场原因，user_id 为 0 的是所有用户共享的默认出场原因
+CREATE TABLE exit_reasons (
+                              id INTEGER PRIMARY KEY AUTOINCREMENT,        -- 出场原因唯一ID
+                              user_id INTEGER NOT NULL,                    -- 关联的用户ID，0 表示默认出场原因
+                              code TEXT NOT NULL,                          -- 出场原因代码（小写下划线，如 stop_hit）
+                              name TEXT NOT NULL,                          -- 出场原因名称
+                              created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- 创建时间
+                              updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- 最后更新时间
+                              UNIQUE(user_id, code)                       -- 确保用户下出场原因代码唯一
+);
+
+INSERT INTO exit_reasons (user_id, code, name) VALUES
+    (0, 'stop_hit', 'Stop hit'),
+    (0, 'target_hit', 'Target hit'),
+    (0, 'manual', 'Manual'),
+    (0, 'time_stop', 'Time stop'),
+    (0, 'trailing_stop', 'Trailing stop'),
+    (0, 'break_even', 'Break even'),
+    (0, 'other', 'Other');
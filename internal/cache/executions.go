@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-dev-frame/sponge/pkg/cache"
+	"github.com/go-dev-frame/sponge/pkg/encoding"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+
+	"helmsman/internal/database"
+	"helmsman/internal/model"
+)
+
+const (
+	// cache prefix key, must end with a colon
+	executionsCachePrefixKey = "executions:"
+	// ExecutionsExpireTime expire time
+	ExecutionsExpireTime = 5 * time.Minute
+)
+
+var _ ExecutionsCache = (*executionsCache)(nil)
+
+// ExecutionsCache cache interface
+type ExecutionsCache interface {
+	Set(ctx context.Context, id uint64, data *model.Executions, duration time.Duration) error
+	Get(ctx context.Context, id uint64) (*model.Executions, error)
+	MultiGet(ctx context.Context, ids []uint64) (map[uint64]*model.Executions, error)
+	MultiSet(ctx context.Context, data []*model.Executions, duration time.Duration) error
+	Del(ctx context.Context, id uint64) error
+	SetPlaceholder(ctx context.Context, id uint64) error
+	IsPlaceholderErr(err error) bool
+}
+
+// executionsCache define a cache struct
+type executionsCache struct {
+	cache cache.Cache
+}
+
+// NewExecutionsCache new a cache
+func NewExecutionsCache(cacheType *database.CacheType) ExecutionsCache {
+	jsonEncoding := encoding.JSONEncoding{}
+	cachePrefix := ""
+
+	cType := strings.ToLower(cacheType.CType)
+	switch cType {
+	case "redis":
+		c := cache.NewRedisCache(cacheType.Rdb, cachePrefix, jsonEncoding, func() interface{} {
+			return &model.Executions{}
+		})
+		return &executionsCache{cache: c}
+	case "memory":
+		c := cache.NewMemoryCache(cachePrefix, jsonEncoding, func() interface{} {
+			return &model.Executions{}
+		})
+		return &executionsCache{cache: c}
+	}
+
+	return nil // no cache
+}
+
+// GetExecutionsCacheKey cache key
+func (c *executionsCache) GetExecutionsCacheKey(id uint64) string {
+	return executionsCachePrefixKey + utils.Uint64ToStr(id)
+}
+
+// Set write to cache
+func (c *executionsCache) Set(ctx context.Context, id uint64, data *model.Executions, duration time.Duration) error {
+	if data == nil || id == 0 {
+		return nil
+	}
+	cacheKey := c.GetExecutionsCacheKey(id)
+	err := c.cache.Set(ctx, cacheKey, data, duration)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get cache value
+func (c *executionsCache) Get(ctx context.Context, id uint64) (*model.Executions, error) {
+	var data *model.Executions
+	cacheKey := c.GetExecutionsCacheKey(id)
+	err := c.cache.Get(ctx, cacheKey, &data)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// MultiSet multiple set cache
+func (c *executionsCache) MultiSet(ctx context.Context, data []*model.Executions, duration time.Duration) error {
+	valMap := make(map[string]interface{})
+	for _, v := range data {
+		cacheKey := c.GetExecutionsCacheKey(v.ID)
+		valMap[cacheKey] = v
+	}
+
+	err := c.cache.MultiSet(ctx, valMap, duration)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MultiGet multiple get cache, return key in map is id value
+func (c *executionsCache) MultiGet(ctx context.Context, ids []uint64) (map[uint64]*model.Executions, error) {
+	var keys []string
+	for _, v := range ids {
+		cacheKey := c.GetExecutionsCacheKey(v)
+		keys = append(keys, cacheKey)
+	}
+
+	itemMap := make(map[string]*model.Executions)
+	err := c.cache.MultiGet(ctx, keys, itemMap)
+	if err != nil {
+		return nil, err
+	}
+
+	retMap := make(map[uint64]*model.Executions)
+	for _, id := range ids {
+		val, ok := itemMap[c.GetExecutionsCacheKey(id)]
+		if ok {
+			retMap[id] = val
+		}
+	}
+
+	return retMap, nil
+}
+
+// Del delete cache
+func (c *executionsCache) Del(ctx context.Context, id uint64) error {
+	cacheKey := c.GetExecutionsCacheKey(id)
+	err := c.cache.Del(ctx, cacheKey)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetPlaceholder set placeholder value to cache
+func (c *executionsCache) SetPlaceholder(ctx context.Context, id uint64) error {
+	cacheKey := c.GetExecutionsCacheKey(id)
+	return c.cache.SetCacheWithNotFound(ctx, cacheKey)
+}
+
+// IsPlaceholderErr check if cache is placeholder error
+func (c *executionsCache) IsPlaceholderErr(err error) bool {
+	return errors.Is(err, cache.ErrPlaceholder)
+}
@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-dev-frame/sponge/pkg/gotest"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+
+	"helmsman/internal/database"
+	"helmsman/internal/model"
+)
+
+func newExecutionsCache() *gotest.Cache {
+	record1 := &model.Executions{}
+	record1.ID = 1
+	record2 := &model.Executions{}
+	record2.ID = 2
+	testData := map[string]interface{}{
+		utils.Uint64ToStr(record1.ID): record1,
+		utils.Uint64ToStr(record2.ID): record2,
+	}
+
+	c := gotest.NewCache(testData)
+	c.ICache = NewExecutionsCache(&database.CacheType{
+		CType: "redis",
+		Rdb:   c.RedisClient,
+	})
+	return c
+}
+
+func Test_executionsCache_Set(t *testing.T) {
+	c := newExecutionsCache()
+	defer c.Close()
+
+	record := c.TestDataSlice[0].(*model.Executions)
+	err := c.ICache.(ExecutionsCache).Set(c.Ctx, record.ID, record, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// nil data
+	err = c.ICache.(ExecutionsCache).Set(c.Ctx, 0, nil, time.Hour)
+	assert.NoError(t, err)
+}
+
+func Test_executionsCache_Get(t *testing.T) {
+	c := newExecutionsCache()
+	defer c.Close()
+
+	record := c.TestDataSlice[0].(*model.Executions)
+	err := c.ICache.(ExecutionsCache).Set(c.Ctx, record.ID, record, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.ICache.(ExecutionsCache).Get(c.Ctx, record.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, record, got)
+
+	// zero key error
+	_, err = c.ICache.(ExecutionsCache).Get(c.Ctx, 0)
+	assert.Error(t, err)
+}
+
+func Test_executionsCache_MultiGet(t *testing.T) {
+	c := newExecutionsCache()
+	defer c.Close()
+
+	var testData []*model.Executions
+	for _, data := range c.TestDataSlice {
+		testData = append(testData, data.(*model.Executions))
+	}
+
+	err := c.ICache.(ExecutionsCache).MultiSet(c.Ctx, testData, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.ICache.(ExecutionsCache).MultiGet(c.Ctx, c.GetIDs())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := c.GetTestData()
+	for k, v := range expected {
+		assert.Equal(t, got[utils.StrToUint64(k)], v.(*model.Executions))
+	}
+}
+
+func Test_executionsCache_MultiSet(t *testing.T) {
+	c := newExecutionsCache()
+	defer c.Close()
+
+	var testData []*model.Executions
+	for _, data := range c.TestDataSlice {
+		testData = append(testData, data.(*model.Executions))
+	}
+
+	err := c.ICache.(ExecutionsCache).MultiSet(c.Ctx, testData, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_executionsCache_Del(t *testing.T) {
+	c := newExecutionsCache()
+	defer c.Close()
+
+	record := c.TestDataSlice[0].(*model.Executions)
+	err := c.ICache.(ExecutionsCache).Del(c.Ctx, record.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_executionsCache_SetCacheWithNotFound(t *testing.T) {
+	c := newExecutionsCache()
+	defer c.Close()
+
+	record := c.TestDataSlice[0].(*model.Executions)
+	err := c.ICache.(ExecutionsCache).SetPlaceholder(c.Ctx, record.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := c.ICache.(ExecutionsCache).IsPlaceholderErr(err)
+	t.Log(b)
+}
+
+func TestNewExecutionsCache(t *testing.T) {
+	c := NewExecutionsCache(&database.CacheType{
+		CType: "",
+	})
+	assert.Nil(t, c)
+	c = NewExecutionsCache(&database.CacheType{
+		CType: "memory",
+	})
+	assert.NotNil(t, c)
+	c = NewExecutionsCache(&database.CacheType{
+		CType: "redis",
+	})
+	assert.NotNil(t, c)
+}
@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-dev-frame/sponge/pkg/cache"
+	"github.com/go-dev-frame/sponge/pkg/encoding"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+
+	"helmsman/internal/database"
+	"helmsman/internal/model"
+)
+
+const (
+	// cache prefix key, must end with a colon
+	exitReasonsCachePrefixKey = "exitReasons:"
+	// ExitReasonsExpireTime expire time
+	ExitReasonsExpireTime = 5 * time.Minute
+)
+
+var _ ExitReasonsCache = (*exitReasonsCache)(nil)
+
+// ExitReasonsCache cache interface
+type ExitReasonsCache interface {
+	Set(ctx context.Context, id uint64, data *model.ExitReasons, duration time.Duration) error
+	Get(ctx context.Context, id uint64) (*model.ExitReasons, error)
+	MultiGet(ctx context.Context, ids []uint64) (map[uint64]*model.ExitReasons, error)
+	MultiSet(ctx context.Context, data []*model.ExitReasons, duration time.Duration) error
+	Del(ctx context.Context, id uint64) error
+	SetPlaceholder(ctx context.Context, id uint64) error
+	IsPlaceholderErr(err error) bool
+}
+
+// exitReasonsCache define a cache struct
+type exitReasonsCache struct {
+	cache cache.Cache
+}
+
+// NewExitReasonsCache new a cache
+func NewExitReasonsCache(cacheType *database.CacheType) ExitReasonsCache {
+	jsonEncoding := encoding.JSONEncoding{}
+	cachePrefix := ""
+
+	cType := strings.ToLower(cacheType.CType)
+	switch cType {
+	case "redis":
+		c := cache.NewRedisCache(cacheType.Rdb, cachePrefix, jsonEncoding, func() interface{} {
+			return &model.ExitReasons{}
+		})
+		return &exitReasonsCache{cache: c}
+	case "memory":
+		c := cache.NewMemoryCache(cachePrefix, jsonEncoding, func() interface{} {
+			return &model.ExitReasons{}
+		})
+		return &exitReasonsCache{cache: c}
+	}
+
+	return nil // no cache
+}
+
+// GetExitReasonsCacheKey cache key
+func (c *exitReasonsCache) GetExitReasonsCacheKey(id uint64) string {
+	return exitReasonsCachePrefixKey + utils.Uint64ToStr(id)
+}
+
+// Set write to cache
+func (c *exitReasonsCache) Set(ctx context.Context, id uint64, data *model.ExitReasons, duration time.Duration) error {
+	if data == nil || id == 0 {
+		return nil
+	}
+	cacheKey := c.GetExitReasonsCacheKey(id)
+	err := c.cache.Set(ctx, cacheKey, data, duration)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get cache value
+func (c *exitReasonsCache) Get(ctx context.Context, id uint64) (*model.ExitReasons, error) {
+	var data *model.ExitReasons
+	cacheKey := c.GetExitReasonsCacheKey(id)
+	err := c.cache.Get(ctx, cacheKey, &data)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// MultiSet multiple set cache
+func (c *exitReasonsCache) MultiSet(ctx context.Context, data []*model.ExitReasons, duration time.Duration) error {
+	valMap := make(map[string]interface{})
+	for _, v := range data {
+		cacheKey := c.GetExitReasonsCacheKey(v.ID)
+		valMap[cacheKey] = v
+	}
+
+	err := c.cache.MultiSet(ctx, valMap, duration)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MultiGet multiple get cache, return key in map is id value
+func (c *exitReasonsCache) MultiGet(ctx context.Context, ids []uint64) (map[uint64]*model.ExitReasons, error) {
+	var keys []string
+	for _, v := range ids {
+		cacheKey := c.GetExitReasonsCacheKey(v)
+		keys = append(keys, cacheKey)
+	}
+
+	itemMap := make(map[string]*model.ExitReasons)
+	err := c.cache.MultiGet(ctx, keys, itemMap)
+	if err != nil {
+		return nil, err
+	}
+
+	retMap := make(map[uint64]*model.ExitReasons)
+	for _, id := range ids {
+		val, ok := itemMap[c.GetExitReasonsCacheKey(id)]
+		if ok {
+			retMap[id] = val
+		}
+	}
+
+	return retMap, nil
+}
+
+// Del delete cache
+func (c *exitReasonsCache) Del(ctx context.Context, id uint64) error {
+	cacheKey := c.GetExitReasonsCacheKey(id)
+	err := c.cache.Del(ctx, cacheKey)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetPlaceholder set placeholder value to cache
+func (c *exitReasonsCache) SetPlaceholder(ctx context.Context, id uint64) error {
+	cacheKey := c.GetExitReasonsCacheKey(id)
+	return c.cache.SetCacheWithNotFound(ctx, cacheKey)
+}
+
+// IsPlaceholderErr check if cache is placeholder error
+func (c *exitReasonsCache) IsPlaceholderErr(err error) bool {
+	return errors.Is(err, cache.ErrPlaceholder)
+}
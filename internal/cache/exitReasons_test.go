@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-dev-frame/sponge/pkg/gotest"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+
+	"helmsman/internal/database"
+	"helmsman/internal/model"
+)
+
+func newExitReasonsCache() *gotest.Cache {
+	record1 := &model.ExitReasons{}
+	record1.ID = 1
+	record2 := &model.ExitReasons{}
+	record2.ID = 2
+	testData := map[string]interface{}{
+		utils.Uint64ToStr(record1.ID): record1,
+		utils.Uint64ToStr(record2.ID): record2,
+	}
+
+	c := gotest.NewCache(testData)
+	c.ICache = NewExitReasonsCache(&database.CacheType{
+		CType: "redis",
+		Rdb:   c.RedisClient,
+	})
+	return c
+}
+
+func Test_exitReasonsCache_Set(t *testing.T) {
+	c := newExitReasonsCache()
+	defer c.Close()
+
+	record := c.TestDataSlice[0].(*model.ExitReasons)
+	err := c.ICache.(ExitReasonsCache).Set(c.Ctx, record.ID, record, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// nil data
+	err = c.ICache.(ExitReasonsCache).Set(c.Ctx, 0, nil, time.Hour)
+	assert.NoError(t, err)
+}
+
+func Test_exitReasonsCache_Get(t *testing.T) {
+	c := newExitReasonsCache()
+	defer c.Close()
+
+	record := c.TestDataSlice[0].(*model.ExitReasons)
+	err := c.ICache.(ExitReasonsCache).Set(c.Ctx, record.ID, record, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.ICache.(ExitReasonsCache).Get(c.Ctx, record.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, record, got)
+
+	// zero key error
+	_, err = c.ICache.(ExitReasonsCache).Get(c.Ctx, 0)
+	assert.Error(t, err)
+}
+
+func Test_exitReasonsCache_MultiGet(t *testing.T) {
+	c := newExitReasonsCache()
+	defer c.Close()
+
+	var testData []*model.ExitReasons
+	for _, data := range c.TestDataSlice {
+		testData = append(testData, data.(*model.ExitReasons))
+	}
+
+	err := c.ICache.(ExitReasonsCache).MultiSet(c.Ctx, testData, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.ICache.(ExitReasonsCache).MultiGet(c.Ctx, c.GetIDs())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := c.GetTestData()
+	for k, v := range expected {
+		assert.Equal(t, got[utils.StrToUint64(k)], v.(*model.ExitReasons))
+	}
+}
+
+func Test_exitReasonsCache_MultiSet(t *testing.T) {
+	c := newExitReasonsCache()
+	defer c.Close()
+
+	var testData []*model.ExitReasons
+	for _, data := range c.TestDataSlice {
+		testData = append(testData, data.(*model.ExitReasons))
+	}
+
+	err := c.ICache.(ExitReasonsCache).MultiSet(c.Ctx, testData, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_exitReasonsCache_Del(t *testing.T) {
+	c := newExitReasonsCache()
+	defer c.Close()
+
+	record := c.TestDataSlice[0].(*model.ExitReasons)
+	err := c.ICache.(ExitReasonsCache).Del(c.Ctx, record.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_exitReasonsCache_SetCacheWithNotFound(t *testing.T) {
+	c := newExitReasonsCache()
+	defer c.Close()
+
+	record := c.TestDataSlice[0].(*model.ExitReasons)
+	err := c.ICache.(ExitReasonsCache).SetPlaceholder(c.Ctx, record.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := c.ICache.(ExitReasonsCache).IsPlaceholderErr(err)
+	t.Log(b)
+}
+
+func TestNewExitReasonsCache(t *testing.T) {
+	c := NewExitReasonsCache(&database.CacheType{
+		CType: "",
+	})
+	assert.Nil(t, c)
+	c = NewExitReasonsCache(&database.CacheType{
+		CType: "memory",
+	})
+	assert.NotNil(t, c)
+	c = NewExitReasonsCache(&database.CacheType{
+		CType: "redis",
+	})
+	assert.NotNil(t, c)
+}
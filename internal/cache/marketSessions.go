@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-dev-frame/sponge/pkg/cache"
+	"github.com/go-dev-frame/sponge/pkg/encoding"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+
+	"helmsman/internal/database"
+	"helmsman/internal/model"
+)
+
+const (
+	// cache prefix key, must end with a colon
+	marketSessionsCachePrefixKey = "marketSessions:"
+	// MarketSessionsExpireTime expire time
+	MarketSessionsExpireTime = 5 * time.Minute
+)
+
+var _ MarketSessionsCache = (*marketSessionsCache)(nil)
+
+// MarketSessionsCache cache interface
+type MarketSessionsCache interface {
+	Set(ctx context.Context, id uint64, data *model.MarketSessions, duration time.Duration) error
+	Get(ctx context.Context, id uint64) (*model.MarketSessions, error)
+	MultiGet(ctx context.Context, ids []uint64) (map[uint64]*model.MarketSessions, error)
+	MultiSet(ctx context.Context, data []*model.MarketSessions, duration time.Duration) error
+	Del(ctx context.Context, id uint64) error
+	SetPlaceholder(ctx context.Context, id uint64) error
+	IsPlaceholderErr(err error) bool
+}
+
+// marketSessionsCache define a cache struct
+type marketSessionsCache struct {
+	cache cache.Cache
+}
+
+// NewMarketSessionsCache new a cache
+func NewMarketSessionsCache(cacheType *database.CacheType) MarketSessionsCache {
+	jsonEncoding := encoding.JSONEncoding{}
+	cachePrefix := ""
+
+	cType := strings.ToLower(cacheType.CType)
+	switch cType {
+	case "redis":
+		c := cache.NewRedisCache(cacheType.Rdb, cachePrefix, jsonEncoding, func() interface{} {
+			return &model.MarketSessions{}
+		})
+		return &marketSessionsCache{cache: c}
+	case "memory":
+		c := cache.NewMemoryCache(cachePrefix, jsonEncoding, func() interface{} {
+			return &model.MarketSessions{}
+		})
+		return &marketSessionsCache{cache: c}
+	}
+
+	return nil // no cache
+}
+
+// GetMarketSessionsCacheKey cache key
+func (c *marketSessionsCache) GetMarketSessionsCacheKey(id uint64) string {
+	return marketSessionsCachePrefixKey + utils.Uint64ToStr(id)
+}
+
+// Set write to cache
+func (c *marketSessionsCache) Set(ctx context.Context, id uint64, data *model.MarketSessions, duration time.Duration) error {
+	if data == nil || id == 0 {
+		return nil
+	}
+	cacheKey := c.GetMarketSessionsCacheKey(id)
+	err := c.cache.Set(ctx, cacheKey, data, duration)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get cache value
+func (c *marketSessionsCache) Get(ctx context.Context, id uint64) (*model.MarketSessions, error) {
+	var data *model.MarketSessions
+	cacheKey := c.GetMarketSessionsCacheKey(id)
+	err := c.cache.Get(ctx, cacheKey, &data)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// MultiSet multiple set cache
+func (c *marketSessionsCache) MultiSet(ctx context.Context, data []*model.MarketSessions, duration time.Duration) error {
+	valMap := make(map[string]interface{})
+	for _, v := range data {
+		cacheKey := c.GetMarketSessionsCacheKey(v.ID)
+		valMap[cacheKey] = v
+	}
+
+	err := c.cache.MultiSet(ctx, valMap, duration)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MultiGet multiple get cache, return key in map is id value
+func (c *marketSessionsCache) MultiGet(ctx context.Context, ids []uint64) (map[uint64]*model.MarketSessions, error) {
+	var keys []string
+	for _, v := range ids {
+		cacheKey := c.GetMarketSessionsCacheKey(v)
+		keys = append(keys, cacheKey)
+	}
+
+	itemMap := make(map[string]*model.MarketSessions)
+	err := c.cache.MultiGet(ctx, keys, itemMap)
+	if err != nil {
+		return nil, err
+	}
+
+	retMap := make(map[uint64]*model.MarketSessions)
+	for _, id := range ids {
+		val, ok := itemMap[c.GetMarketSessionsCacheKey(id)]
+		if ok {
+			retMap[id] = val
+		}
+	}
+
+	return retMap, nil
+}
+
+// Del delete cache
+func (c *marketSessionsCache) Del(ctx context.Context, id uint64) error {
+	cacheKey := c.GetMarketSessionsCacheKey(id)
+	err := c.cache.Del(ctx, cacheKey)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetPlaceholder set placeholder value to cache
+func (c *marketSessionsCache) SetPlaceholder(ctx context.Context, id uint64) error {
+	cacheKey := c.GetMarketSessionsCacheKey(id)
+	return c.cache.SetCacheWithNotFound(ctx, cacheKey)
+}
+
+// IsPlaceholderErr check if cache is placeholder error
+func (c *marketSessionsCache) IsPlaceholderErr(err error) bool {
+	return errors.Is(err, cache.ErrPlaceholder)
+}
@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-dev-frame/sponge/pkg/gotest"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+
+	"helmsman/internal/database"
+	"helmsman/internal/model"
+)
+
+func newMarketSessionsCache() *gotest.Cache {
+	record1 := &model.MarketSessions{}
+	record1.ID = 1
+	record2 := &model.MarketSessions{}
+	record2.ID = 2
+	testData := map[string]interface{}{
+		utils.Uint64ToStr(record1.ID): record1,
+		utils.Uint64ToStr(record2.ID): record2,
+	}
+
+	c := gotest.NewCache(testData)
+	c.ICache = NewMarketSessionsCache(&database.CacheType{
+		CType: "redis",
+		Rdb:   c.RedisClient,
+	})
+	return c
+}
+
+func Test_marketSessionsCache_Set(t *testing.T) {
+	c := newMarketSessionsCache()
+	defer c.Close()
+
+	record := c.TestDataSlice[0].(*model.MarketSessions)
+	err := c.ICache.(MarketSessionsCache).Set(c.Ctx, record.ID, record, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// nil data
+	err = c.ICache.(MarketSessionsCache).Set(c.Ctx, 0, nil, time.Hour)
+	assert.NoError(t, err)
+}
+
+func Test_marketSessionsCache_Get(t *testing.T) {
+	c := newMarketSessionsCache()
+	defer c.Close()
+
+	record := c.TestDataSlice[0].(*model.MarketSessions)
+	err := c.ICache.(MarketSessionsCache).Set(c.Ctx, record.ID, record, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.ICache.(MarketSessionsCache).Get(c.Ctx, record.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, record, got)
+
+	// zero key error
+	_, err = c.ICache.(MarketSessionsCache).Get(c.Ctx, 0)
+	assert.Error(t, err)
+}
+
+func Test_marketSessionsCache_MultiGet(t *testing.T) {
+	c := newMarketSessionsCache()
+	defer c.Close()
+
+	var testData []*model.MarketSessions
+	for _, data := range c.TestDataSlice {
+		testData = append(testData, data.(*model.MarketSessions))
+	}
+
+	err := c.ICache.(MarketSessionsCache).MultiSet(c.Ctx, testData, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.ICache.(MarketSessionsCache).MultiGet(c.Ctx, c.GetIDs())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := c.GetTestData()
+	for k, v := range expected {
+		assert.Equal(t, got[utils.StrToUint64(k)], v.(*model.MarketSessions))
+	}
+}
+
+func Test_marketSessionsCache_MultiSet(t *testing.T) {
+	c := newMarketSessionsCache()
+	defer c.Close()
+
+	var testData []*model.MarketSessions
+	for _, data := range c.TestDataSlice {
+		testData = append(testData, data.(*model.MarketSessions))
+	}
+
+	err := c.ICache.(MarketSessionsCache).MultiSet(c.Ctx, testData, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_marketSessionsCache_Del(t *testing.T) {
+	c := newMarketSessionsCache()
+	defer c.Close()
+
+	record := c.TestDataSlice[0].(*model.MarketSessions)
+	err := c.ICache.(MarketSessionsCache).Del(c.Ctx, record.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_marketSessionsCache_SetCacheWithNotFound(t *testing.T) {
+	c := newMarketSessionsCache()
+	defer c.Close()
+
+	record := c.TestDataSlice[0].(*model.MarketSessions)
+	err := c.ICache.(MarketSessionsCache).SetPlaceholder(c.Ctx, record.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := c.ICache.(MarketSessionsCache).IsPlaceholderErr(err)
+	t.Log(b)
+}
+
+func TestNewMarketSessionsCache(t *testing.T) {
+	c := NewMarketSessionsCache(&database.CacheType{
+		CType: "",
+	})
+	assert.Nil(t, c)
+	c = NewMarketSessionsCache(&database.CacheType{
+		CType: "memory",
+	})
+	assert.NotNil(t, c)
+	c = NewMarketSessionsCache(&database.CacheType{
+		CType: "redis",
+	})
+	assert.NotNil(t, c)
+}
@@ -103,6 +103,18 @@ func (d *accountsDao) updateDataByID(ctx context.Context, db *gorm.DB, table *mo
 	if table.Currency != "" {
 		update["currency"] = table.Currency
 	}
+	if table.RiskMode != "" {
+		update["risk_mode"] = table.RiskMode
+	}
+	if table.RiskPercent != 0 {
+		update["risk_percent"] = table.RiskPercent
+	}
+	if table.RiskAmount != 0 {
+		update["risk_amount"] = table.RiskAmount
+	}
+	if table.MaxPositionSize != 0 {
+		update["max_position_size"] = table.MaxPositionSize
+	}
 
 	return db.WithContext(ctx).Model(table).Updates(update).Error
 }
@@ -0,0 +1,275 @@
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+
+	"github.com/go-dev-frame/sponge/pkg/logger"
+	"github.com/go-dev-frame/sponge/pkg/sgorm/query"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+
+	"helmsman/internal/cache"
+	"helmsman/internal/database"
+	"helmsman/internal/model"
+)
+
+var _ ExecutionsDao = (*executionsDao)(nil)
+
+// ExecutionsDao defining the dao interface
+type ExecutionsDao interface {
+	Create(ctx context.Context, table *model.Executions) error
+	DeleteByID(ctx context.Context, id uint64) error
+	UpdateByID(ctx context.Context, table *model.Executions) error
+	GetByID(ctx context.Context, id uint64) (*model.Executions, error)
+	GetByColumns(ctx context.Context, params *query.Params) ([]*model.Executions, int64, error)
+	GetByTradeID(ctx context.Context, tradeID uint64) ([]*model.Executions, error)
+
+	CreateByTx(ctx context.Context, tx *gorm.DB, table *model.Executions) (uint64, error)
+	DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64) error
+	UpdateByTx(ctx context.Context, tx *gorm.DB, table *model.Executions) error
+	GetByTradeIDByTx(ctx context.Context, tx *gorm.DB, tradeID uint64) ([]*model.Executions, error)
+	DeleteByTradeIDByTx(ctx context.Context, tx *gorm.DB, tradeID uint64) error
+}
+
+type executionsDao struct {
+	db    *gorm.DB
+	cache cache.ExecutionsCache // if nil, the cache is not used.
+	sfg   *singleflight.Group   // if cache is nil, the sfg is not used.
+}
+
+// NewExecutionsDao creating the dao interface
+func NewExecutionsDao(db *gorm.DB, xCache cache.ExecutionsCache) ExecutionsDao {
+	if xCache == nil {
+		return &executionsDao{db: db}
+	}
+	return &executionsDao{
+		db:    db,
+		cache: xCache,
+		sfg:   new(singleflight.Group),
+	}
+}
+
+func (d *executionsDao) deleteCache(ctx context.Context, id uint64) error {
+	if d.cache != nil {
+		return d.cache.Del(ctx, id)
+	}
+	return nil
+}
+
+// Create a new executions, insert the record and the id value is written back to the table
+func (d *executionsDao) Create(ctx context.Context, table *model.Executions) error {
+	return d.db.WithContext(ctx).Create(table).Error
+}
+
+// DeleteByID delete a executions by id
+func (d *executionsDao) DeleteByID(ctx context.Context, id uint64) error {
+	err := d.db.WithContext(ctx).Where("id = ?", id).Delete(&model.Executions{}).Error
+	if err != nil {
+		return err
+	}
+
+	// delete cache
+	_ = d.deleteCache(ctx, id)
+
+	return nil
+}
+
+// UpdateByID update a executions by id, support partial update
+func (d *executionsDao) UpdateByID(ctx context.Context, table *model.Executions) error {
+	err := d.updateDataByID(ctx, d.db, table)
+
+	// delete cache
+	_ = d.deleteCache(ctx, table.ID)
+
+	return err
+}
+
+func (d *executionsDao) updateDataByID(ctx context.Context, db *gorm.DB, table *model.Executions) error {
+	if table.ID < 1 {
+		return errors.New("id cannot be 0")
+	}
+
+	update := map[string]interface{}{}
+
+	if table.TradeID != 0 {
+		update["trade_id"] = table.TradeID
+	}
+	if table.Side != "" {
+		update["side"] = table.Side
+	}
+	if table.Quantity != 0 {
+		update["quantity"] = table.Quantity
+	}
+	if table.Price != 0 {
+		update["price"] = table.Price
+	}
+	if table.Fee != 0 {
+		update["fee"] = table.Fee
+	}
+	if table.ExecutedAt != "" {
+		update["executed_at"] = table.ExecutedAt
+	}
+	if table.UpdatedAt != "" {
+		update["updated_at"] = table.UpdatedAt
+	}
+
+	return db.WithContext(ctx).Model(table).Updates(update).Error
+}
+
+// GetByID get a executions by id
+func (d *executionsDao) GetByID(ctx context.Context, id uint64) (*model.Executions, error) {
+	// no cache
+	if d.cache == nil {
+		record := &model.Executions{}
+		err := d.db.WithContext(ctx).Where("id = ?", id).First(record).Error
+		return record, err
+	}
+
+	// get from cache
+	record, err := d.cache.Get(ctx, id)
+	if err == nil {
+		return record, nil
+	}
+
+	// get from database
+	if errors.Is(err, database.ErrCacheNotFound) {
+		// for the same id, prevent high concurrent simultaneous access to database
+		val, err, _ := d.sfg.Do(utils.Uint64ToStr(id), func() (interface{}, error) { //nolint
+			table := &model.Executions{}
+			err = d.db.WithContext(ctx).Where("id = ?", id).First(table).Error
+			if err != nil {
+				if errors.Is(err, database.ErrRecordNotFound) {
+					// set placeholder cache to prevent cache penetration, default expiration time 10 minutes
+					if err = d.cache.SetPlaceholder(ctx, id); err != nil {
+						logger.Warn("cache.SetPlaceholder error", logger.Err(err), logger.Any("id", id))
+					}
+					return nil, database.ErrRecordNotFound
+				}
+				return nil, err
+			}
+			// set cache
+			if err = d.cache.Set(ctx, id, table, cache.ExecutionsExpireTime); err != nil {
+				logger.Warn("cache.Set error", logger.Err(err), logger.Any("id", id))
+			}
+			return table, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		table, ok := val.(*model.Executions)
+		if !ok {
+			return nil, database.ErrRecordNotFound
+		}
+		return table, nil
+	}
+
+	if d.cache.IsPlaceholderErr(err) {
+		return nil, database.ErrRecordNotFound
+	}
+
+	return nil, err
+}
+
+// GetByColumns get a paginated list of executionss by custom conditions.
+// For more details, please refer to https://go-sponge.com/component/data/custom-page-query.html
+func (d *executionsDao) GetByColumns(ctx context.Context, params *query.Params) ([]*model.Executions, int64, error) {
+	queryStr, args, err := params.ConvertToGormConditions(query.WithWhitelistNames(model.ExecutionsColumnNames))
+	if err != nil {
+		return nil, 0, errors.New("query params error: " + err.Error())
+	}
+
+	var total int64
+	if params.Sort != "ignore count" { // determine if count is required
+		err = d.db.WithContext(ctx).Model(&model.Executions{}).Where(queryStr, args...).Count(&total).Error
+		if err != nil {
+			return nil, 0, err
+		}
+		if total == 0 {
+			return nil, total, nil
+		}
+	}
+
+	records := []*model.Executions{}
+	order, limit, offset := params.ConvertToPage()
+	err = d.db.WithContext(ctx).Order(order).Limit(limit).Offset(offset).Where(queryStr, args...).Find(&records).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return records, total, err
+}
+
+// GetByTradeID get all executions of a trade, ordered by execution time and id
+func (d *executionsDao) GetByTradeID(ctx context.Context, tradeID uint64) ([]*model.Executions, error) {
+	return d.getByTradeID(ctx, d.db, tradeID)
+}
+
+func (d *executionsDao) getByTradeID(ctx context.Context, db *gorm.DB, tradeID uint64) ([]*model.Executions, error) {
+	records := []*model.Executions{}
+	err := db.WithContext(ctx).Where("trade_id = ?", tradeID).Order("executed_at ASC, id ASC").Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// CreateByTx create a record in the database using the provided transaction
+func (d *executionsDao) CreateByTx(ctx context.Context, tx *gorm.DB, table *model.Executions) (uint64, error) {
+	err := tx.WithContext(ctx).Create(table).Error
+	return table.ID, err
+}
+
+// DeleteByTx delete a record by id in the database using the provided transaction
+func (d *executionsDao) DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64) error {
+	err := tx.WithContext(ctx).Where("id = ?", id).Delete(&model.Executions{}).Error
+	if err != nil {
+		return err
+	}
+
+	// delete cache
+	_ = d.deleteCache(ctx, id)
+
+	return nil
+}
+
+// UpdateByTx update a record by id in the database using the provided transaction
+func (d *executionsDao) UpdateByTx(ctx context.Context, tx *gorm.DB, table *model.Executions) error {
+	err := d.updateDataByID(ctx, tx, table)
+
+	// delete cache
+	_ = d.deleteCache(ctx, table.ID)
+
+	return err
+}
+
+// GetByTradeIDByTx get the executions of a trades in the order of their time using the provided transaction
+func (d *executionsDao) GetByTradeIDByTx(ctx context.Context, tx *gorm.DB, tradeID uint64) ([]*model.Executions, error) {
+	return d.getByTradeID(ctx, tx, tradeID)
+}
+
+// DeleteByTradeIDByTx delete the executions of a trades using the provided transaction
+func (d *executionsDao) DeleteByTradeIDByTx(ctx context.Context, tx *gorm.DB, tradeID uint64) error {
+	var ids []uint64
+	err := tx.WithContext(ctx).Model(&model.Executions{}).Where("trade_id = ?", tradeID).Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err = tx.WithContext(ctx).Where("id IN ?", ids).Delete(&model.Executions{}).Error
+	if err != nil {
+		return err
+	}
+
+	// delete cache
+	for _, id := range ids {
+		_ = d.deleteCache(ctx, id)
+	}
+
+	return nil
+}
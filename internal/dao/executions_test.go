@@ -0,0 +1,309 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-dev-frame/sponge/pkg/gotest"
+	"github.com/go-dev-frame/sponge/pkg/sgorm/query"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/cache"
+	"helmsman/internal/database"
+	"helmsman/internal/model"
+)
+
+func newExecutionsDao() *gotest.Dao {
+	testData := &model.Executions{}
+	testData.ID = 1
+	testData.Side = model.ExecutionsSideBuy
+	// you can set the other fields of testData here, such as:
+	//testData.CreatedAt = time.Now()
+	//testData.UpdatedAt = testData.CreatedAt
+
+	// init mock cache
+	//c := gotest.NewCache(map[string]interface{}{"no cache": testData}) // to test mysql, disable caching
+	c := gotest.NewCache(map[string]interface{}{utils.Uint64ToStr(testData.ID): testData})
+	c.ICache = cache.NewExecutionsCache(&database.CacheType{
+		CType: "redis",
+		Rdb:   c.RedisClient,
+	})
+
+	// init mock dao
+	d := gotest.NewDao(c, testData)
+	d.IDao = NewExecutionsDao(d.DB, c.ICache.(cache.ExecutionsCache))
+
+	return d
+}
+
+func Test_executionsDao_Create(t *testing.T) {
+	d := newExecutionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Executions)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("INSERT INTO .*").
+		WithArgs(d.GetAnyArgs(testData)...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(ExecutionsDao).Create(d.Ctx, testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_executionsDao_DeleteByID(t *testing.T) {
+	d := newExecutionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Executions)
+	expectedSQLForDeletion := "DELETE .*"
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec(expectedSQLForDeletion).
+		WithArgs(testData.ID).
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(ExecutionsDao).DeleteByID(d.Ctx, testData.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// zero id error
+	err = d.IDao.(ExecutionsDao).DeleteByID(d.Ctx, 0)
+	assert.Error(t, err)
+}
+
+func Test_executionsDao_UpdateByID(t *testing.T) {
+	d := newExecutionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Executions)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs(testData.Side, testData.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(ExecutionsDao).UpdateByID(d.Ctx, testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// zero id error
+	err = d.IDao.(ExecutionsDao).UpdateByID(d.Ctx, &model.Executions{})
+	assert.Error(t, err)
+
+}
+
+func Test_executionsDao_GetByID(t *testing.T) {
+	d := newExecutionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Executions)
+
+	// column names and corresponding data
+	rows := sqlmock.NewRows([]string{"id"}).
+		AddRow(testData.ID)
+
+	d.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(testData.ID, 1).
+		WillReturnRows(rows)
+
+	_, err := d.IDao.(ExecutionsDao).GetByID(d.Ctx, testData.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.SQLMock.ExpectationsWereMet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error test
+	d.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(2).
+		WillReturnRows(rows)
+	_, err = d.IDao.(ExecutionsDao).GetByID(d.Ctx, 2)
+	assert.Error(t, err)
+
+	d.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(3, 4).
+		WillReturnRows(rows)
+	_, err = d.IDao.(ExecutionsDao).GetByID(d.Ctx, 4)
+	assert.Error(t, err)
+}
+
+func Test_executionsDao_GetByColumns(t *testing.T) {
+	d := newExecutionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Executions)
+
+	// column names and corresponding data
+	rows := sqlmock.NewRows([]string{"id"}).
+		AddRow(testData.ID)
+
+	d.SQLMock.ExpectQuery("SELECT .*").WillReturnRows(rows)
+
+	_, _, err := d.IDao.(ExecutionsDao).GetByColumns(d.Ctx, &query.Params{
+		Page:  0,
+		Limit: 10,
+		Sort:  "ignore count", // ignore test count(*)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.SQLMock.ExpectationsWereMet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// err test
+	_, _, err = d.IDao.(ExecutionsDao).GetByColumns(d.Ctx, &query.Params{
+		Page:  0,
+		Limit: 10,
+		Columns: []query.Column{
+			{
+				Name:  "id",
+				Exp:   "<",
+				Value: 0,
+			},
+		},
+	})
+	assert.Error(t, err)
+
+	// error test
+	dao := &executionsDao{}
+	_, _, err = dao.GetByColumns(context.Background(), &query.Params{Columns: []query.Column{{}}})
+	t.Log(err)
+}
+
+func Test_executionsDao_GetByTradeID(t *testing.T) {
+	d := newExecutionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Executions)
+
+	// column names and corresponding data
+	rows := sqlmock.NewRows([]string{"id", "trade_id", "side"}).
+		AddRow(testData.ID, 1, testData.Side)
+
+	d.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	records, err := d.IDao.(ExecutionsDao).GetByTradeID(d.Ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, records, 1)
+
+	// get error test
+	d.SQLMock.ExpectQuery("SELECT .*").WillReturnError(errors.New("mock error"))
+	_, err = d.IDao.(ExecutionsDao).GetByTradeID(d.Ctx, 1)
+	assert.Error(t, err)
+}
+
+func Test_executionsDao_CreateByTx(t *testing.T) {
+	d := newExecutionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Executions)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("INSERT INTO .*").
+		WithArgs(d.GetAnyArgs(testData)...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	d.SQLMock.ExpectCommit()
+
+	_, err := d.IDao.(ExecutionsDao).CreateByTx(d.Ctx, d.DB, testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_executionsDao_DeleteByTx(t *testing.T) {
+	d := newExecutionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Executions)
+	expectedSQLForDeletion := "DELETE .*"
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec(expectedSQLForDeletion).
+		WithArgs(testData.ID).
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(ExecutionsDao).DeleteByTx(d.Ctx, d.DB, testData.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_executionsDao_UpdateByTx(t *testing.T) {
+	d := newExecutionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Executions)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs(testData.Side, testData.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(ExecutionsDao).UpdateByTx(d.Ctx, d.DB, testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_executionsDao_GetByTradeIDByTx(t *testing.T) {
+	d := newExecutionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Executions)
+
+	rows := sqlmock.NewRows([]string{"id", "trade_id", "side"}).
+		AddRow(testData.ID, 1, testData.Side)
+	d.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	records, err := d.IDao.(ExecutionsDao).GetByTradeIDByTx(d.Ctx, d.DB, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, records, 1)
+}
+
+func Test_executionsDao_DeleteByTradeIDByTx(t *testing.T) {
+	d := newExecutionsDao()
+	defer d.Close()
+
+	d.SQLMock.ExpectQuery("SELECT `id` FROM `executions` .*").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("DELETE .*").
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(ExecutionsDao).DeleteByTradeIDByTx(d.Ctx, d.DB, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// no executions
+	d.SQLMock.ExpectQuery("SELECT `id` FROM `executions` .*").
+		WithArgs(6).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	err = d.IDao.(ExecutionsDao).DeleteByTradeIDByTx(d.Ctx, d.DB, 6)
+	assert.NoError(t, err)
+
+	err = d.SQLMock.ExpectationsWereMet()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
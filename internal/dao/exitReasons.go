@@ -0,0 +1,229 @@
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+
+	"github.com/go-dev-frame/sponge/pkg/logger"
+	"github.com/go-dev-frame/sponge/pkg/sgorm/query"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+
+	"helmsman/internal/cache"
+	"helmsman/internal/database"
+	"helmsman/internal/model"
+)
+
+var _ ExitReasonsDao = (*exitReasonsDao)(nil)
+
+// ExitReasonsDao defining the dao interface
+type ExitReasonsDao interface {
+	Create(ctx context.Context, table *model.ExitReasons) error
+	DeleteByID(ctx context.Context, id uint64) error
+	UpdateByID(ctx context.Context, table *model.ExitReasons) error
+	GetByID(ctx context.Context, id uint64) (*model.ExitReasons, error)
+	GetByColumns(ctx context.Context, params *query.Params) ([]*model.ExitReasons, int64, error)
+	GetAll(ctx context.Context, uid uint64) ([]*model.ExitReasons, error)
+
+	CreateByTx(ctx context.Context, tx *gorm.DB, table *model.ExitReasons) (uint64, error)
+	DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64) error
+	UpdateByTx(ctx context.Context, tx *gorm.DB, table *model.ExitReasons) error
+}
+
+type exitReasonsDao struct {
+	db    *gorm.DB
+	cache cache.ExitReasonsCache // if nil, the cache is not used.
+	sfg   *singleflight.Group    // if cache is nil, the sfg is not used.
+}
+
+// NewExitReasonsDao creating the dao interface
+func NewExitReasonsDao(db *gorm.DB, xCache cache.ExitReasonsCache) ExitReasonsDao {
+	if xCache == nil {
+		return &exitReasonsDao{db: db}
+	}
+	return &exitReasonsDao{
+		db:    db,
+		cache: xCache,
+		sfg:   new(singleflight.Group),
+	}
+}
+
+func (d *exitReasonsDao) deleteCache(ctx context.Context, id uint64) error {
+	if d.cache != nil {
+		return d.cache.Del(ctx, id)
+	}
+	return nil
+}
+
+// Create a new exitReasons, insert the record and the id value is written back to the table
+func (d *exitReasonsDao) Create(ctx context.Context, table *model.ExitReasons) error {
+	return d.db.WithContext(ctx).Create(table).Error
+}
+
+// DeleteByID delete a exitReasons by id
+func (d *exitReasonsDao) DeleteByID(ctx context.Context, id uint64) error {
+	err := d.db.WithContext(ctx).Where("id = ?", id).Delete(&model.ExitReasons{}).Error
+	if err != nil {
+		return err
+	}
+
+	// delete cache
+	_ = d.deleteCache(ctx, id)
+
+	return nil
+}
+
+// UpdateByID update a exitReasons by id, support partial update
+func (d *exitReasonsDao) UpdateByID(ctx context.Context, table *model.ExitReasons) error {
+	err := d.updateDataByID(ctx, d.db, table)
+
+	// delete cache
+	_ = d.deleteCache(ctx, table.ID)
+
+	return err
+}
+
+func (d *exitReasonsDao) updateDataByID(ctx context.Context, db *gorm.DB, table *model.ExitReasons) error {
+	if table.ID < 1 {
+		return errors.New("id cannot be 0")
+	}
+
+	update := map[string]interface{}{}
+
+	if table.UserID != 0 {
+		update["user_id"] = table.UserID
+	}
+	if table.Code != "" {
+		update["code"] = table.Code
+	}
+	if table.Name != "" {
+		update["name"] = table.Name
+	}
+
+	return db.WithContext(ctx).Model(table).Updates(update).Error
+}
+
+// GetByID get a exitReasons by id
+func (d *exitReasonsDao) GetByID(ctx context.Context, id uint64) (*model.ExitReasons, error) {
+	// no cache
+	if d.cache == nil {
+		record := &model.ExitReasons{}
+		err := d.db.WithContext(ctx).Where("id = ?", id).First(record).Error
+		return record, err
+	}
+
+	// get from cache
+	record, err := d.cache.Get(ctx, id)
+	if err == nil {
+		return record, nil
+	}
+
+	// get from database
+	if errors.Is(err, database.ErrCacheNotFound) {
+		// for the same id, prevent high concurrent simultaneous access to database
+		val, err, _ := d.sfg.Do(utils.Uint64ToStr(id), func() (interface{}, error) { //nolint
+			table := &model.ExitReasons{}
+			err = d.db.WithContext(ctx).Where("id = ?", id).First(table).Error
+			if err != nil {
+				if errors.Is(err, database.ErrRecordNotFound) {
+					// set placeholder cache to prevent cache penetration, default expiration time 10 minutes
+					if err = d.cache.SetPlaceholder(ctx, id); err != nil {
+						logger.Warn("cache.SetPlaceholder error", logger.Err(err), logger.Any("id", id))
+					}
+					return nil, database.ErrRecordNotFound
+				}
+				return nil, err
+			}
+			// set cache
+			if err = d.cache.Set(ctx, id, table, cache.ExitReasonsExpireTime); err != nil {
+				logger.Warn("cache.Set error", logger.Err(err), logger.Any("id", id))
+			}
+			return table, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		table, ok := val.(*model.ExitReasons)
+		if !ok {
+			return nil, database.ErrRecordNotFound
+		}
+		return table, nil
+	}
+
+	if d.cache.IsPlaceholderErr(err) {
+		return nil, database.ErrRecordNotFound
+	}
+
+	return nil, err
+}
+
+// GetByColumns get a paginated list of exitReasonss by custom conditions.
+// For more details, please refer to https://go-sponge.com/component/data/custom-page-query.html
+func (d *exitReasonsDao) GetByColumns(ctx context.Context, params *query.Params) ([]*model.ExitReasons, int64, error) {
+	queryStr, args, err := params.ConvertToGormConditions(query.WithWhitelistNames(model.ExitReasonsColumnNames))
+	if err != nil {
+		return nil, 0, errors.New("query params error: " + err.Error())
+	}
+
+	var total int64
+	if params.Sort != "ignore count" { // determine if count is required
+		err = d.db.WithContext(ctx).Model(&model.ExitReasons{}).Where(queryStr, args...).Count(&total).Error
+		if err != nil {
+			return nil, 0, err
+		}
+		if total == 0 {
+			return nil, total, nil
+		}
+	}
+
+	records := []*model.ExitReasons{}
+	order, limit, offset := params.ConvertToPage()
+	err = d.db.WithContext(ctx).Order(order).Limit(limit).Offset(offset).Where(queryStr, args...).Find(&records).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return records, total, err
+}
+
+// CreateByTx create a record in the database using the provided transaction
+func (d *exitReasonsDao) CreateByTx(ctx context.Context, tx *gorm.DB, table *model.ExitReasons) (uint64, error) {
+	err := tx.WithContext(ctx).Create(table).Error
+	return table.ID, err
+}
+
+// DeleteByTx delete a record by id in the database using the provided transaction
+func (d *exitReasonsDao) DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64) error {
+	err := tx.WithContext(ctx).Where("id = ?", id).Delete(&model.ExitReasons{}).Error
+	if err != nil {
+		return err
+	}
+
+	// delete cache
+	_ = d.deleteCache(ctx, id)
+
+	return nil
+}
+
+// UpdateByTx update a record by id in the database using the provided transaction
+func (d *exitReasonsDao) UpdateByTx(ctx context.Context, tx *gorm.DB, table *model.ExitReasons) error {
+	err := d.updateDataByID(ctx, tx, table)
+
+	// delete cache
+	_ = d.deleteCache(ctx, table.ID)
+
+	return err
+}
+
+// GetAll get the exit reasons of a user together with the default exit reasons shared by all users
+func (d *exitReasonsDao) GetAll(ctx context.Context, uid uint64) ([]*model.ExitReasons, error) {
+	var records []*model.ExitReasons
+	err := d.db.WithContext(ctx).Order("user_id asc").Order("id asc").
+		Where("user_id IN ?", []uint64{model.ExitReasonsDefaultUserID, uid}).Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,259 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-dev-frame/sponge/pkg/gotest"
+	"github.com/go-dev-frame/sponge/pkg/sgorm/query"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/cache"
+	"helmsman/internal/database"
+	"helmsman/internal/model"
+)
+
+func newExitReasonsDao() *gotest.Dao {
+	testData := &model.ExitReasons{}
+	testData.ID = 1
+	testData.Name = "News exit"
+	// you can set the other fields of testData here, such as:
+	//testData.CreatedAt = time.Now()
+	//testData.UpdatedAt = testData.CreatedAt
+
+	// init mock cache
+	//c := gotest.NewCache(map[string]interface{}{"no cache": testData}) // to test mysql, disable caching
+	c := gotest.NewCache(map[string]interface{}{utils.Uint64ToStr(testData.ID): testData})
+	c.ICache = cache.NewExitReasonsCache(&database.CacheType{
+		CType: "redis",
+		Rdb:   c.RedisClient,
+	})
+
+	// init mock dao
+	d := gotest.NewDao(c, testData)
+	d.IDao = NewExitReasonsDao(d.DB, c.ICache.(cache.ExitReasonsCache))
+
+	return d
+}
+
+func Test_exitReasonsDao_Create(t *testing.T) {
+	d := newExitReasonsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.ExitReasons)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("INSERT INTO .*").
+		WithArgs(d.GetAnyArgs(testData)...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(ExitReasonsDao).Create(d.Ctx, testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_exitReasonsDao_DeleteByID(t *testing.T) {
+	d := newExitReasonsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.ExitReasons)
+	expectedSQLForDeletion := "DELETE .*"
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec(expectedSQLForDeletion).
+		WithArgs(testData.ID).
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(ExitReasonsDao).DeleteByID(d.Ctx, testData.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// zero id error
+	err = d.IDao.(ExitReasonsDao).DeleteByID(d.Ctx, 0)
+	assert.Error(t, err)
+}
+
+func Test_exitReasonsDao_UpdateByID(t *testing.T) {
+	d := newExitReasonsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.ExitReasons)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs(testData.Name, testData.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(ExitReasonsDao).UpdateByID(d.Ctx, testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// zero id error
+	err = d.IDao.(ExitReasonsDao).UpdateByID(d.Ctx, &model.ExitReasons{})
+	assert.Error(t, err)
+
+}
+
+func Test_exitReasonsDao_GetByID(t *testing.T) {
+	d := newExitReasonsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.ExitReasons)
+
+	// column names and corresponding data
+	rows := sqlmock.NewRows([]string{"id"}).
+		AddRow(testData.ID)
+
+	d.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(testData.ID, 1).
+		WillReturnRows(rows)
+
+	_, err := d.IDao.(ExitReasonsDao).GetByID(d.Ctx, testData.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.SQLMock.ExpectationsWereMet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error test
+	d.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(2).
+		WillReturnRows(rows)
+	_, err = d.IDao.(ExitReasonsDao).GetByID(d.Ctx, 2)
+	assert.Error(t, err)
+
+	d.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(3, 4).
+		WillReturnRows(rows)
+	_, err = d.IDao.(ExitReasonsDao).GetByID(d.Ctx, 4)
+	assert.Error(t, err)
+}
+
+func Test_exitReasonsDao_GetByColumns(t *testing.T) {
+	d := newExitReasonsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.ExitReasons)
+
+	// column names and corresponding data
+	rows := sqlmock.NewRows([]string{"id"}).
+		AddRow(testData.ID)
+
+	d.SQLMock.ExpectQuery("SELECT .*").WillReturnRows(rows)
+
+	_, _, err := d.IDao.(ExitReasonsDao).GetByColumns(d.Ctx, &query.Params{
+		Page:  0,
+		Limit: 10,
+		Sort:  "ignore count", // ignore test count(*)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.SQLMock.ExpectationsWereMet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// err test
+	_, _, err = d.IDao.(ExitReasonsDao).GetByColumns(d.Ctx, &query.Params{
+		Page:  0,
+		Limit: 10,
+		Columns: []query.Column{
+			{
+				Name:  "id",
+				Exp:   "<",
+				Value: 0,
+			},
+		},
+	})
+	assert.Error(t, err)
+
+	// error test
+	dao := &exitReasonsDao{}
+	_, _, err = dao.GetByColumns(context.Background(), &query.Params{Columns: []query.Column{{}}})
+	t.Log(err)
+}
+
+func Test_exitReasonsDao_CreateByTx(t *testing.T) {
+	d := newExitReasonsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.ExitReasons)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("INSERT INTO .*").
+		WithArgs(d.GetAnyArgs(testData)...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	d.SQLMock.ExpectCommit()
+
+	_, err := d.IDao.(ExitReasonsDao).CreateByTx(d.Ctx, d.DB, testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_exitReasonsDao_DeleteByTx(t *testing.T) {
+	d := newExitReasonsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.ExitReasons)
+	expectedSQLForDeletion := "DELETE .*"
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec(expectedSQLForDeletion).
+		WithArgs(testData.ID).
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(ExitReasonsDao).DeleteByTx(d.Ctx, d.DB, testData.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_exitReasonsDao_UpdateByTx(t *testing.T) {
+	d := newExitReasonsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.ExitReasons)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs(testData.Name, testData.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(ExitReasonsDao).UpdateByTx(d.Ctx, d.DB, testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_exitReasonsDao_GetAll(t *testing.T) {
+	d := newExitReasonsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.ExitReasons)
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "code"}).
+		AddRow(testData.ID, model.ExitReasonsDefaultUserID, model.ExitReasonStopHit).
+		AddRow(testData.ID+1, 7, "news_exit")
+
+	d.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(model.ExitReasonsDefaultUserID, 7).
+		WillReturnRows(rows)
+
+	records, err := d.IDao.(ExitReasonsDao).GetAll(d.Ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, records, 2)
+
+	err = d.SQLMock.ExpectationsWereMet()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
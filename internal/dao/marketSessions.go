@@ -0,0 +1,237 @@
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+
+	"github.com/go-dev-frame/sponge/pkg/logger"
+	"github.com/go-dev-frame/sponge/pkg/sgorm/query"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+
+	"helmsman/internal/cache"
+	"helmsman/internal/database"
+	"helmsman/internal/model"
+)
+
+var _ MarketSessionsDao = (*marketSessionsDao)(nil)
+
+// MarketSessionsDao defining the dao interface
+type MarketSessionsDao interface {
+	Create(ctx context.Context, table *model.MarketSessions) error
+	DeleteByID(ctx context.Context, id uint64) error
+	UpdateByID(ctx context.Context, table *model.MarketSessions) error
+	GetByID(ctx context.Context, id uint64) (*model.MarketSessions, error)
+	GetByColumns(ctx context.Context, params *query.Params) ([]*model.MarketSessions, int64, error)
+	GetByUserID(ctx context.Context, uid uint64) ([]*model.MarketSessions, error)
+
+	CreateByTx(ctx context.Context, tx *gorm.DB, table *model.MarketSessions) (uint64, error)
+	DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64) error
+	UpdateByTx(ctx context.Context, tx *gorm.DB, table *model.MarketSessions) error
+}
+
+type marketSessionsDao struct {
+	db    *gorm.DB
+	cache cache.MarketSessionsCache // if nil, the cache is not used.
+	sfg   *singleflight.Group       // if cache is nil, the sfg is not used.
+}
+
+// NewMarketSessionsDao creating the dao interface
+func NewMarketSessionsDao(db *gorm.DB, xCache cache.MarketSessionsCache) MarketSessionsDao {
+	if xCache == nil {
+		return &marketSessionsDao{db: db}
+	}
+	return &marketSessionsDao{
+		db:    db,
+		cache: xCache,
+		sfg:   new(singleflight.Group),
+	}
+}
+
+func (d *marketSessionsDao) deleteCache(ctx context.Context, id uint64) error {
+	if d.cache != nil {
+		return d.cache.Del(ctx, id)
+	}
+	return nil
+}
+
+// Create a new marketSessions, insert the record and the id value is written back to the table
+func (d *marketSessionsDao) Create(ctx context.Context, table *model.MarketSessions) error {
+	return d.db.WithContext(ctx).Create(table).Error
+}
+
+// DeleteByID delete a marketSessions by id
+func (d *marketSessionsDao) DeleteByID(ctx context.Context, id uint64) error {
+	err := d.db.WithContext(ctx).Where("id = ?", id).Delete(&model.MarketSessions{}).Error
+	if err != nil {
+		return err
+	}
+
+	// delete cache
+	_ = d.deleteCache(ctx, id)
+
+	return nil
+}
+
+// UpdateByID update a marketSessions by id, support partial update
+func (d *marketSessionsDao) UpdateByID(ctx context.Context, table *model.MarketSessions) error {
+	err := d.updateDataByID(ctx, d.db, table)
+
+	// delete cache
+	_ = d.deleteCache(ctx, table.ID)
+
+	return err
+}
+
+func (d *marketSessionsDao) updateDataByID(ctx context.Context, db *gorm.DB, table *model.MarketSessions) error {
+	if table.ID < 1 {
+		return errors.New("id cannot be 0")
+	}
+
+	update := map[string]interface{}{}
+
+	if table.UserID != 0 {
+		update["user_id"] = table.UserID
+	}
+	if table.Name != "" {
+		update["name"] = table.Name
+	}
+	if table.Timezone != "" {
+		update["timezone"] = table.Timezone
+	}
+	if table.OpenTime != "" {
+		update["open_time"] = table.OpenTime
+	}
+	if table.CloseTime != "" {
+		update["close_time"] = table.CloseTime
+	}
+	if table.UpdatedAt != "" {
+		update["updated_at"] = table.UpdatedAt
+	}
+
+	return db.WithContext(ctx).Model(table).Updates(update).Error
+}
+
+// GetByID get a marketSessions by id
+func (d *marketSessionsDao) GetByID(ctx context.Context, id uint64) (*model.MarketSessions, error) {
+	// no cache
+	if d.cache == nil {
+		record := &model.MarketSessions{}
+		err := d.db.WithContext(ctx).Where("id = ?", id).First(record).Error
+		return record, err
+	}
+
+	// get from cache
+	record, err := d.cache.Get(ctx, id)
+	if err == nil {
+		return record, nil
+	}
+
+	// get from database
+	if errors.Is(err, database.ErrCacheNotFound) {
+		// for the same id, prevent high concurrent simultaneous access to database
+		val, err, _ := d.sfg.Do(utils.Uint64ToStr(id), func() (interface{}, error) { //nolint
+			table := &model.MarketSessions{}
+			err = d.db.WithContext(ctx).Where("id = ?", id).First(table).Error
+			if err != nil {
+				if errors.Is(err, database.ErrRecordNotFound) {
+					// set placeholder cache to prevent cache penetration, default expiration time 10 minutes
+					if err = d.cache.SetPlaceholder(ctx, id); err != nil {
+						logger.Warn("cache.SetPlaceholder error", logger.Err(err), logger.Any("id", id))
+					}
+					return nil, database.ErrRecordNotFound
+				}
+				return nil, err
+			}
+			// set cache
+			if err = d.cache.Set(ctx, id, table, cache.MarketSessionsExpireTime); err != nil {
+				logger.Warn("cache.Set error", logger.Err(err), logger.Any("id", id))
+			}
+			return table, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		table, ok := val.(*model.MarketSessions)
+		if !ok {
+			return nil, database.ErrRecordNotFound
+		}
+		return table, nil
+	}
+
+	if d.cache.IsPlaceholderErr(err) {
+		return nil, database.ErrRecordNotFound
+	}
+
+	return nil, err
+}
+
+// GetByColumns get a paginated list of marketSessionss by custom conditions.
+// For more details, please refer to https://go-sponge.com/component/data/custom-page-query.html
+func (d *marketSessionsDao) GetByColumns(ctx context.Context, params *query.Params) ([]*model.MarketSessions, int64, error) {
+	queryStr, args, err := params.ConvertToGormConditions(query.WithWhitelistNames(model.MarketSessionsColumnNames))
+	if err != nil {
+		return nil, 0, errors.New("query params error: " + err.Error())
+	}
+
+	var total int64
+	if params.Sort != "ignore count" { // determine if count is required
+		err = d.db.WithContext(ctx).Model(&model.MarketSessions{}).Where(queryStr, args...).Count(&total).Error
+		if err != nil {
+			return nil, 0, err
+		}
+		if total == 0 {
+			return nil, total, nil
+		}
+	}
+
+	records := []*model.MarketSessions{}
+	order, limit, offset := params.ConvertToPage()
+	err = d.db.WithContext(ctx).Order(order).Limit(limit).Offset(offset).Where(queryStr, args...).Find(&records).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return records, total, err
+}
+
+// CreateByTx create a record in the database using the provided transaction
+func (d *marketSessionsDao) CreateByTx(ctx context.Context, tx *gorm.DB, table *model.MarketSessions) (uint64, error) {
+	err := tx.WithContext(ctx).Create(table).Error
+	return table.ID, err
+}
+
+// DeleteByTx delete a record by id in the database using the provided transaction
+func (d *marketSessionsDao) DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64) error {
+	err := tx.WithContext(ctx).Where("id = ?", id).Delete(&model.MarketSessions{}).Error
+	if err != nil {
+		return err
+	}
+
+	// delete cache
+	_ = d.deleteCache(ctx, id)
+
+	return nil
+}
+
+// UpdateByTx update a record by id in the database using the provided transaction
+func (d *marketSessionsDao) UpdateByTx(ctx context.Context, tx *gorm.DB, table *model.MarketSessions) error {
+	err := d.updateDataByID(ctx, tx, table)
+
+	// delete cache
+	_ = d.deleteCache(ctx, table.ID)
+
+	return err
+}
+
+// GetByUserID get the market sessions of a user in the order they were created
+func (d *marketSessionsDao) GetByUserID(ctx context.Context, uid uint64) ([]*model.MarketSessions, error) {
+	var records []*model.MarketSessions
+	err := d.db.WithContext(ctx).Where("user_id = ?", uid).Order("id asc").Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,259 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-dev-frame/sponge/pkg/gotest"
+	"github.com/go-dev-frame/sponge/pkg/sgorm/query"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/cache"
+	"helmsman/internal/database"
+	"helmsman/internal/model"
+)
+
+func newMarketSessionsDao() *gotest.Dao {
+	testData := &model.MarketSessions{}
+	testData.ID = 1
+	testData.Name = "London"
+	// you can set the other fields of testData here, such as:
+	//testData.CreatedAt = time.Now()
+	//testData.UpdatedAt = testData.CreatedAt
+
+	// init mock cache
+	//c := gotest.NewCache(map[string]interface{}{"no cache": testData}) // to test mysql, disable caching
+	c := gotest.NewCache(map[string]interface{}{utils.Uint64ToStr(testData.ID): testData})
+	c.ICache = cache.NewMarketSessionsCache(&database.CacheType{
+		CType: "redis",
+		Rdb:   c.RedisClient,
+	})
+
+	// init mock dao
+	d := gotest.NewDao(c, testData)
+	d.IDao = NewMarketSessionsDao(d.DB, c.ICache.(cache.MarketSessionsCache))
+
+	return d
+}
+
+func Test_marketSessionsDao_Create(t *testing.T) {
+	d := newMarketSessionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.MarketSessions)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("INSERT INTO .*").
+		WithArgs(d.GetAnyArgs(testData)...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(MarketSessionsDao).Create(d.Ctx, testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_marketSessionsDao_DeleteByID(t *testing.T) {
+	d := newMarketSessionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.MarketSessions)
+	expectedSQLForDeletion := "DELETE .*"
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec(expectedSQLForDeletion).
+		WithArgs(testData.ID).
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(MarketSessionsDao).DeleteByID(d.Ctx, testData.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// zero id error
+	err = d.IDao.(MarketSessionsDao).DeleteByID(d.Ctx, 0)
+	assert.Error(t, err)
+}
+
+func Test_marketSessionsDao_UpdateByID(t *testing.T) {
+	d := newMarketSessionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.MarketSessions)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs(testData.Name, testData.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(MarketSessionsDao).UpdateByID(d.Ctx, testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// zero id error
+	err = d.IDao.(MarketSessionsDao).UpdateByID(d.Ctx, &model.MarketSessions{})
+	assert.Error(t, err)
+
+}
+
+func Test_marketSessionsDao_GetByID(t *testing.T) {
+	d := newMarketSessionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.MarketSessions)
+
+	// column names and corresponding data
+	rows := sqlmock.NewRows([]string{"id"}).
+		AddRow(testData.ID)
+
+	d.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(testData.ID, 1).
+		WillReturnRows(rows)
+
+	_, err := d.IDao.(MarketSessionsDao).GetByID(d.Ctx, testData.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.SQLMock.ExpectationsWereMet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error test
+	d.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(2).
+		WillReturnRows(rows)
+	_, err = d.IDao.(MarketSessionsDao).GetByID(d.Ctx, 2)
+	assert.Error(t, err)
+
+	d.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(3, 4).
+		WillReturnRows(rows)
+	_, err = d.IDao.(MarketSessionsDao).GetByID(d.Ctx, 4)
+	assert.Error(t, err)
+}
+
+func Test_marketSessionsDao_GetByColumns(t *testing.T) {
+	d := newMarketSessionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.MarketSessions)
+
+	// column names and corresponding data
+	rows := sqlmock.NewRows([]string{"id"}).
+		AddRow(testData.ID)
+
+	d.SQLMock.ExpectQuery("SELECT .*").WillReturnRows(rows)
+
+	_, _, err := d.IDao.(MarketSessionsDao).GetByColumns(d.Ctx, &query.Params{
+		Page:  0,
+		Limit: 10,
+		Sort:  "ignore count", // ignore test count(*)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = d.SQLMock.ExpectationsWereMet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// err test
+	_, _, err = d.IDao.(MarketSessionsDao).GetByColumns(d.Ctx, &query.Params{
+		Page:  0,
+		Limit: 10,
+		Columns: []query.Column{
+			{
+				Name:  "id",
+				Exp:   "<",
+				Value: 0,
+			},
+		},
+	})
+	assert.Error(t, err)
+
+	// error test
+	dao := &marketSessionsDao{}
+	_, _, err = dao.GetByColumns(context.Background(), &query.Params{Columns: []query.Column{{}}})
+	t.Log(err)
+}
+
+func Test_marketSessionsDao_CreateByTx(t *testing.T) {
+	d := newMarketSessionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.MarketSessions)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("INSERT INTO .*").
+		WithArgs(d.GetAnyArgs(testData)...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	d.SQLMock.ExpectCommit()
+
+	_, err := d.IDao.(MarketSessionsDao).CreateByTx(d.Ctx, d.DB, testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_marketSessionsDao_DeleteByTx(t *testing.T) {
+	d := newMarketSessionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.MarketSessions)
+	expectedSQLForDeletion := "DELETE .*"
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec(expectedSQLForDeletion).
+		WithArgs(testData.ID).
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(MarketSessionsDao).DeleteByTx(d.Ctx, d.DB, testData.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_marketSessionsDao_UpdateByTx(t *testing.T) {
+	d := newMarketSessionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.MarketSessions)
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs(testData.Name, testData.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(MarketSessionsDao).UpdateByTx(d.Ctx, d.DB, testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_marketSessionsDao_GetByUserID(t *testing.T) {
+	d := newMarketSessionsDao()
+	defer d.Close()
+	testData := d.TestData.(*model.MarketSessions)
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "name"}).
+		AddRow(testData.ID, 7, testData.Name).
+		AddRow(testData.ID+1, 7, "New York")
+
+	d.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(7).
+		WillReturnRows(rows)
+
+	records, err := d.IDao.(MarketSessionsDao).GetByUserID(d.Ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, records, 2)
+
+	err = d.SQLMock.ExpectationsWereMet()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
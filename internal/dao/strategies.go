@@ -26,6 +26,7 @@ type StrategiesDao interface {
 	GetByID(ctx context.Context, id uint64) (*model.Strategies, error)
 	GetByColumns(ctx context.Context, params *query.Params) ([]*model.Strategies, int64, error)
 	GetAll(ctx context.Context) ([]*model.Strategies, error)
+	GetAllByUserID(ctx context.Context, uid int) ([]*model.Strategies, error)
 
 	CreateByTx(ctx context.Context, tx *gorm.DB, table *model.Strategies) (uint64, error)
 	DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64) error
@@ -101,6 +102,9 @@ func (d *strategiesDao) updateDataByID(ctx context.Context, db *gorm.DB, table *
 	if table.Description != "" {
 		update["description"] = table.Description
 	}
+	if table.MinRewardRisk != 0 {
+		update["min_reward_risk"] = table.MinRewardRisk
+	}
 
 	return db.WithContext(ctx).Model(table).Updates(update).Error
 }
@@ -225,3 +229,12 @@ func (d *strategiesDao) GetAll(ctx context.Context) ([]*model.Strategies, error)
 	}
 	return records, nil
 }
+
+func (d *strategiesDao) GetAllByUserID(ctx context.Context, uid int) ([]*model.Strategies, error) {
+	var records []*model.Strategies
+	err := d.db.WithContext(ctx).Order("id asc").Where("user_id = ?", uid).Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
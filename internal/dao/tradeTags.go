@@ -26,6 +26,7 @@ type TradeTagsDao interface {
 	GetByTradeID(ctx context.Context, tradeID int) (*model.TradeTags, error)
 	GetByColumns(ctx context.Context, params *query.Params) ([]*model.TradeTags, int64, error)
 	GetAll(ctx context.Context) ([]*model.TradeTags, error)
+	GetByTagIDs(ctx context.Context, tagIDs []int) ([]*model.TradeTags, error)
 
 	CreateByTx(ctx context.Context, tx *gorm.DB, table *model.TradeTags) (int, error)
 	DeleteByTx(ctx context.Context, tx *gorm.DB, tradeID int) error
@@ -226,3 +227,16 @@ func (d *tradeTagsDao) GetAll(ctx context.Context) ([]*model.TradeTags, error) {
 	}
 	return records, nil
 }
+
+// GetByTagIDs get all tradeTags of the tags
+func (d *tradeTagsDao) GetByTagIDs(ctx context.Context, tagIDs []int) ([]*model.TradeTags, error) {
+	records := []*model.TradeTags{}
+	if len(tagIDs) == 0 {
+		return records, nil
+	}
+	err := d.db.WithContext(ctx).Where("tag_id IN (?)", tagIDs).Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -23,12 +23,16 @@ type TradesDao interface {
 	Create(ctx context.Context, table *model.Trades) error
 	DeleteByID(ctx context.Context, id uint64) error
 	UpdateByID(ctx context.Context, table *model.Trades) error
+	UpdateStatus(ctx context.Context, id uint64, fromStatus string, columns map[string]interface{}) error
 	GetByID(ctx context.Context, id uint64) (*model.Trades, error)
 	GetByColumns(ctx context.Context, params *query.Params) ([]*model.Trades, int64, error)
+	GetClosed(ctx context.Context, condition *ClosedTradesCondition) ([]*model.Trades, error)
 
 	CreateByTx(ctx context.Context, tx *gorm.DB, table *model.Trades) (uint64, error)
 	DeleteByTx(ctx context.Context, tx *gorm.DB, id uint64) error
 	UpdateByTx(ctx context.Context, tx *gorm.DB, table *model.Trades) error
+	UpdateStatusByTx(ctx context.Context, tx *gorm.DB, id uint64, fromStatus string, columns map[string]interface{}) error
+	RenameExitReasonByTx(ctx context.Context, tx *gorm.DB, userID int, fromCode string, toCode string) error
 }
 
 type tradesDao struct {
@@ -136,6 +140,12 @@ func (d *tradesDao) updateDataByID(ctx context.Context, db *gorm.DB, table *mode
 	if table.ActualExitPrice != 0 {
 		update["actual_exit_price"] = table.ActualExitPrice
 	}
+	if table.MaePrice != 0 {
+		update["mae_price"] = table.MaePrice
+	}
+	if table.MfePrice != 0 {
+		update["mfe_price"] = table.MfePrice
+	}
 	if table.Commission != 0 {
 		update["commission"] = table.Commission
 	}
@@ -158,6 +168,28 @@ func (d *tradesDao) updateDataByID(ctx context.Context, db *gorm.DB, table *mode
 	return db.WithContext(ctx).Model(table).Updates(update).Error
 }
 
+// UpdateStatus update the columns of a trades only if its status is still fromStatus, so that
+// concurrent transitions cannot both succeed. Zero values in columns are written, which clears them.
+// database.ErrRecordNotFound is returned if no record has the id and status.
+func (d *tradesDao) UpdateStatus(ctx context.Context, id uint64, fromStatus string, columns map[string]interface{}) error {
+	return d.updateStatus(ctx, d.db, id, fromStatus, columns)
+}
+
+func (d *tradesDao) updateStatus(ctx context.Context, db *gorm.DB, id uint64, fromStatus string, columns map[string]interface{}) error {
+	result := db.WithContext(ctx).Model(&model.Trades{}).Where("id = ? AND status = ?", id, fromStatus).Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// delete cache
+	_ = d.deleteCache(ctx, id)
+
+	if result.RowsAffected == 0 {
+		return database.ErrRecordNotFound
+	}
+	return nil
+}
+
 // GetByID get a trades by id
 func (d *tradesDao) GetByID(ctx context.Context, id uint64) (*model.Trades, error) {
 	// no cache
@@ -241,6 +273,45 @@ func (d *tradesDao) GetByColumns(ctx context.Context, params *query.Params) ([]*
 	return records, total, err
 }
 
+// ClosedTradesCondition conditions for loading closed trades, zero value fields are ignored
+type ClosedTradesCondition struct {
+	AccountID  uint64
+	UserID     int // trades of all accounts owned by the user
+	StrategyID int
+	Columns    []query.Column // custom conditions in the same format as GetByColumns
+}
+
+// GetClosed get all closed trades matching the condition, ordered by exit time
+func (d *tradesDao) GetClosed(ctx context.Context, condition *ClosedTradesCondition) ([]*model.Trades, error) {
+	db := d.db.WithContext(ctx).Where("status = ?", model.TradesStatusClosed)
+	if condition.AccountID != 0 {
+		db = db.Where("account_id = ?", condition.AccountID)
+	}
+	if condition.UserID != 0 {
+		db = db.Where("account_id IN (?)", d.db.Model(&model.Accounts{}).Select("id").Where("user_id = ?", condition.UserID))
+	}
+	if condition.StrategyID != 0 {
+		db = db.Where("strategy_id = ?", condition.StrategyID)
+	}
+	if len(condition.Columns) > 0 {
+		params := &query.Params{Columns: condition.Columns}
+		queryStr, args, err := params.ConvertToGormConditions(query.WithWhitelistNames(model.TradesColumnNames))
+		if err != nil {
+			return nil, errors.New("query params error: " + err.Error())
+		}
+		if queryStr != "" {
+			db = db.Where("("+queryStr+")", args...)
+		}
+	}
+
+	records := []*model.Trades{}
+	err := db.Order("actual_exit_time asc").Order("id asc").Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
 // CreateByTx create a record in the database using the provided transaction
 func (d *tradesDao) CreateByTx(ctx context.Context, tx *gorm.DB, table *model.Trades) (uint64, error) {
 	err := tx.WithContext(ctx).Create(table).Error
@@ -269,3 +340,37 @@ func (d *tradesDao) UpdateByTx(ctx context.Context, tx *gorm.DB, table *model.Tr
 
 	return err
 }
+
+// UpdateStatusByTx update the columns of a trades only if its status is still fromStatus using the
+// provided transaction, like UpdateStatus
+func (d *tradesDao) UpdateStatusByTx(ctx context.Context, tx *gorm.DB, id uint64, fromStatus string, columns map[string]interface{}) error {
+	return d.updateStatus(ctx, tx, id, fromStatus, columns)
+}
+
+// RenameExitReasonByTx change the exit reason of the trades of the accounts of a user from one code
+// to another using the provided transaction
+func (d *tradesDao) RenameExitReasonByTx(ctx context.Context, tx *gorm.DB, userID int, fromCode string, toCode string) error {
+	var ids []uint64
+	err := tx.WithContext(ctx).Model(&model.Trades{}).
+		Where("exit_reason = ?", fromCode).
+		Where("account_id IN (?)", tx.Model(&model.Accounts{}).Select("id").Where("user_id = ?", userID)).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err = tx.WithContext(ctx).Model(&model.Trades{}).Where("id IN ?", ids).Update("exit_reason", toCode).Error
+	if err != nil {
+		return err
+	}
+
+	// delete cache
+	for _, id := range ids {
+		_ = d.deleteCache(ctx, id)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package dao
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -180,6 +181,109 @@ func Test_tradesDao_GetByColumns(t *testing.T) {
 	t.Log(err)
 }
 
+func Test_tradesDao_UpdateStatus(t *testing.T) {
+	d := newTradesDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Trades)
+	columns := map[string]interface{}{"status": model.TradesStatusCancelled, "cancelled_at": "2024-01-01 10:00:00"}
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs("2024-01-01 10:00:00", model.TradesStatusCancelled, testData.ID, model.TradesStatusPlanned).
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(TradesDao).UpdateStatus(d.Ctx, testData.ID, model.TradesStatusPlanned, columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// status changed concurrently
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(0, 0))
+	d.SQLMock.ExpectCommit()
+	err = d.IDao.(TradesDao).UpdateStatus(d.Ctx, testData.ID, model.TradesStatusPlanned, columns)
+	assert.ErrorIs(t, err, database.ErrRecordNotFound)
+
+	// update error test
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").WillReturnError(errors.New("mock error"))
+	d.SQLMock.ExpectRollback()
+	err = d.IDao.(TradesDao).UpdateStatus(d.Ctx, testData.ID, model.TradesStatusPlanned, columns)
+	assert.Error(t, err)
+}
+
+func Test_tradesDao_UpdateStatusByTx(t *testing.T) {
+	d := newTradesDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Trades)
+	columns := map[string]interface{}{"status": model.TradesStatusActive, "position_size": 2}
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs(2, model.TradesStatusActive, testData.ID, model.TradesStatusPlanned).
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(TradesDao).UpdateStatusByTx(d.Ctx, d.DB, testData.ID, model.TradesStatusPlanned, columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// status changed concurrently
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(0, 0))
+	d.SQLMock.ExpectCommit()
+	err = d.IDao.(TradesDao).UpdateStatusByTx(d.Ctx, d.DB, testData.ID, model.TradesStatusPlanned, columns)
+	assert.ErrorIs(t, err, database.ErrRecordNotFound)
+}
+
+func Test_tradesDao_GetClosed(t *testing.T) {
+	d := newTradesDao()
+	defer d.Close()
+	testData := d.TestData.(*model.Trades)
+
+	// column names and corresponding data
+	rows := sqlmock.NewRows([]string{"id", "status"}).
+		AddRow(testData.ID, model.TradesStatusClosed)
+
+	d.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(model.TradesStatusClosed, 1, 2).
+		WillReturnRows(rows)
+
+	records, err := d.IDao.(TradesDao).GetClosed(d.Ctx, &ClosedTradesCondition{AccountID: 1, StrategyID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, records, 1)
+
+	err = d.SQLMock.ExpectationsWereMet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// error test
+	d.SQLMock.ExpectQuery("SELECT .*").WithArgs(model.TradesStatusClosed, 3).WillReturnError(errors.New("mock error"))
+	_, err = d.IDao.(TradesDao).GetClosed(d.Ctx, &ClosedTradesCondition{UserID: 3})
+	assert.Error(t, err)
+
+	// custom conditions
+	d.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(model.TradesStatusClosed, "EURUSD").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(testData.ID))
+	records, err = d.IDao.(TradesDao).GetClosed(d.Ctx, &ClosedTradesCondition{
+		Columns: []query.Column{{Name: "symbol", Value: "EURUSD"}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+
+	// column not allowed
+	_, err = d.IDao.(TradesDao).GetClosed(d.Ctx, &ClosedTradesCondition{
+		Columns: []query.Column{{Name: "unknown", Value: 1}},
+	})
+	assert.Error(t, err)
+}
+
 func Test_tradesDao_CreateByTx(t *testing.T) {
 	d := newTradesDao()
 	defer d.Close()
@@ -231,3 +335,34 @@ func Test_tradesDao_UpdateByTx(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func Test_tradesDao_RenameExitReasonByTx(t *testing.T) {
+	d := newTradesDao()
+	defer d.Close()
+
+	d.SQLMock.ExpectQuery("SELECT `id` FROM `trades` .*").
+		WithArgs("news", 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(3))
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs("news_exit", 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	d.SQLMock.ExpectCommit()
+
+	err := d.IDao.(TradesDao).RenameExitReasonByTx(d.Ctx, d.DB, 7, "news", "news_exit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// no trades with the exit reason
+	d.SQLMock.ExpectQuery("SELECT `id` FROM `trades` .*").
+		WithArgs("other", 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	err = d.IDao.(TradesDao).RenameExitReasonByTx(d.Ctx, d.DB, 7, "other", "others")
+	assert.NoError(t, err)
+
+	err = d.SQLMock.ExpectationsWereMet()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
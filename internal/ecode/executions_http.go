@@ -0,0 +1,23 @@
+package ecode
+
+import (
+	"github.com/go-dev-frame/sponge/pkg/errcode"
+)
+
+// executions business-level http error codes.
+// the executionsNO value range is 1~999, if the same error code is used, it will cause panic.
+var (
+	executionsNO       = 82
+	executionsName     = "executions"
+	executionsBaseCode = errcode.HCode(executionsNO)
+
+	ErrCreateExecutions     = errcode.NewError(executionsBaseCode+1, "failed to create "+executionsName)
+	ErrDeleteByIDExecutions = errcode.NewError(executionsBaseCode+2, "failed to delete "+executionsName)
+	ErrUpdateByIDExecutions = errcode.NewError(executionsBaseCode+3, "failed to update "+executionsName)
+	ErrGetByIDExecutions    = errcode.NewError(executionsBaseCode+4, "failed to get "+executionsName+" details")
+	ErrListExecutions       = errcode.NewError(executionsBaseCode+5, "failed to list of "+executionsName)
+	ErrSideExecutions       = errcode.NewError(executionsBaseCode+6, "side must be buy or sell")
+	ErrOverfilledExecutions = errcode.NewError(executionsBaseCode+7, "an exit is larger than the open quantity of the trades")
+
+	// error codes are globally unique, adding 1 to the previous error code
+)
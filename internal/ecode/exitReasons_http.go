@@ -0,0 +1,23 @@
+package ecode
+
+import (
+	"github.com/go-dev-frame/sponge/pkg/errcode"
+)
+
+// exitReasons business-level http error codes.
+// the exitReasonsNO value range is 1~999, if the same error code is used, it will cause panic.
+var (
+	exitReasonsNO       = 81
+	exitReasonsName     = "exitReasons"
+	exitReasonsBaseCode = errcode.HCode(exitReasonsNO)
+
+	ErrCreateExitReasons     = errcode.NewError(exitReasonsBaseCode+1, "failed to create "+exitReasonsName)
+	ErrDeleteByIDExitReasons = errcode.NewError(exitReasonsBaseCode+2, "failed to delete "+exitReasonsName)
+	ErrUpdateByIDExitReasons = errcode.NewError(exitReasonsBaseCode+3, "failed to update "+exitReasonsName)
+	ErrGetByIDExitReasons    = errcode.NewError(exitReasonsBaseCode+4, "failed to get "+exitReasonsName+" details")
+	ErrListExitReasons       = errcode.NewError(exitReasonsBaseCode+5, "failed to list of "+exitReasonsName)
+	ErrCodeExistsExitReasons = errcode.NewError(exitReasonsBaseCode+6, exitReasonsName+" code already exists")
+	ErrDefaultExitReasons    = errcode.NewError(exitReasonsBaseCode+7, "default "+exitReasonsName+" are shared by all users and cannot be changed")
+
+	// error codes are globally unique, adding 1 to the previous error code
+)
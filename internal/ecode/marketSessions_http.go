@@ -0,0 +1,22 @@
+package ecode
+
+import (
+	"github.com/go-dev-frame/sponge/pkg/errcode"
+)
+
+// marketSessions business-level http error codes.
+// the marketSessionsNO value range is 1~999, if the same error code is used, it will cause panic.
+var (
+	marketSessionsNO       = 83
+	marketSessionsName     = "marketSessions"
+	marketSessionsBaseCode = errcode.HCode(marketSessionsNO)
+
+	ErrCreateMarketSessions     = errcode.NewError(marketSessionsBaseCode+1, "failed to create "+marketSessionsName)
+	ErrDeleteByIDMarketSessions = errcode.NewError(marketSessionsBaseCode+2, "failed to delete "+marketSessionsName)
+	ErrUpdateByIDMarketSessions = errcode.NewError(marketSessionsBaseCode+3, "failed to update "+marketSessionsName)
+	ErrGetByIDMarketSessions    = errcode.NewError(marketSessionsBaseCode+4, "failed to get "+marketSessionsName+" details")
+	ErrListMarketSessions       = errcode.NewError(marketSessionsBaseCode+5, "failed to list of "+marketSessionsName)
+	ErrSessionMarketSessions    = errcode.NewError(marketSessionsBaseCode+6, "invalid session window, open and close must be HH:MM and timezone an IANA time zone")
+
+	// error codes are globally unique, adding 1 to the previous error code
+)
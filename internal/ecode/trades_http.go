@@ -11,11 +11,29 @@ var (
 	tradesName     = "trades"
 	tradesBaseCode = errcode.HCode(tradesNO)
 
-	ErrCreateTrades     = errcode.NewError(tradesBaseCode+1, "failed to create "+tradesName)
-	ErrDeleteByIDTrades = errcode.NewError(tradesBaseCode+2, "failed to delete "+tradesName)
-	ErrUpdateByIDTrades = errcode.NewError(tradesBaseCode+3, "failed to update "+tradesName)
-	ErrGetByIDTrades    = errcode.NewError(tradesBaseCode+4, "failed to get "+tradesName+" details")
-	ErrListTrades       = errcode.NewError(tradesBaseCode+5, "failed to list of "+tradesName)
+	ErrCreateTrades          = errcode.NewError(tradesBaseCode+1, "failed to create "+tradesName)
+	ErrDeleteByIDTrades      = errcode.NewError(tradesBaseCode+2, "failed to delete "+tradesName)
+	ErrUpdateByIDTrades      = errcode.NewError(tradesBaseCode+3, "failed to update "+tradesName)
+	ErrGetByIDTrades         = errcode.NewError(tradesBaseCode+4, "failed to get "+tradesName+" details")
+	ErrListTrades            = errcode.NewError(tradesBaseCode+5, "failed to list of "+tradesName)
+	ErrExitReasonTrades      = errcode.NewError(tradesBaseCode+6, "exit reason is not in the vocabulary of the user")
+	ErrDirectionTrades       = errcode.NewError(tradesBaseCode+7, "direction must be long or short")
+	ErrTransitionTrades      = errcode.NewError(tradesBaseCode+8, "the transition is not allowed in the current status of "+tradesName)
+	ErrEntryRequiredTrades   = errcode.NewError(tradesBaseCode+9, "actual entry price and time are required for an active or closed "+tradesName)
+	ErrExitRequiredTrades    = errcode.NewError(tradesBaseCode+10, "actual exit price and time are required for a closed "+tradesName)
+	ErrStatusTrades          = errcode.NewError(tradesBaseCode+11, "status can only be changed by the transition endpoints")
+	ErrPhaseTrades           = errcode.NewError(tradesBaseCode+12, "the fields cannot be set in the current status of "+tradesName)
+	ErrPnlOverrideTrades     = errcode.NewError(tradesBaseCode+13, "pnl and r_multiple are computed by the server, set overridePnl to provide broker-reported values")
+	ErrResultTrades          = errcode.NewError(tradesBaseCode+14, "pnl cannot be computed from the "+tradesName+" without its entry and exit, provide it with overridePnl")
+	ErrPositionSizeTrades    = errcode.NewError(tradesBaseCode+15, "position size cannot be computed from the risk settings of the account")
+	ErrPlanTrades            = errcode.NewError(tradesBaseCode+16, "the plan of the "+tradesName+" violates the plan rules, set draft to save it without the checks")
+	ErrStopSideTrades        = errcode.NewError(tradesBaseCode+17, "planned stop loss must be below the entry for a long and above it for a short")
+	ErrTakeProfitSideTrades  = errcode.NewError(tradesBaseCode+18, "planned take profit must be above the entry for a long and below it for a short")
+	ErrRewardRiskTrades      = errcode.NewError(tradesBaseCode+19, "planned reward:risk is below the minimum of the strategy")
+	ErrMaxPositionSizeTrades = errcode.NewError(tradesBaseCode+20, "position size exceeds the maximum of the account")
+	ErrPnlRequiredTrades     = errcode.NewError(tradesBaseCode+21, "pnl is required when overridePnl is set")
+	ErrAccountTrades         = errcode.NewError(tradesBaseCode+22, "account of the "+tradesName+" does not exist or belongs to another user")
+	ErrStrategyTrades        = errcode.NewError(tradesBaseCode+23, "strategy of the "+tradesName+" does not exist or belongs to another user")
 
 	// error codes are globally unique, adding 1 to the previous error code
 )
@@ -18,6 +18,7 @@ import (
 	"helmsman/internal/database"
 	"helmsman/internal/ecode"
 	"helmsman/internal/model"
+	"helmsman/internal/stats"
 	"helmsman/internal/types"
 )
 
@@ -30,10 +31,18 @@ type AccountsHandler interface {
 	UpdateByID(c *gin.Context)
 	GetByID(c *gin.Context)
 	List(c *gin.Context)
+	GetStats(c *gin.Context)
+	GetEquityCurve(c *gin.Context)
+	GetCalendar(c *gin.Context)
+	GetTimeAnalysis(c *gin.Context)
+	GetStreaks(c *gin.Context)
+	GetCompare(c *gin.Context)
 }
 
 type accountsHandler struct {
-	iDao dao.AccountsDao
+	iDao              dao.AccountsDao
+	tradesDao         dao.TradesDao
+	marketSessionsDao dao.MarketSessionsDao
 }
 
 // NewAccountsHandler creating the handler interface
@@ -43,6 +52,14 @@ func NewAccountsHandler() AccountsHandler {
 			database.GetDB(), // db driver is sqlite
 			cache.NewAccountsCache(database.GetCacheType()),
 		),
+		tradesDao: dao.NewTradesDao(
+			database.GetDB(), // db driver is sqlite
+			cache.NewTradesCache(database.GetCacheType()),
+		),
+		marketSessionsDao: dao.NewMarketSessionsDao(
+			database.GetDB(), // db driver is sqlite
+			cache.NewMarketSessionsCache(database.GetCacheType()),
+		),
 	}
 }
 
@@ -246,6 +263,334 @@ func (h *accountsHandler) List(c *gin.Context) {
 	})
 }
 
+// GetStats get the performance summary of a accounts
+// @Summary Get the performance summary of a accounts
+// @Description Computes win rate, average win/loss, expectancy, profit factor, average R, total commission and net pnl over the closed trades of the account.
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param startTime query string false "start of actual exit time, e.g. 2024-01-01"
+// @Param endTime query string false "end of actual exit time, a date without time includes the whole day"
+// @Param strategyID query int false "strategy id"
+// @Success 200 {object} types.GetAccountsStatsReply{}
+// @Router /api/v1/accounts/{id}/stats [get]
+// @Security BearerAuth
+func (h *accountsHandler) GetStats(c *gin.Context) {
+	_, id, isAbort := getAccountsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	form := &types.GetAccountsStatsRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	_, trades, timeRange, isAbort := h.getClosedTrades(c, id, &form.TradesStatsParams)
+	if isAbort {
+		return
+	}
+	trades = stats.FilterByExitTime(trades, timeRange, time.Local)
+
+	response.Success(c, gin.H{"stats": stats.Summarize(trades)})
+}
+
+// GetEquityCurve get the equity curve and drawdown of a accounts
+// @Summary Get the equity curve and drawdown of a accounts
+// @Description Builds the equity curve from the initial balance plus the cumulative net pnl of closed trades ordered by exit time, with running peak, drawdown, max drawdown, max drawdown duration and recovery time.
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param startTime query string false "start of actual exit time, trades before it are included in the starting equity"
+// @Param endTime query string false "end of actual exit time, a date without time includes the whole day"
+// @Param strategyID query int false "strategy id"
+// @Success 200 {object} types.GetAccountsEquityCurveReply{}
+// @Router /api/v1/accounts/{id}/equity [get]
+// @Security BearerAuth
+func (h *accountsHandler) GetEquityCurve(c *gin.Context) {
+	_, id, isAbort := getAccountsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	form := &types.GetAccountsEquityCurveRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	accounts, trades, timeRange, isAbort := h.getClosedTrades(c, id, &form.TradesStatsParams)
+	if isAbort {
+		return
+	}
+
+	response.Success(c, gin.H{"equityCurve": stats.BuildEquityCurve(accounts.InitialBalance, trades, timeRange, time.Local)})
+}
+
+// GetCalendar get the daily pnl calendar of a accounts
+// @Summary Get the daily pnl calendar of a accounts
+// @Description Aggregates the closed trades of a month or year per calendar day of the exit time in the requested time zone into net pnl, trade count and win/loss count.
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param period query string false "month like 2024-03 or year like 2024, default is the current month"
+// @Param timezone query string false "IANA time zone, e.g. Asia/Shanghai, default is the server time zone"
+// @Param strategyID query int false "strategy id"
+// @Success 200 {object} types.GetAccountsCalendarReply{}
+// @Router /api/v1/accounts/{id}/calendar [get]
+// @Security BearerAuth
+func (h *accountsHandler) GetCalendar(c *gin.Context) {
+	_, id, isAbort := getAccountsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	form := &types.GetAccountsCalendarRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	loc, err := stats.LoadLocation(form.Timezone)
+	if err != nil {
+		logger.Warn("LoadLocation error: ", logger.Err(err), logger.String("timezone", form.Timezone), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if form.Period == "" {
+		form.Period = time.Now().In(loc).Format("2006-01")
+	}
+	period, err := stats.ParsePeriod(form.Period, loc)
+	if err != nil {
+		logger.Warn("ParsePeriod error: ", logger.Err(err), logger.String("period", form.Period), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	_, trades, _, isAbort := h.getClosedTrades(c, id, &types.TradesStatsParams{StrategyID: form.StrategyID})
+	if isAbort {
+		return
+	}
+	trades = stats.FilterByExitTime(trades, period, loc)
+
+	response.Success(c, gin.H{
+		"period":   form.Period,
+		"timezone": loc.String(),
+		"days":     stats.DailyCalendar(trades, loc),
+		"stats":    stats.Summarize(trades),
+	})
+}
+
+// GetTimeAnalysis get the performance of a accounts by time of entry
+// @Summary Get the performance by time of entry
+// @Description Buckets the closed trades of the account by entry hour, weekday, market session and time slot since the session opened, with win rate and expectancy per bucket. The market sessions of the owner of the account are used, Asia/London/New York sessions if the owner has none.
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param startTime query string false "start of actual exit time, e.g. 2024-01-01"
+// @Param endTime query string false "end of actual exit time, a date without time includes the whole day"
+// @Param strategyID query int false "strategy id"
+// @Param timezone query string false "IANA time zone of entry hour and weekday, default is the server time zone"
+// @Param intervalMinutes query int false "size of the time slots since session open, default 30"
+// @Success 200 {object} types.GetAccountsTimeAnalysisReply{}
+// @Router /api/v1/accounts/{id}/timeAnalysis [get]
+// @Security BearerAuth
+func (h *accountsHandler) GetTimeAnalysis(c *gin.Context) {
+	_, id, isAbort := getAccountsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	form := &types.GetAccountsTimeAnalysisRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	loc, err := stats.LoadLocation(form.Timezone)
+	if err != nil {
+		logger.Warn("LoadLocation error: ", logger.Err(err), logger.String("timezone", form.Timezone), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	accounts, trades, timeRange, isAbort := h.getClosedTrades(c, id, &form.TradesStatsParams)
+	if isAbort {
+		return
+	}
+	trades = stats.FilterByExitTime(trades, timeRange, time.Local)
+
+	ctx := middleware.WrapCtx(c)
+	marketSessionss, err := h.marketSessionsDao.GetByUserID(ctx, uint64(accounts.UserID))
+	if err != nil {
+		logger.Error("GetByUserID error", logger.Err(err), logger.Any("userID", accounts.UserID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	configs := marketSessionsConfigs(marketSessionss)
+	sessions, err := stats.NewSessions(configs, loc)
+	if err != nil {
+		// the sessions are checked when they are saved, a time zone may have been removed since
+		logger.Warn("NewSessions error: ", logger.Err(err), logger.Any("sessions", configs), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrSessionMarketSessions.WithDetails(err.Error()))
+		return
+	}
+
+	response.Success(c, gin.H{"timeAnalysis": stats.AnalyzeTime(trades, loc, sessions, form.IntervalMinutes)})
+}
+
+// GetStreaks get the win/loss streaks of a accounts
+// @Summary Get the win/loss streaks of a accounts
+// @Description Finds the consecutive win and loss streaks of the closed trades of the account ordered by exit time, including the current and longest streaks and the performance of the trade right after N consecutive losses.
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param startTime query string false "start of actual exit time, e.g. 2024-01-01"
+// @Param endTime query string false "end of actual exit time, a date without time includes the whole day"
+// @Param strategyID query int false "strategy id"
+// @Param maxLosses query int false "longest losing streak to report the following trade for, default 5"
+// @Success 200 {object} types.GetAccountsStreaksReply{}
+// @Router /api/v1/accounts/{id}/streaks [get]
+// @Security BearerAuth
+func (h *accountsHandler) GetStreaks(c *gin.Context) {
+	_, id, isAbort := getAccountsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	form := &types.GetAccountsStreaksRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	_, trades, timeRange, isAbort := h.getClosedTrades(c, id, &form.TradesStatsParams)
+	if isAbort {
+		return
+	}
+	trades = stats.FilterByExitTime(trades, timeRange, time.Local)
+
+	response.Success(c, gin.H{"streaks": stats.AnalyzeStreaks(trades, form.MaxLosses)})
+}
+
+// GetCompare compare the performance of a accounts in two periods
+// @Summary Compare the performance of a accounts in two periods
+// @Description Compares the core metrics of the closed trades of the account in two arbitrary ranges of actual exit time, e.g. this week vs last week or this month vs the same month last year, returning the absolute and percent delta of every metric.
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param currentStartTime query string true "start of the current period, e.g. 2024-02-01"
+// @Param currentEndTime query string true "end of the current period, a date without time includes the whole day"
+// @Param previousStartTime query string true "start of the period to compare with, e.g. 2024-01-01"
+// @Param previousEndTime query string true "end of the period to compare with, a date without time includes the whole day"
+// @Param strategyID query int false "strategy id"
+// @Success 200 {object} types.GetAccountsCompareReply{}
+// @Router /api/v1/accounts/{id}/compare [get]
+// @Security BearerAuth
+func (h *accountsHandler) GetCompare(c *gin.Context) {
+	_, id, isAbort := getAccountsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	form := &types.GetAccountsCompareRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	currentRange, err := stats.ParseTimeRange(form.CurrentStartTime, form.CurrentEndTime, time.Local)
+	if err != nil {
+		logger.Warn("ParseTimeRange error: ", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	previousRange, err := stats.ParseTimeRange(form.PreviousStartTime, form.PreviousEndTime, time.Local)
+	if err != nil {
+		logger.Warn("ParseTimeRange error: ", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	_, trades, _, isAbort := h.getClosedTrades(c, id, &types.TradesStatsParams{StrategyID: form.StrategyID})
+	if isAbort {
+		return
+	}
+
+	response.Success(c, gin.H{"comparison": stats.ComparePeriods(
+		stats.FilterByExitTime(trades, currentRange, time.Local),
+		stats.FilterByExitTime(trades, previousRange, time.Local),
+	)})
+}
+
+// getClosedTrades get the accounts and all its closed trades of the strategy in params, ordered by
+// exit time, the time range in params is parsed and returned for the caller to apply. An account of
+// another user is not found.
+// If an error occurs, the response is written and isAbort is true.
+func (h *accountsHandler) getClosedTrades(c *gin.Context, id uint64, params *types.TradesStatsParams) (*model.Accounts, []*model.Trades, stats.TimeRange, bool) {
+	timeRange, err := stats.ParseTimeRange(params.StartTime, params.EndTime, time.Local)
+	if err != nil {
+		logger.Warn("ParseTimeRange error: ", logger.Err(err), logger.Any("params", params), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return nil, nil, timeRange, true
+	}
+
+	ctx := middleware.WrapCtx(c)
+	accounts, err := h.iDao.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			logger.Warn("GetByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return nil, nil, timeRange, true
+	}
+	claim, ok := middleware.GetClaims(c)
+	if !ok {
+		response.Error(c, ecode.Unauthorized)
+		return nil, nil, timeRange, true
+	}
+	if accounts.UserID != cast.ToInt(claim.UID) {
+		logger.Warn("account of another user", logger.Any("id", id), logger.Any("uid", claim.UID), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.NotFound)
+		return nil, nil, timeRange, true
+	}
+
+	trades, err := h.tradesDao.GetClosed(ctx, &dao.ClosedTradesCondition{AccountID: id, StrategyID: params.StrategyID})
+	if err != nil {
+		logger.Error("GetClosed error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return nil, nil, timeRange, true
+	}
+	stats.SortByExitTime(trades, time.Local)
+
+	return accounts, trades, timeRange, false
+}
+
 func getAccountsIDFromPath(c *gin.Context) (string, uint64, bool) {
 	idStr := c.Param("id")
 	id, err := utils.StrToUint64E(idStr)
@@ -17,6 +17,7 @@ import (
 	"helmsman/internal/cache"
 	"helmsman/internal/dao"
 	"helmsman/internal/database"
+	"helmsman/internal/ecode"
 	"helmsman/internal/model"
 	"helmsman/internal/types"
 )
@@ -41,7 +42,10 @@ func newAccountsHandler() *gotest.Handler {
 
 	// init mock handler
 	h := gotest.NewHandler(d, testData)
-	h.IHandler = &accountsHandler{iDao: d.IDao.(dao.AccountsDao)}
+	h.IHandler = &accountsHandler{
+		iDao:      d.IDao.(dao.AccountsDao),
+		tradesDao: dao.NewTradesDao(d.DB, nil),
+	}
 	iHandler := h.IHandler.(AccountsHandler)
 
 	testFns := []gotest.RouterInfo{
@@ -75,6 +79,12 @@ func newAccountsHandler() *gotest.Handler {
 			Path:        "/accounts/list",
 			HandlerFunc: iHandler.List,
 		},
+		{
+			FuncName:    "GetStats",
+			Method:      http.MethodGet,
+			Path:        "/accounts/:id/stats",
+			HandlerFunc: withClaims("1", iHandler.GetStats),
+		},
 	}
 
 	h.GoRunHTTPServer(testFns)
@@ -234,6 +244,30 @@ func Test_accountsHandler_List(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func Test_accountsHandler_GetStats(t *testing.T) {
+	h := newAccountsHandler()
+	defer h.Close()
+	result := &httpcli.StdResult{}
+
+	// own account
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(2, 1))
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `trades`").WithArgs(model.TradesStatusClosed, 2).
+		WillReturnRows(newTradesRows())
+	err := httpcli.Get(result, h.GetRequestURL("GetStats", 2))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Code)
+
+	// account of another user
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(3, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(3, 2))
+	err = httpcli.Get(result, h.GetRequestURL("GetStats", 3))
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+
+	assert.NoError(t, h.MockDao.SQLMock.ExpectationsWereMet())
+}
+
 func TestNewAccountsHandler(t *testing.T) {
 	defer func() {
 		recover()
@@ -0,0 +1,506 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/go-dev-frame/sponge/pkg/copier"
+	"github.com/go-dev-frame/sponge/pkg/errcode"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+
+	"helmsman/internal/cache"
+	"helmsman/internal/dao"
+	"helmsman/internal/database"
+	"helmsman/internal/ecode"
+	"helmsman/internal/model"
+	"helmsman/internal/stats"
+	"helmsman/internal/types"
+)
+
+var _ ExecutionsHandler = (*executionsHandler)(nil)
+
+// ExecutionsHandler defining the handler interface
+type ExecutionsHandler interface {
+	Create(c *gin.Context)
+	DeleteByID(c *gin.Context)
+	UpdateByID(c *gin.Context)
+	List(c *gin.Context)
+}
+
+type executionsHandler struct {
+	db            *gorm.DB
+	iDao          dao.ExecutionsDao
+	tradesDao     dao.TradesDao
+	accountsDao   dao.AccountsDao
+	strategiesDao dao.StrategiesDao
+}
+
+// NewExecutionsHandler creating the handler interface
+func NewExecutionsHandler() ExecutionsHandler {
+	return &executionsHandler{
+		db: database.GetDB(),
+		iDao: dao.NewExecutionsDao(
+			database.GetDB(), // db driver is sqlite
+			cache.NewExecutionsCache(database.GetCacheType()),
+		),
+		tradesDao: dao.NewTradesDao(
+			database.GetDB(), // db driver is sqlite
+			cache.NewTradesCache(database.GetCacheType()),
+		),
+		accountsDao: dao.NewAccountsDao(
+			database.GetDB(), // db driver is sqlite
+			cache.NewAccountsCache(database.GetCacheType()),
+		),
+		strategiesDao: dao.NewStrategiesDao(
+			database.GetDB(), // db driver is sqlite
+			cache.NewStrategiesCache(database.GetCacheType()),
+		),
+	}
+}
+
+// Create a new executions of a trades
+// @Summary Create a new executions of a trades
+// @Description Records a fill of the trades in the path and derives the average entry and exit price, position size, commission, pnl, r_multiple and status of the trades from all of its executions. A draft activated by its first fill is checked against the plan rules and finalized.
+// @Tags executions
+// @Accept json
+// @Produce json
+// @Param id path string true "trades id"
+// @Param data body types.CreateExecutionsRequest true "executions information"
+// @Success 200 {object} types.CreateExecutionsReply{}
+// @Router /api/v1/trades/{id}/executions [post]
+// @Security BearerAuth
+func (h *executionsHandler) Create(c *gin.Context) {
+	form := &types.CreateExecutionsRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	trades, isAbort := h.getTrades(c, true)
+	if isAbort {
+		return
+	}
+
+	executions := &model.Executions{}
+	err = copier.Copy(executions, form)
+	if err != nil {
+		response.Error(c, ecode.ErrCreateExecutions)
+		return
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+	executions.TradeID = int(trades.ID)
+	executions.Side = stats.NormalizeExecutionSide(form.Side)
+	executions.CreatedAt = time.Now().Format("2006-01-02 15:04:05")
+	executions.UpdatedAt = executions.CreatedAt
+
+	position, isAbort := h.changeExecutions(c, trades, func(executionss []*model.Executions) ([]*model.Executions, *errcode.Error) {
+		return append(executionss, executions), nil
+	}, func(ctx context.Context, tx *gorm.DB) error {
+		_, err := h.iDao.CreateByTx(ctx, tx, executions)
+		return err
+	})
+	if isAbort {
+		return
+	}
+
+	response.Success(c, gin.H{"id": executions.ID, "position": position})
+}
+
+// DeleteByID delete an executions of a trades by id
+// @Summary Delete an executions of a trades by id
+// @Description Deletes a fill of the trades in the path and derives the trades again from the remaining executions.
+// @Tags executions
+// @Accept json
+// @Produce json
+// @Param id path string true "trades id"
+// @Param executionID path string true "executions id"
+// @Success 200 {object} types.DeleteExecutionsByIDReply{}
+// @Router /api/v1/trades/{id}/executions/{executionID} [delete]
+// @Security BearerAuth
+func (h *executionsHandler) DeleteByID(c *gin.Context) {
+	executionID, isAbort := getExecutionsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	trades, isAbort := h.getTrades(c, true)
+	if isAbort {
+		return
+	}
+
+	position, isAbort := h.changeExecutions(c, trades, func(executionss []*model.Executions) ([]*model.Executions, *errcode.Error) {
+		remaining := make([]*model.Executions, 0, len(executionss))
+		for _, e := range executionss {
+			if e.ID != executionID {
+				remaining = append(remaining, e)
+			}
+		}
+		if len(remaining) == len(executionss) {
+			return nil, ecode.NotFound
+		}
+		return remaining, nil
+	}, func(ctx context.Context, tx *gorm.DB) error {
+		return h.iDao.DeleteByTx(ctx, tx, executionID)
+	})
+	if isAbort {
+		return
+	}
+
+	response.Success(c, gin.H{"position": position})
+}
+
+// UpdateByID update an executions of a trades by id
+// @Summary Update an executions of a trades by id
+// @Description Updates a fill of the trades in the path, support partial update, and derives the trades again from its executions.
+// @Tags executions
+// @Accept json
+// @Produce json
+// @Param id path string true "trades id"
+// @Param executionID path string true "executions id"
+// @Param data body types.UpdateExecutionsByIDRequest true "executions information"
+// @Success 200 {object} types.UpdateExecutionsByIDReply{}
+// @Router /api/v1/trades/{id}/executions/{executionID} [put]
+// @Security BearerAuth
+func (h *executionsHandler) UpdateByID(c *gin.Context) {
+	executionID, isAbort := getExecutionsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	form := &types.UpdateExecutionsByIDRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	trades, isAbort := h.getTrades(c, true)
+	if isAbort {
+		return
+	}
+
+	executions := &model.Executions{}
+	err = copier.Copy(executions, form)
+	if err != nil {
+		response.Error(c, ecode.ErrUpdateByIDExecutions)
+		return
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+	executions.ID = executionID
+	executions.UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+	if form.Side != "" {
+		executions.Side = stats.NormalizeExecutionSide(form.Side)
+		if executions.Side == "" {
+			logger.Warn("unknown side", logger.String("side", form.Side), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrSideExecutions)
+			return
+		}
+	}
+
+	position, isAbort := h.changeExecutions(c, trades, func(executionss []*model.Executions) ([]*model.Executions, *errcode.Error) {
+		updated := make([]*model.Executions, 0, len(executionss))
+		found := false
+		for _, e := range executionss {
+			if e.ID == executionID {
+				e = mergeExecutions(e, executions)
+				found = true
+			}
+			updated = append(updated, e)
+		}
+		if !found {
+			return nil, ecode.NotFound
+		}
+		return updated, nil
+	}, func(ctx context.Context, tx *gorm.DB) error {
+		return h.iDao.UpdateByTx(ctx, tx, executions)
+	})
+	if isAbort {
+		return
+	}
+
+	response.Success(c, gin.H{"position": position})
+}
+
+// List get the executions of a trades
+// @Summary Get the executions of a trades
+// @Description Returns the fills of the trades in the path in the order of their time and the position derived from them.
+// @Tags executions
+// @Accept json
+// @Produce json
+// @Param id path string true "trades id"
+// @Success 200 {object} types.ListExecutionssReply{}
+// @Router /api/v1/trades/{id}/executions [get]
+// @Security BearerAuth
+func (h *executionsHandler) List(c *gin.Context) {
+	trades, executionss, isAbort := h.getTradesExecutions(c)
+	if isAbort {
+		return
+	}
+
+	position, isAbort := derivePosition(c, trades, executionss)
+	if isAbort {
+		return
+	}
+
+	data, err := convertExecutionss(executionss)
+	if err != nil {
+		response.Error(c, ecode.ErrListExecutions)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"executions": data,
+		"position":   position,
+	})
+}
+
+// getTrades get the trades in the path, the executions of a cancelled trades cannot be changed.
+// If an error occurs, the response is written and isAbort is true.
+func (h *executionsHandler) getTrades(c *gin.Context, change bool) (*model.Trades, bool) {
+	_, id, isAbort := getTradesIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return nil, true
+	}
+
+	ctx := middleware.WrapCtx(c)
+	trades, err := h.tradesDao.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			logger.Warn("GetByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return nil, true
+	}
+	if change && trades.Status == model.TradesStatusCancelled {
+		logger.Warn("executions of a cancelled trades", logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrPhaseTrades)
+		return nil, true
+	}
+	return trades, false
+}
+
+// getTradesExecutions get the trades in the path and its executions. If an error occurs, the
+// response is written and isAbort is true.
+func (h *executionsHandler) getTradesExecutions(c *gin.Context) (*model.Trades, []*model.Executions, bool) {
+	trades, isAbort := h.getTrades(c, false)
+	if isAbort {
+		return nil, nil, true
+	}
+
+	ctx := middleware.WrapCtx(c)
+	executionss, err := h.iDao.GetByTradeID(ctx, trades.ID)
+	if err != nil {
+		logger.Error("GetByTradeID error", logger.Err(err), logger.Any("id", trades.ID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return nil, nil, true
+	}
+	return trades, executionss, false
+}
+
+// changeExecutions change an execution of the trades in one transaction: the executions are read
+// again, apply returns them after the change or an error if it is not possible, write stores the
+// change and the position derived from the changed executions is written to the trades. The status
+// of the trades is only changed if nobody else changed it in the meantime, a draft activated by the
+// executions is checked against the plan rules and finalized. If an error occurs, the response is
+// written and isAbort is true.
+func (h *executionsHandler) changeExecutions(c *gin.Context, trades *model.Trades,
+	apply func(executionss []*model.Executions) ([]*model.Executions, *errcode.Error),
+	write func(ctx context.Context, tx *gorm.DB) error) (*stats.Position, bool) {
+	ctx := middleware.WrapCtx(c)
+	var position *stats.Position
+	var e *errcode.Error
+	isPlanAbort := false
+	err := h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		executionss, err := h.iDao.GetByTradeIDByTx(ctx, tx, trades.ID)
+		if err != nil {
+			return err
+		}
+		executionss, e = apply(executionss)
+		if e != nil {
+			return e.Err()
+		}
+		position, err = stats.DerivePosition(trades.Direction, executionss, time.Local)
+		if err != nil {
+			e = positionError(err)
+			return err
+		}
+		isDraftActivated := trades.Draft && position.Status != model.TradesStatusPlanned
+		// a draft is checked against the plan rules with its derived entry once it is activated
+		if isDraftActivated && checkTradesPlan(c, h.strategiesDao, h.accountsDao, tradesFromPosition(trades, position)) {
+			isPlanAbort = true
+			return ecode.ErrPlanTrades.Err()
+		}
+		err = write(ctx, tx)
+		if err != nil {
+			return err
+		}
+		now := time.Now().Format("2006-01-02 15:04:05")
+		columns := tradesColumnsFromPosition(trades, position, now)
+		if isDraftActivated {
+			columns["draft"] = false
+		}
+		return h.tradesDao.UpdateStatusByTx(ctx, tx, trades.ID, trades.Status, columns)
+	})
+	if err != nil {
+		switch {
+		case isPlanAbort:
+			// the response is written by checkTradesPlan
+		case e != nil:
+			logger.Warn("change executions error: ", logger.Err(err), logger.Any("id", trades.ID), middleware.GCtxRequestIDField(c))
+			response.Error(c, e)
+		case errors.Is(err, database.ErrRecordNotFound):
+			// the status was changed by another request in the meantime
+			logger.Warn("UpdateStatusByTx conflict", logger.Err(err), logger.Any("id", trades.ID), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrTransitionTrades)
+		default:
+			logger.Error("change executions error", logger.Err(err), logger.Any("id", trades.ID), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return nil, true
+	}
+	return position, false
+}
+
+// derivePosition derive the position of the trades from executions. If an error occurs, the
+// response is written and isAbort is true.
+func derivePosition(c *gin.Context, trades *model.Trades, executionss []*model.Executions) (*stats.Position, bool) {
+	position, err := stats.DerivePosition(trades.Direction, executionss, time.Local)
+	if err != nil {
+		logger.Warn("DerivePosition error: ", logger.Err(err), logger.Any("id", trades.ID), middleware.GCtxRequestIDField(c))
+		response.Error(c, positionError(err))
+		return nil, true
+	}
+	return position, false
+}
+
+// positionError error code of an error of stats.DerivePosition
+func positionError(err error) *errcode.Error {
+	switch {
+	case errors.Is(err, stats.ErrPositionDirection):
+		return ecode.ErrDirectionTrades
+	case errors.Is(err, stats.ErrExecutionSide):
+		return ecode.ErrSideExecutions
+	case errors.Is(err, stats.ErrOverfilled):
+		return ecode.ErrOverfilledExecutions
+	}
+	return ecode.InvalidParams
+}
+
+// tradesColumnsFromPosition columns of the trades derived from the position, the exit and result
+// are only set once the position is flat so that an open trades keeps the fields of its phase
+func tradesColumnsFromPosition(trades *model.Trades, position *stats.Position, now string) map[string]interface{} {
+	derived := tradesFromPosition(trades, position)
+	columns := map[string]interface{}{
+		"status":             position.Status,
+		"actual_entry_price": derived.ActualEntryPrice,
+		"actual_entry_time":  derived.ActualEntryTime,
+		"actual_exit_price":  derived.ActualExitPrice,
+		"actual_exit_time":   derived.ActualExitTime,
+		"position_size":      derived.PositionSize,
+		"commission":         derived.Commission,
+		"pnl":                derived.Pnl,
+		"r_multiple":         derived.RMultiple,
+		"updated_at":         now,
+	}
+	if position.Status == model.TradesStatusPlanned {
+		columns["activated_at"] = ""
+	} else if trades.Status == model.TradesStatusPlanned {
+		columns["activated_at"] = now
+	}
+	if position.Status != model.TradesStatusClosed {
+		columns["closed_at"] = ""
+	} else if trades.Status != model.TradesStatusClosed {
+		columns["closed_at"] = now
+	}
+	return columns
+}
+
+// tradesFromPosition copy of the trades with the entry, exit, position size, commission and result
+// derived from the position
+func tradesFromPosition(trades *model.Trades, position *stats.Position) *model.Trades {
+	derived := *trades
+	derived.ActualEntryPrice, derived.ActualEntryTime = position.AvgEntryPrice, position.EntryTime
+	derived.ActualExitPrice, derived.ActualExitTime = 0, ""
+	derived.PositionSize, derived.Commission = position.EntryQuantity, position.Fees
+	derived.Pnl, derived.RMultiple = 0, 0
+	if position.Status == model.TradesStatusClosed {
+		derived.ActualExitPrice, derived.ActualExitTime = position.AvgExitPrice, position.ExitTime
+		derived.Pnl = position.RealizedPnl
+		if risk, ok := stats.InitialRisk(&derived); ok {
+			derived.RMultiple = stats.NetPnl(&derived) / risk
+		}
+	}
+	return &derived
+}
+
+// mergeExecutions copy of current with the fields set in update
+func mergeExecutions(current *model.Executions, update *model.Executions) *model.Executions {
+	merged := *current
+	if update.Side != "" {
+		merged.Side = update.Side
+	}
+	if update.Quantity != 0 {
+		merged.Quantity = update.Quantity
+	}
+	if update.Price != 0 {
+		merged.Price = update.Price
+	}
+	if update.Fee != 0 {
+		merged.Fee = update.Fee
+	}
+	if update.ExecutedAt != "" {
+		merged.ExecutedAt = update.ExecutedAt
+	}
+	return &merged
+}
+
+func getExecutionsIDFromPath(c *gin.Context) (uint64, bool) {
+	idStr := c.Param("executionID")
+	id, err := utils.StrToUint64E(idStr)
+	if err != nil || id == 0 {
+		logger.Warn("StrToUint64E error: ", logger.String("idStr", idStr), middleware.GCtxRequestIDField(c))
+		return 0, true
+	}
+
+	return id, false
+}
+
+func convertExecutions(executions *model.Executions) (*types.ExecutionsObjDetail, error) {
+	data := &types.ExecutionsObjDetail{}
+	err := copier.Copy(data, executions)
+	if err != nil {
+		return nil, err
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+
+	return data, nil
+}
+
+func convertExecutionss(fromValues []*model.Executions) ([]*types.ExecutionsObjDetail, error) {
+	toValues := []*types.ExecutionsObjDetail{}
+	for _, v := range fromValues {
+		data, err := convertExecutions(v)
+		if err != nil {
+			return nil, err
+		}
+		toValues = append(toValues, data)
+	}
+
+	return toValues, nil
+}
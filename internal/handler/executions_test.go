@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-dev-frame/sponge/pkg/gotest"
+	"github.com/go-dev-frame/sponge/pkg/httpcli"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+
+	"helmsman/internal/cache"
+	"helmsman/internal/dao"
+	"helmsman/internal/database"
+	"helmsman/internal/ecode"
+	"helmsman/internal/model"
+	"helmsman/internal/types"
+)
+
+func newExecutionsHandler() *gotest.Handler {
+	testData := &model.Executions{}
+	testData.ID = 1
+
+	// init mock cache
+	c := gotest.NewCache(map[string]interface{}{utils.Uint64ToStr(testData.ID): testData})
+	c.ICache = cache.NewExecutionsCache(&database.CacheType{
+		CType: "redis",
+		Rdb:   c.RedisClient,
+	})
+
+	// init mock dao
+	d := gotest.NewDao(c, testData)
+	d.IDao = dao.NewExecutionsDao(d.DB, c.ICache.(cache.ExecutionsCache))
+
+	// init mock handler
+	h := gotest.NewHandler(d, testData)
+	h.IHandler = &executionsHandler{
+		db:            d.DB,
+		iDao:          d.IDao.(dao.ExecutionsDao),
+		tradesDao:     dao.NewTradesDao(d.DB, nil),
+		accountsDao:   dao.NewAccountsDao(d.DB, nil),
+		strategiesDao: dao.NewStrategiesDao(d.DB, nil),
+	}
+	iHandler := h.IHandler.(ExecutionsHandler)
+
+	testFns := []gotest.RouterInfo{
+		{
+			FuncName:    "Create",
+			Method:      http.MethodPost,
+			Path:        "/trades/:id/executions",
+			HandlerFunc: iHandler.Create,
+		},
+		{
+			FuncName:    "DeleteByID",
+			Method:      http.MethodDelete,
+			Path:        "/trades/:id/executions/:executionID",
+			HandlerFunc: iHandler.DeleteByID,
+		},
+		{
+			FuncName:    "UpdateByID",
+			Method:      http.MethodPut,
+			Path:        "/trades/:id/executions/:executionID",
+			HandlerFunc: iHandler.UpdateByID,
+		},
+		{
+			FuncName:    "List",
+			Method:      http.MethodGet,
+			Path:        "/trades/:id/executions",
+			HandlerFunc: iHandler.List,
+		},
+	}
+
+	h.GoRunHTTPServer(testFns)
+
+	time.Sleep(time.Millisecond * 200)
+	return h
+}
+
+func Test_executionsHandler_Create(t *testing.T) {
+	h := newExecutionsHandler()
+	defer h.Close()
+	result := &httpcli.StdResult{}
+
+	// the first fill activates the trades
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(2, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 2, Status: model.TradesStatusPlanned, Direction: model.TradesDirectionLong}))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(2).WillReturnRows(newExecutionsRows())
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs(sqlmock.AnyArg(), 100.0, "2024-01-02 09:30:00", 0.0, "", "", 0.0, 0.0, 2.0, 0.0, model.TradesStatusActive,
+			sqlmock.AnyArg(), 2, model.TradesStatusPlanned).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+	err := httpcli.Post(result, h.GetRequestURL("Create", 2), &types.CreateExecutionsRequest{
+		Side: "buy", Quantity: 2, Price: 100, ExecutedAt: "2024-01-02 09:30:00",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Code)
+
+	// the status of the trades was changed by another request, the fill is rolled back
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(3, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 3, Status: model.TradesStatusPlanned, Direction: model.TradesDirectionLong}))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(3).WillReturnRows(newExecutionsRows())
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(0, 0))
+	h.MockDao.SQLMock.ExpectRollback()
+	err = httpcli.Post(result, h.GetRequestURL("Create", 3), &types.CreateExecutionsRequest{
+		Side: "buy", Quantity: 2, Price: 100, ExecutedAt: "2024-01-02 09:30:00",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrTransitionTrades.Code(), result.Code)
+
+	assert.NoError(t, h.MockDao.SQLMock.ExpectationsWereMet())
+}
+
+func Test_executionsHandler_Draft(t *testing.T) {
+	h := newExecutionsHandler()
+	defer h.Close()
+	result := &httpcli.StdResult{}
+	fill := &types.CreateExecutionsRequest{Side: "buy", Quantity: 2, Price: 100, ExecutedAt: "2024-01-02 09:30:00"}
+
+	// the first fill of a draft checks its plan, the stop loss is above the entry
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(4, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 4, Status: model.TradesStatusPlanned, Direction: model.TradesDirectionLong,
+			PlannedEntryPrice: 100, PlannedStopLoss: 105, Draft: true}))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(4).WillReturnRows(newExecutionsRows())
+	h.MockDao.SQLMock.ExpectRollback()
+	err := httpcli.Post(result, h.GetRequestURL("Create", 4), fill)
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrPlanTrades.Code(), result.Code)
+
+	// the first fill of a valid draft finalizes it
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(5, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 5, Status: model.TradesStatusPlanned, Direction: model.TradesDirectionLong,
+			PlannedEntryPrice: 100, PlannedStopLoss: 95, PlannedTakeProfit: 110, Draft: true}))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(5).WillReturnRows(newExecutionsRows())
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs(sqlmock.AnyArg(), 100.0, "2024-01-02 09:30:00", 0.0, "", "", 0.0, false, 0.0, 2.0, 0.0, model.TradesStatusActive,
+			sqlmock.AnyArg(), 5, model.TradesStatusPlanned).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+	err = httpcli.Post(result, h.GetRequestURL("Create", 5), fill)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Code)
+
+	assert.NoError(t, h.MockDao.SQLMock.ExpectationsWereMet())
+}
+
+func Test_executionsHandler_Overfilled(t *testing.T) {
+	h := newExecutionsHandler()
+	defer h.Close()
+	result := &httpcli.StdResult{}
+
+	// selling more than the open quantity
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(2, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 2, Status: model.TradesStatusActive, Direction: model.TradesDirectionLong}))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(2).
+		WillReturnRows(newExecutionsRows(&model.Executions{ID: 1, TradeID: 2, Side: "buy", Quantity: 2, Price: 100, ExecutedAt: "2024-01-02 09:30:00"}))
+	h.MockDao.SQLMock.ExpectRollback()
+	err := httpcli.Post(result, h.GetRequestURL("Create", 2), &types.CreateExecutionsRequest{
+		Side: "sell", Quantity: 3, Price: 110, ExecutedAt: "2024-01-02 10:00:00",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrOverfilledExecutions.Code(), result.Code)
+
+	// moving an exit before the entry it closes
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(3, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 3, Status: model.TradesStatusClosed, Direction: model.TradesDirectionLong}))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(3).
+		WillReturnRows(newExecutionsRows(
+			&model.Executions{ID: 1, TradeID: 3, Side: "buy", Quantity: 2, Price: 100, ExecutedAt: "2024-01-02 09:30:00"},
+			&model.Executions{ID: 2, TradeID: 3, Side: "sell", Quantity: 2, Price: 110, ExecutedAt: "2024-01-02 10:00:00"},
+		))
+	h.MockDao.SQLMock.ExpectRollback()
+	err = httpcli.Put(result, h.GetRequestURL("UpdateByID", 3, 2), &types.UpdateExecutionsByIDRequest{ExecutedAt: "2024-01-02 09:00:00"})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrOverfilledExecutions.Code(), result.Code)
+
+	assert.NoError(t, h.MockDao.SQLMock.ExpectationsWereMet())
+}
+
+func Test_executionsHandler_Cancelled(t *testing.T) {
+	h := newExecutionsHandler()
+	defer h.Close()
+	result := &httpcli.StdResult{}
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(2, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 2, Status: model.TradesStatusCancelled, Direction: model.TradesDirectionLong}))
+	err := httpcli.Post(result, h.GetRequestURL("Create", 2), &types.CreateExecutionsRequest{
+		Side: "buy", Quantity: 2, Price: 100, ExecutedAt: "2024-01-02 09:30:00",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrPhaseTrades.Code(), result.Code)
+
+	assert.NoError(t, h.MockDao.SQLMock.ExpectationsWereMet())
+}
+
+func Test_executionsHandler_DeleteByID(t *testing.T) {
+	h := newExecutionsHandler()
+	defer h.Close()
+	result := &httpcli.StdResult{}
+
+	// deleting the last fill returns the trades to planned
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(4, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 4, Status: model.TradesStatusActive, Direction: model.TradesDirectionLong}))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(4).
+		WillReturnRows(newExecutionsRows(&model.Executions{ID: 9, TradeID: 4, Side: "buy", Quantity: 2, Price: 100, ExecutedAt: "2024-01-02 09:30:00"}))
+	h.MockDao.SQLMock.ExpectExec("DELETE .*").WithArgs(9).WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs("", 0.0, "", 0.0, "", "", 0.0, 0.0, 0.0, 0.0, model.TradesStatusPlanned,
+			sqlmock.AnyArg(), 4, model.TradesStatusActive).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+	err := httpcli.Delete(result, h.GetRequestURL("DeleteByID", 4, 9))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Code)
+
+	// an executions of another trades
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(5, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 5, Status: model.TradesStatusActive, Direction: model.TradesDirectionLong}))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(5).
+		WillReturnRows(newExecutionsRows(&model.Executions{ID: 10, TradeID: 5, Side: "buy", Quantity: 2, Price: 100, ExecutedAt: "2024-01-02 09:30:00"}))
+	h.MockDao.SQLMock.ExpectRollback()
+	err = httpcli.Delete(result, h.GetRequestURL("DeleteByID", 5, 9))
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.NotFound.Code(), result.Code)
+
+	assert.NoError(t, h.MockDao.SQLMock.ExpectationsWereMet())
+}
+
+func Test_executionsHandler_List(t *testing.T) {
+	h := newExecutionsHandler()
+	defer h.Close()
+	result := &httpcli.StdResult{}
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(2, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 2, Status: model.TradesStatusActive, Direction: model.TradesDirectionLong}))
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(2).
+		WillReturnRows(newExecutionsRows(&model.Executions{ID: 1, TradeID: 2, Side: "buy", Quantity: 2, Price: 100, ExecutedAt: "2024-01-02 09:30:00"}))
+	err := httpcli.Get(result, h.GetRequestURL("List", 2))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Code)
+
+	assert.NoError(t, h.MockDao.SQLMock.ExpectationsWereMet())
+}
+
+func TestNewExecutionsHandler(t *testing.T) {
+	defer func() {
+		recover()
+	}()
+	_ = NewExecutionsHandler()
+}
+
+func newExecutionsRows(values ...*model.Executions) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "trade_id", "side", "quantity", "price", "fee", "executed_at"})
+	for _, v := range values {
+		rows.AddRow(v.ID, v.TradeID, v.Side, v.Quantity, v.Price, v.Fee, v.ExecutedAt)
+	}
+	return rows
+}
@@ -0,0 +1,503 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+
+	"github.com/go-dev-frame/sponge/pkg/copier"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+
+	"helmsman/internal/cache"
+	"helmsman/internal/dao"
+	"helmsman/internal/database"
+	"helmsman/internal/ecode"
+	"helmsman/internal/model"
+	"helmsman/internal/stats"
+	"helmsman/internal/types"
+)
+
+var _ ExitReasonsHandler = (*exitReasonsHandler)(nil)
+
+// ExitReasonsHandler defining the handler interface
+type ExitReasonsHandler interface {
+	Create(c *gin.Context)
+	DeleteByID(c *gin.Context)
+	UpdateByID(c *gin.Context)
+	GetByID(c *gin.Context)
+	List(c *gin.Context)
+	ListAll(c *gin.Context)
+	GetPerformance(c *gin.Context)
+}
+
+type exitReasonsHandler struct {
+	db        *gorm.DB
+	iDao      dao.ExitReasonsDao
+	tradesDao dao.TradesDao
+}
+
+// NewExitReasonsHandler creating the handler interface
+func NewExitReasonsHandler() ExitReasonsHandler {
+	return &exitReasonsHandler{
+		db: database.GetDB(),
+		iDao: dao.NewExitReasonsDao(
+			database.GetDB(), // db driver is sqlite
+			cache.NewExitReasonsCache(database.GetCacheType()),
+		),
+		tradesDao: dao.NewTradesDao(
+			database.GetDB(), // db driver is sqlite
+			cache.NewTradesCache(database.GetCacheType()),
+		),
+	}
+}
+
+// Create a new exitReasons
+// @Summary Create a new exitReasons
+// @Description Creates a new exitReasons entity using the provided data in the request body.
+// @Tags exitReasons
+// @Accept json
+// @Produce json
+// @Param data body types.CreateExitReasonsRequest true "exitReasons information"
+// @Success 200 {object} types.CreateExitReasonsReply{}
+// @Router /api/v1/exitReasons [post]
+// @Security BearerAuth
+func (h *exitReasonsHandler) Create(c *gin.Context) {
+	form := &types.CreateExitReasonsRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	exitReasons := &model.ExitReasons{}
+	err = copier.Copy(exitReasons, form)
+	if err != nil {
+		response.Error(c, ecode.ErrCreateExitReasons)
+		return
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+	exitReasons.CreatedAt = time.Now().Format("2006-01-02 15:04:05")
+	exitReasons.UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+	claim, ok := middleware.GetClaims(c)
+	if !ok {
+		response.Error(c, ecode.Unauthorized)
+		return
+	}
+	exitReasons.UserID = cast.ToInt(claim.UID)
+	if exitReasons.Code == "" {
+		exitReasons.Code = exitReasons.Name
+	}
+	exitReasons.Code = stats.NormalizeExitReasonCode(exitReasons.Code)
+	if exitReasons.Code == "" {
+		logger.Warn("empty exit reason code", logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	vocabulary, err := h.iDao.GetAll(ctx, uint64(exitReasons.UserID))
+	if err != nil {
+		logger.Error("GetAll error", logger.Err(err), logger.Any("userID", exitReasons.UserID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	for _, v := range vocabulary {
+		if v.Code == exitReasons.Code {
+			response.Error(c, ecode.ErrCodeExistsExitReasons)
+			return
+		}
+	}
+	err = h.iDao.Create(ctx, exitReasons)
+	if err != nil {
+		logger.Error("Create error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	response.Success(c, gin.H{"id": exitReasons.ID})
+}
+
+// DeleteByID delete a exitReasons by id
+// @Summary Delete a exitReasons by id
+// @Description Deletes a existing exitReasons of the current user identified by the given id in the path, the default exit reasons cannot be deleted. The trades of the user with its code are changed to other.
+// @Tags exitReasons
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.DeleteExitReasonsByIDReply{}
+// @Router /api/v1/exitReasons/{id} [delete]
+// @Security BearerAuth
+func (h *exitReasonsHandler) DeleteByID(c *gin.Context) {
+	_, id, isAbort := getExitReasonsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	current, isAbort := h.getOwnExitReasons(c, id)
+	if isAbort {
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	// the trades must not keep referring to a code that no longer exists
+	err := h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := h.tradesDao.RenameExitReasonByTx(ctx, tx, current.UserID, current.Code, model.ExitReasonOther)
+		if err != nil {
+			return err
+		}
+		return h.iDao.DeleteByTx(ctx, tx, id)
+	})
+	if err != nil {
+		logger.Error("DeleteByTx error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	response.Success(c)
+}
+
+// UpdateByID update a exitReasons by id
+// @Summary Update a exitReasons by id
+// @Description Updates the specified exitReasons of the current user by given id in the path, support partial update. The default exit reasons cannot be updated, a changed code must be unique and is also changed in the trades of the user.
+// @Tags exitReasons
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param data body types.UpdateExitReasonsByIDRequest true "exitReasons information"
+// @Success 200 {object} types.UpdateExitReasonsByIDReply{}
+// @Router /api/v1/exitReasons/{id} [put]
+// @Security BearerAuth
+func (h *exitReasonsHandler) UpdateByID(c *gin.Context) {
+	_, id, isAbort := getExitReasonsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	form := &types.UpdateExitReasonsByIDRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	form.ID = id
+
+	exitReasons := &model.ExitReasons{}
+	err = copier.Copy(exitReasons, form)
+	if err != nil {
+		response.Error(c, ecode.ErrUpdateByIDExitReasons)
+		return
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+	if form.Code != "" {
+		exitReasons.Code = stats.NormalizeExitReasonCode(form.Code)
+		if exitReasons.Code == "" {
+			logger.Warn("empty exit reason code", logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+			return
+		}
+	}
+
+	current, isAbort := h.getOwnExitReasons(c, id)
+	if isAbort {
+		return
+	}
+	ctx := middleware.WrapCtx(c)
+	if exitReasons.Code == "" || exitReasons.Code == current.Code {
+		exitReasons.Code = ""
+		err = h.iDao.UpdateByID(ctx, exitReasons)
+	} else {
+		var vocabulary []*model.ExitReasons
+		vocabulary, err = h.iDao.GetAll(ctx, uint64(current.UserID))
+		if err != nil {
+			logger.Error("GetAll error", logger.Err(err), logger.Any("userID", current.UserID), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+			return
+		}
+		for _, v := range vocabulary {
+			if v.Code == exitReasons.Code {
+				response.Error(c, ecode.ErrCodeExistsExitReasons)
+				return
+			}
+		}
+		// the trades keep referring to the exit reason by its code
+		err = h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			err := h.iDao.UpdateByTx(ctx, tx, exitReasons)
+			if err != nil {
+				return err
+			}
+			return h.tradesDao.RenameExitReasonByTx(ctx, tx, current.UserID, current.Code, exitReasons.Code)
+		})
+	}
+	if err != nil {
+		logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	response.Success(c)
+}
+
+// GetByID get a exitReasons by id
+// @Summary Get a exitReasons by id
+// @Description Gets detailed information of a exitReasons specified by the given id in the path.
+// @Tags exitReasons
+// @Param id path string true "id"
+// @Accept json
+// @Produce json
+// @Success 200 {object} types.GetExitReasonsByIDReply{}
+// @Router /api/v1/exitReasons/{id} [get]
+// @Security BearerAuth
+func (h *exitReasonsHandler) GetByID(c *gin.Context) {
+	_, id, isAbort := getExitReasonsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	exitReasons, err := h.iDao.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			logger.Warn("GetByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	data := &types.ExitReasonsObjDetail{}
+	err = copier.Copy(data, exitReasons)
+	if err != nil {
+		response.Error(c, ecode.ErrGetByIDExitReasons)
+		return
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+
+	response.Success(c, gin.H{"exitReasons": data})
+}
+
+// List get a paginated list of exitReasonss by custom conditions
+// @Summary Get a paginated list of exitReasonss by custom conditions
+// @Description Returns a paginated list of exitReasons based on query filters, including page number and size.
+// @Tags exitReasons
+// @Accept json
+// @Produce json
+// @Param data body types.Params true "query parameters"
+// @Success 200 {object} types.ListExitReasonssReply{}
+// @Router /api/v1/exitReasons/list [post]
+// @Security BearerAuth
+func (h *exitReasonsHandler) List(c *gin.Context) {
+	form := &types.ListExitReasonssRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	exitReasonss, total, err := h.iDao.GetByColumns(ctx, &form.Params)
+	if err != nil {
+		logger.Error("GetByColumns error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertExitReasonss(exitReasonss)
+	if err != nil {
+		response.Error(c, ecode.ErrListExitReasons)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"exitReasonss": data,
+		"total":        total,
+	})
+}
+
+// ListAll 获取全部出场原因，包括默认出场原因
+// @Summary Get all exitReasons
+// @Description Returns the exit reason vocabulary of the current user, including the default exit reasons shared by all users.
+// @Tags exitReasons
+// @Accept json
+// @Produce json
+// @Success 200 {object} types.ListExitReasonssReply{}
+// @Router /api/v1/exitReasons/list/all [get]
+// @Security BearerAuth
+func (h *exitReasonsHandler) ListAll(c *gin.Context) {
+	ctx := middleware.WrapCtx(c)
+	claim, ok := middleware.GetClaims(c)
+	if !ok {
+		response.Error(c, ecode.Unauthorized)
+		return
+	}
+	userID := cast.ToInt(claim.UID)
+	exitReasonss, err := h.iDao.GetAll(ctx, uint64(userID))
+	if err != nil {
+		logger.Error("GetAll error", logger.Err(err), logger.Any("userID", userID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertExitReasonss(exitReasonss)
+	if err != nil {
+		response.Error(c, ecode.ErrListExitReasons)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"exitReasonss": data,
+	})
+}
+
+// GetPerformance get the performance of trades per exit reason
+// @Summary Get the performance per exit reason
+// @Description Returns trade count, win rate, average R and pnl of the closed trades of the current user per exit reason of the vocabulary, trades whose exit reason is not in the vocabulary are reported per stored value.
+// @Tags exitReasons
+// @Accept json
+// @Produce json
+// @Param accountID query int false "account id"
+// @Param strategyID query int false "strategy id"
+// @Param startTime query string false "start of actual exit time, e.g. 2024-01-01"
+// @Param endTime query string false "end of actual exit time, a date without time includes the whole day"
+// @Success 200 {object} types.GetExitReasonsPerformanceReply{}
+// @Router /api/v1/exitReasons/performance [get]
+// @Security BearerAuth
+func (h *exitReasonsHandler) GetPerformance(c *gin.Context) {
+	form := &types.GetExitReasonsPerformanceRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	timeRange, err := stats.ParseTimeRange(form.StartTime, form.EndTime, time.Local)
+	if err != nil {
+		logger.Warn("ParseTimeRange error: ", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	claim, ok := middleware.GetClaims(c)
+	if !ok {
+		response.Error(c, ecode.Unauthorized)
+		return
+	}
+	userID := cast.ToInt(claim.UID)
+
+	ctx := middleware.WrapCtx(c)
+	exitReasonss, err := h.iDao.GetAll(ctx, uint64(userID))
+	if err != nil {
+		logger.Error("GetAll error", logger.Err(err), logger.Any("userID", userID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	trades, err := h.tradesDao.GetClosed(ctx, &dao.ClosedTradesCondition{
+		AccountID:  form.AccountID,
+		UserID:     userID,
+		StrategyID: form.StrategyID,
+	})
+	if err != nil {
+		logger.Error("GetClosed error", logger.Err(err), logger.Any("userID", userID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	trades = stats.FilterByExitTime(trades, timeRange, time.Local)
+
+	breakdown, unmapped := stats.ExitReasonBreakdown(trades, exitReasonss)
+	items := make([]*types.ExitReasonsPerformanceItem, 0, len(breakdown))
+	for _, v := range breakdown {
+		items = append(items, &types.ExitReasonsPerformanceItem{
+			ExitReasonID: v.ExitReason.ID,
+			Code:         v.ExitReason.Code,
+			Name:         v.ExitReason.Name,
+			Summary:      *v.Summary,
+		})
+	}
+
+	response.Success(c, gin.H{
+		"exitReasons": items,
+		"unmapped":    unmapped,
+	})
+}
+
+// getOwnExitReasons get an exit reason of the current user, the default exit reasons and the exit
+// reasons of other users cannot be changed. If an error occurs, the response is written and isAbort
+// is true.
+func (h *exitReasonsHandler) getOwnExitReasons(c *gin.Context, id uint64) (*model.ExitReasons, bool) {
+	claim, ok := middleware.GetClaims(c)
+	if !ok {
+		response.Error(c, ecode.Unauthorized)
+		return nil, true
+	}
+
+	ctx := middleware.WrapCtx(c)
+	exitReasons, err := h.iDao.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			logger.Warn("GetByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return nil, true
+	}
+	if exitReasons.UserID == model.ExitReasonsDefaultUserID {
+		logger.Warn("change of a default exitReasons", logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrDefaultExitReasons)
+		return nil, true
+	}
+	if exitReasons.UserID != cast.ToInt(claim.UID) {
+		logger.Warn("exitReasons of another user", logger.Any("id", id), logger.Any("uid", claim.UID), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.Forbidden)
+		return nil, true
+	}
+	return exitReasons, false
+}
+
+func getExitReasonsIDFromPath(c *gin.Context) (string, uint64, bool) {
+	idStr := c.Param("id")
+	id, err := utils.StrToUint64E(idStr)
+	if err != nil || id == 0 {
+		logger.Warn("StrToUint64E error: ", logger.String("idStr", idStr), middleware.GCtxRequestIDField(c))
+		return "", 0, true
+	}
+
+	return idStr, id, false
+}
+
+func convertExitReasons(exitReasons *model.ExitReasons) (*types.ExitReasonsObjDetail, error) {
+	data := &types.ExitReasonsObjDetail{}
+	err := copier.Copy(data, exitReasons)
+	if err != nil {
+		return nil, err
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+
+	return data, nil
+}
+
+func convertExitReasonss(fromValues []*model.ExitReasons) ([]*types.ExitReasonsObjDetail, error) {
+	toValues := []*types.ExitReasonsObjDetail{}
+	for _, v := range fromValues {
+		data, err := convertExitReasons(v)
+		if err != nil {
+			return nil, err
+		}
+		toValues = append(toValues, data)
+	}
+
+	return toValues, nil
+}
@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-dev-frame/sponge/pkg/copier"
+	"github.com/go-dev-frame/sponge/pkg/gotest"
+	"github.com/go-dev-frame/sponge/pkg/httpcli"
+	"github.com/go-dev-frame/sponge/pkg/sgorm/query"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+
+	"helmsman/internal/cache"
+	"helmsman/internal/dao"
+	"helmsman/internal/database"
+	"helmsman/internal/ecode"
+	"helmsman/internal/model"
+	"helmsman/internal/types"
+)
+
+func newExitReasonsHandler() *gotest.Handler {
+	testData := &model.ExitReasons{}
+	testData.ID = 1
+	testData.UserID = 1
+	testData.Code = "news_exit"
+	testData.Name = "News exit"
+	// you can set the other fields of testData here, such as:
+	//testData.CreatedAt = time.Now()
+	//testData.UpdatedAt = testData.CreatedAt
+
+	// init mock cache
+	c := gotest.NewCache(map[string]interface{}{utils.Uint64ToStr(testData.ID): testData})
+	c.ICache = cache.NewExitReasonsCache(&database.CacheType{
+		CType: "redis",
+		Rdb:   c.RedisClient,
+	})
+
+	// init mock dao
+	d := gotest.NewDao(c, testData)
+	d.IDao = dao.NewExitReasonsDao(d.DB, c.ICache.(cache.ExitReasonsCache))
+
+	// init mock handler
+	h := gotest.NewHandler(d, testData)
+	h.IHandler = &exitReasonsHandler{
+		db:        d.DB,
+		iDao:      d.IDao.(dao.ExitReasonsDao),
+		tradesDao: dao.NewTradesDao(d.DB, nil),
+	}
+	iHandler := h.IHandler.(ExitReasonsHandler)
+
+	testFns := []gotest.RouterInfo{
+		{
+			FuncName:    "Create",
+			Method:      http.MethodPost,
+			Path:        "/exitReasons",
+			HandlerFunc: withClaims("1", iHandler.Create),
+		},
+		{
+			FuncName:    "DeleteByID",
+			Method:      http.MethodDelete,
+			Path:        "/exitReasons/:id",
+			HandlerFunc: withClaims("1", iHandler.DeleteByID),
+		},
+		{
+			FuncName:    "UpdateByID",
+			Method:      http.MethodPut,
+			Path:        "/exitReasons/:id",
+			HandlerFunc: withClaims("1", iHandler.UpdateByID),
+		},
+		{
+			FuncName:    "GetByID",
+			Method:      http.MethodGet,
+			Path:        "/exitReasons/:id",
+			HandlerFunc: iHandler.GetByID,
+		},
+		{
+			FuncName:    "List",
+			Method:      http.MethodPost,
+			Path:        "/exitReasons/list",
+			HandlerFunc: iHandler.List,
+		},
+	}
+
+	h.GoRunHTTPServer(testFns)
+
+	time.Sleep(time.Millisecond * 200)
+	return h
+}
+
+func Test_exitReasonsHandler_Create(t *testing.T) {
+	h := newExitReasonsHandler()
+	defer h.Close()
+	testData := &types.CreateExitReasonsRequest{}
+	_ = copier.Copy(testData, h.TestData.(*model.ExitReasons))
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(model.ExitReasonsDefaultUserID, 1).
+		WillReturnRows(newExitReasonsRows())
+	h.MockDao.SQLMock.ExpectBegin()
+	args := h.MockDao.GetAnyArgs(h.TestData)
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").
+		WithArgs(args[:len(args)-1]...). // adjusted for the amount of test data
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &httpcli.StdResult{}
+	err := httpcli.Post(result, h.GetRequestURL("Create"), testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("%+v", result)
+
+}
+
+func Test_exitReasonsHandler_DeleteByID(t *testing.T) {
+	h := newExitReasonsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.ExitReasons)
+	expectedSQLForDeletion := "DELETE .*"
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(testData.ID, 1).
+		WillReturnRows(newExitReasonsRows(testData))
+	h.MockDao.SQLMock.ExpectBegin()
+	// the trades using the code are changed to other in the same transaction
+	h.MockDao.SQLMock.ExpectQuery("SELECT `id` FROM `trades` .*").
+		WithArgs(testData.Code, testData.UserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	h.MockDao.SQLMock.ExpectExec("UPDATE `trades` .*").
+		WithArgs(model.ExitReasonOther, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectExec(expectedSQLForDeletion).
+		WithArgs(testData.ID). // adjusted for the amount of test data
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &httpcli.StdResult{}
+	err := httpcli.Delete(result, h.GetRequestURL("DeleteByID", testData.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// zero id error test
+	err = httpcli.Delete(result, h.GetRequestURL("DeleteByID", 0))
+	assert.NoError(t, err)
+
+	// delete error test
+	err = httpcli.Delete(result, h.GetRequestURL("DeleteByID", 111))
+	assert.Error(t, err)
+}
+
+func Test_exitReasonsHandler_UpdateByID(t *testing.T) {
+	h := newExitReasonsHandler()
+	defer h.Close()
+	testData := &types.UpdateExitReasonsByIDRequest{}
+	_ = copier.Copy(testData, h.TestData.(*model.ExitReasons))
+
+	// the code is unchanged
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(testData.ID, 1).
+		WillReturnRows(newExitReasonsRows(h.TestData.(*model.ExitReasons)))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs(testData.Name, testData.ID). // adjusted for the amount of test data
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &httpcli.StdResult{}
+	err := httpcli.Put(result, h.GetRequestURL("UpdateByID", testData.ID), testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// zero id error test
+	err = httpcli.Put(result, h.GetRequestURL("UpdateByID", 0), testData)
+	assert.NoError(t, err)
+
+	// update error test
+	err = httpcli.Put(result, h.GetRequestURL("UpdateByID", 111), testData)
+	assert.Error(t, err)
+}
+
+func Test_exitReasonsHandler_GetByID(t *testing.T) {
+	h := newExitReasonsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.ExitReasons)
+
+	// column names and corresponding data
+	rows := sqlmock.NewRows([]string{"id"}).
+		AddRow(testData.ID)
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(testData.ID, 1).
+		WillReturnRows(rows)
+
+	result := &httpcli.StdResult{}
+	err := httpcli.Get(result, h.GetRequestURL("GetByID", testData.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// zero id error test
+	err = httpcli.Get(result, h.GetRequestURL("GetByID", 0))
+	assert.NoError(t, err)
+
+	// get error test
+	err = httpcli.Get(result, h.GetRequestURL("GetByID", 111))
+	assert.Error(t, err)
+}
+
+func Test_exitReasonsHandler_List(t *testing.T) {
+	h := newExitReasonsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.ExitReasons)
+
+	// column names and corresponding data
+	rows := sqlmock.NewRows([]string{"id"}).
+		AddRow(testData.ID)
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WillReturnRows(rows)
+
+	result := &httpcli.StdResult{}
+	err := httpcli.Post(result, h.GetRequestURL("List"), &types.ListExitReasonssRequest{Params: query.Params{
+		Page:  0,
+		Limit: 10,
+		Sort:  "ignore count", // ignore test count
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// nil params error test
+	err = httpcli.Post(result, h.GetRequestURL("List"), nil)
+	assert.NoError(t, err)
+
+	// get error test
+	err = httpcli.Post(result, h.GetRequestURL("List"), &types.ListExitReasonssRequest{Params: query.Params{
+		Page:  0,
+		Limit: 10,
+		Sort:  "unknown-column",
+	}})
+	assert.Error(t, err)
+}
+
+func Test_exitReasonsHandler_UpdateCode(t *testing.T) {
+	h := newExitReasonsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.ExitReasons)
+
+	// the changed code is also changed in the trades in the same transaction
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(testData.ID, 1).
+		WillReturnRows(newExitReasonsRows(testData))
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(model.ExitReasonsDefaultUserID, testData.UserID).
+		WillReturnRows(newExitReasonsRows(testData))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE `exit_reasons` .*").
+		WithArgs("news", testData.ID).
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	h.MockDao.SQLMock.ExpectQuery("SELECT `id` FROM `trades` .*").
+		WithArgs(testData.Code, testData.UserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	h.MockDao.SQLMock.ExpectExec("UPDATE `trades` .*").
+		WithArgs("news", 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &httpcli.StdResult{}
+	err := httpcli.Put(result, h.GetRequestURL("UpdateByID", testData.ID), &types.UpdateExitReasonsByIDRequest{Code: "News"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Code)
+
+	// the code is already used by a default exit reason
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(testData.ID, 1).
+		WillReturnRows(newExitReasonsRows(testData))
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(model.ExitReasonsDefaultUserID, testData.UserID).
+		WillReturnRows(newExitReasonsRows(&model.ExitReasons{ID: 3, Code: model.ExitReasonManual}, testData))
+	err = httpcli.Put(result, h.GetRequestURL("UpdateByID", testData.ID), &types.UpdateExitReasonsByIDRequest{Code: "manual"})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrCodeExistsExitReasons.Code(), result.Code)
+
+	assert.NoError(t, h.MockDao.SQLMock.ExpectationsWereMet())
+}
+
+func Test_exitReasonsHandler_Ownership(t *testing.T) {
+	h := newExitReasonsHandler()
+	defer h.Close()
+
+	// a default exit reason
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(3, 1).
+		WillReturnRows(newExitReasonsRows(&model.ExitReasons{ID: 3, Code: model.ExitReasonManual}))
+	result := &httpcli.StdResult{}
+	err := httpcli.Delete(result, h.GetRequestURL("DeleteByID", 3))
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrDefaultExitReasons.Code(), result.Code)
+
+	// an exit reason of another user
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(4, 1).
+		WillReturnRows(newExitReasonsRows(&model.ExitReasons{ID: 4, UserID: 9, Code: "news_exit"}))
+	err = httpcli.Put(result, h.GetRequestURL("UpdateByID", 4), &types.UpdateExitReasonsByIDRequest{Name: "News"})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.Forbidden.Code(), result.Code)
+}
+
+func TestNewExitReasonsHandler(t *testing.T) {
+	defer func() {
+		recover()
+	}()
+	_ = NewExitReasonsHandler()
+}
+
+func newExitReasonsRows(values ...*model.ExitReasons) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "user_id", "code", "name"})
+	for _, v := range values {
+		rows.AddRow(v.ID, v.UserID, v.Code, v.Name)
+	}
+	return rows
+}
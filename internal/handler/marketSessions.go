@@ -0,0 +1,410 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+
+	"github.com/go-dev-frame/sponge/pkg/copier"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+	"github.com/go-dev-frame/sponge/pkg/gin/response"
+	"github.com/go-dev-frame/sponge/pkg/logger"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+
+	"helmsman/internal/cache"
+	"helmsman/internal/dao"
+	"helmsman/internal/database"
+	"helmsman/internal/ecode"
+	"helmsman/internal/model"
+	"helmsman/internal/stats"
+	"helmsman/internal/types"
+)
+
+var _ MarketSessionsHandler = (*marketSessionsHandler)(nil)
+
+// MarketSessionsHandler defining the handler interface
+type MarketSessionsHandler interface {
+	Create(c *gin.Context)
+	DeleteByID(c *gin.Context)
+	UpdateByID(c *gin.Context)
+	GetByID(c *gin.Context)
+	List(c *gin.Context)
+	ListAll(c *gin.Context)
+}
+
+type marketSessionsHandler struct {
+	iDao dao.MarketSessionsDao
+}
+
+// NewMarketSessionsHandler creating the handler interface
+func NewMarketSessionsHandler() MarketSessionsHandler {
+	return &marketSessionsHandler{
+		iDao: dao.NewMarketSessionsDao(
+			database.GetDB(), // db driver is sqlite
+			cache.NewMarketSessionsCache(database.GetCacheType()),
+		),
+	}
+}
+
+// Create a new marketSessions
+// @Summary Create a new marketSessions
+// @Description Creates a new marketSessions entity using the provided data in the request body.
+// @Tags marketSessions
+// @Accept json
+// @Produce json
+// @Param data body types.CreateMarketSessionsRequest true "marketSessions information"
+// @Success 200 {object} types.CreateMarketSessionsReply{}
+// @Router /api/v1/marketSessions [post]
+// @Security BearerAuth
+func (h *marketSessionsHandler) Create(c *gin.Context) {
+	form := &types.CreateMarketSessionsRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	marketSessions := &model.MarketSessions{}
+	err = copier.Copy(marketSessions, form)
+	if err != nil {
+		response.Error(c, ecode.ErrCreateMarketSessions)
+		return
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+	marketSessions.CreatedAt = time.Now().Format("2006-01-02 15:04:05")
+	marketSessions.UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+	claim, ok := middleware.GetClaims(c)
+	if !ok {
+		response.Error(c, ecode.Unauthorized)
+		return
+	}
+	marketSessions.UserID = cast.ToInt(claim.UID)
+	if isAbort := checkMarketSessions(c, marketSessions); isAbort {
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err = h.iDao.Create(ctx, marketSessions)
+	if err != nil {
+		logger.Error("Create error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	response.Success(c, gin.H{"id": marketSessions.ID})
+}
+
+// DeleteByID delete a marketSessions by id
+// @Summary Delete a marketSessions by id
+// @Description Deletes a existing marketSessions identified by the given id in the path.
+// @Tags marketSessions
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.DeleteMarketSessionsByIDReply{}
+// @Router /api/v1/marketSessions/{id} [delete]
+// @Security BearerAuth
+func (h *marketSessionsHandler) DeleteByID(c *gin.Context) {
+	_, id, isAbort := getMarketSessionsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	_, isAbort = h.getOwnMarketSessions(c, id)
+	if isAbort {
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err := h.iDao.DeleteByID(ctx, id)
+	if err != nil {
+		logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	response.Success(c)
+}
+
+// UpdateByID update a marketSessions by id
+// @Summary Update a marketSessions by id
+// @Description Updates the specified marketSessions by given id in the path, support partial update.
+// @Tags marketSessions
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param data body types.UpdateMarketSessionsByIDRequest true "marketSessions information"
+// @Success 200 {object} types.UpdateMarketSessionsByIDReply{}
+// @Router /api/v1/marketSessions/{id} [put]
+// @Security BearerAuth
+func (h *marketSessionsHandler) UpdateByID(c *gin.Context) {
+	_, id, isAbort := getMarketSessionsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	form := &types.UpdateMarketSessionsByIDRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	form.ID = id
+
+	marketSessions := &model.MarketSessions{}
+	err = copier.Copy(marketSessions, form)
+	if err != nil {
+		response.Error(c, ecode.ErrUpdateByIDMarketSessions)
+		return
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+	marketSessions.UpdatedAt = time.Now().Format("2006-01-02 15:04:05")
+
+	current, isAbort := h.getOwnMarketSessions(c, id)
+	if isAbort {
+		return
+	}
+	// the window is checked as a whole with the unchanged fields
+	merged := *current
+	if marketSessions.Name != "" {
+		merged.Name = marketSessions.Name
+	}
+	if marketSessions.Timezone != "" {
+		merged.Timezone = marketSessions.Timezone
+	}
+	if marketSessions.OpenTime != "" {
+		merged.OpenTime = marketSessions.OpenTime
+	}
+	if marketSessions.CloseTime != "" {
+		merged.CloseTime = marketSessions.CloseTime
+	}
+	if isAbort = checkMarketSessions(c, &merged); isAbort {
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	err = h.iDao.UpdateByID(ctx, marketSessions)
+	if err != nil {
+		logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	response.Success(c)
+}
+
+// GetByID get a marketSessions by id
+// @Summary Get a marketSessions by id
+// @Description Gets detailed information of a marketSessions specified by the given id in the path.
+// @Tags marketSessions
+// @Param id path string true "id"
+// @Accept json
+// @Produce json
+// @Success 200 {object} types.GetMarketSessionsByIDReply{}
+// @Router /api/v1/marketSessions/{id} [get]
+// @Security BearerAuth
+func (h *marketSessionsHandler) GetByID(c *gin.Context) {
+	_, id, isAbort := getMarketSessionsIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	marketSessions, err := h.iDao.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			logger.Warn("GetByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	data := &types.MarketSessionsObjDetail{}
+	err = copier.Copy(data, marketSessions)
+	if err != nil {
+		response.Error(c, ecode.ErrGetByIDMarketSessions)
+		return
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+
+	response.Success(c, gin.H{"marketSessions": data})
+}
+
+// List get a paginated list of marketSessionss by custom conditions
+// @Summary Get a paginated list of marketSessionss by custom conditions
+// @Description Returns a paginated list of marketSessions based on query filters, including page number and size.
+// @Tags marketSessions
+// @Accept json
+// @Produce json
+// @Param data body types.Params true "query parameters"
+// @Success 200 {object} types.ListMarketSessionssReply{}
+// @Router /api/v1/marketSessions/list [post]
+// @Security BearerAuth
+func (h *marketSessionsHandler) List(c *gin.Context) {
+	form := &types.ListMarketSessionssRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	marketSessionss, total, err := h.iDao.GetByColumns(ctx, &form.Params)
+	if err != nil {
+		logger.Error("GetByColumns error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertMarketSessionss(marketSessionss)
+	if err != nil {
+		response.Error(c, ecode.ErrListMarketSessions)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"marketSessionss": data,
+		"total":           total,
+	})
+}
+
+// ListAll get all market sessions of the current user
+// @Summary Get all marketSessions of the current user
+// @Description Returns the market sessions of the current user used by the time analysis, if the user has none the analysis uses Asia/London/New York sessions.
+// @Tags marketSessions
+// @Accept json
+// @Produce json
+// @Success 200 {object} types.ListMarketSessionssReply{}
+// @Router /api/v1/marketSessions/list/all [get]
+// @Security BearerAuth
+func (h *marketSessionsHandler) ListAll(c *gin.Context) {
+	ctx := middleware.WrapCtx(c)
+	claim, ok := middleware.GetClaims(c)
+	if !ok {
+		response.Error(c, ecode.Unauthorized)
+		return
+	}
+	userID := cast.ToInt(claim.UID)
+	marketSessionss, err := h.iDao.GetByUserID(ctx, uint64(userID))
+	if err != nil {
+		logger.Error("GetByUserID error", logger.Err(err), logger.Any("userID", userID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	data, err := convertMarketSessionss(marketSessionss)
+	if err != nil {
+		response.Error(c, ecode.ErrListMarketSessions)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"marketSessionss": data,
+	})
+}
+
+// getOwnMarketSessions get a market session of the current user, sessions of other users cannot
+// be changed. If an error occurs, the response is written and isAbort is true.
+func (h *marketSessionsHandler) getOwnMarketSessions(c *gin.Context, id uint64) (*model.MarketSessions, bool) {
+	claim, ok := middleware.GetClaims(c)
+	if !ok {
+		response.Error(c, ecode.Unauthorized)
+		return nil, true
+	}
+
+	ctx := middleware.WrapCtx(c)
+	marketSessions, err := h.iDao.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			logger.Warn("GetByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return nil, true
+	}
+	if marketSessions.UserID != cast.ToInt(claim.UID) {
+		logger.Warn("marketSessions of another user", logger.Any("id", id), logger.Any("uid", claim.UID), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.Forbidden)
+		return nil, true
+	}
+	return marketSessions, false
+}
+
+// checkMarketSessions check that a market session is a valid session window. If it is not, the
+// response is written and isAbort is true.
+func checkMarketSessions(c *gin.Context, marketSessions *model.MarketSessions) bool {
+	_, err := stats.NewSession(marketSessionsConfig(marketSessions), time.UTC)
+	if err != nil {
+		logger.Warn("NewSession error: ", logger.Err(err), logger.Any("marketSessions", marketSessions), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrSessionMarketSessions.WithDetails(err.Error()))
+		return true
+	}
+	return false
+}
+
+func marketSessionsConfig(marketSessions *model.MarketSessions) stats.SessionConfig {
+	return stats.SessionConfig{
+		Name:     marketSessions.Name,
+		Timezone: marketSessions.Timezone,
+		Open:     marketSessions.OpenTime,
+		Close:    marketSessions.CloseTime,
+	}
+}
+
+// marketSessionsConfigs session configs of the market sessions of a user, empty if the user has
+// none so that the default sessions are used
+func marketSessionsConfigs(marketSessionss []*model.MarketSessions) []stats.SessionConfig {
+	configs := make([]stats.SessionConfig, 0, len(marketSessionss))
+	for _, v := range marketSessionss {
+		configs = append(configs, marketSessionsConfig(v))
+	}
+	return configs
+}
+
+func getMarketSessionsIDFromPath(c *gin.Context) (string, uint64, bool) {
+	idStr := c.Param("id")
+	id, err := utils.StrToUint64E(idStr)
+	if err != nil || id == 0 {
+		logger.Warn("StrToUint64E error: ", logger.String("idStr", idStr), middleware.GCtxRequestIDField(c))
+		return "", 0, true
+	}
+
+	return idStr, id, false
+}
+
+func convertMarketSessions(marketSessions *model.MarketSessions) (*types.MarketSessionsObjDetail, error) {
+	data := &types.MarketSessionsObjDetail{}
+	err := copier.Copy(data, marketSessions)
+	if err != nil {
+		return nil, err
+	}
+	// Note: if copier.Copy cannot assign a value to a field, add it here
+
+	return data, nil
+}
+
+func convertMarketSessionss(fromValues []*model.MarketSessions) ([]*types.MarketSessionsObjDetail, error) {
+	toValues := []*types.MarketSessionsObjDetail{}
+	for _, v := range fromValues {
+		data, err := convertMarketSessions(v)
+		if err != nil {
+			return nil, err
+		}
+		toValues = append(toValues, data)
+	}
+
+	return toValues, nil
+}
@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-dev-frame/sponge/pkg/copier"
+	"github.com/go-dev-frame/sponge/pkg/gotest"
+	"github.com/go-dev-frame/sponge/pkg/httpcli"
+	"github.com/go-dev-frame/sponge/pkg/jwt"
+	"github.com/go-dev-frame/sponge/pkg/sgorm/query"
+	"github.com/go-dev-frame/sponge/pkg/utils"
+
+	"helmsman/internal/cache"
+	"helmsman/internal/dao"
+	"helmsman/internal/database"
+	"helmsman/internal/ecode"
+	"helmsman/internal/model"
+	"helmsman/internal/types"
+)
+
+func newMarketSessionsHandler() *gotest.Handler {
+	testData := &model.MarketSessions{}
+	testData.ID = 1
+	testData.UserID = 1
+	testData.Name = "London"
+	testData.Timezone = "Europe/London"
+	testData.OpenTime = "08:00"
+	testData.CloseTime = "16:30"
+	// you can set the other fields of testData here, such as:
+	//testData.CreatedAt = time.Now()
+	//testData.UpdatedAt = testData.CreatedAt
+
+	// init mock cache
+	c := gotest.NewCache(map[string]interface{}{utils.Uint64ToStr(testData.ID): testData})
+	c.ICache = cache.NewMarketSessionsCache(&database.CacheType{
+		CType: "redis",
+		Rdb:   c.RedisClient,
+	})
+
+	// init mock dao
+	d := gotest.NewDao(c, testData)
+	d.IDao = dao.NewMarketSessionsDao(d.DB, c.ICache.(cache.MarketSessionsCache))
+
+	// init mock handler
+	h := gotest.NewHandler(d, testData)
+	h.IHandler = &marketSessionsHandler{iDao: d.IDao.(dao.MarketSessionsDao)}
+	iHandler := h.IHandler.(MarketSessionsHandler)
+
+	testFns := []gotest.RouterInfo{
+		{
+			FuncName:    "Create",
+			Method:      http.MethodPost,
+			Path:        "/marketSessions",
+			HandlerFunc: withClaims("1", iHandler.Create),
+		},
+		{
+			FuncName:    "DeleteByID",
+			Method:      http.MethodDelete,
+			Path:        "/marketSessions/:id",
+			HandlerFunc: withClaims("1", iHandler.DeleteByID),
+		},
+		{
+			FuncName:    "UpdateByID",
+			Method:      http.MethodPut,
+			Path:        "/marketSessions/:id",
+			HandlerFunc: withClaims("1", iHandler.UpdateByID),
+		},
+		{
+			FuncName:    "GetByID",
+			Method:      http.MethodGet,
+			Path:        "/marketSessions/:id",
+			HandlerFunc: iHandler.GetByID,
+		},
+		{
+			FuncName:    "List",
+			Method:      http.MethodPost,
+			Path:        "/marketSessions/list",
+			HandlerFunc: iHandler.List,
+		},
+	}
+
+	h.GoRunHTTPServer(testFns)
+
+	time.Sleep(time.Millisecond * 200)
+	return h
+}
+
+func Test_marketSessionsHandler_Create(t *testing.T) {
+	h := newMarketSessionsHandler()
+	defer h.Close()
+	testData := &types.CreateMarketSessionsRequest{}
+	_ = copier.Copy(testData, h.TestData.(*model.MarketSessions))
+
+	h.MockDao.SQLMock.ExpectBegin()
+	args := h.MockDao.GetAnyArgs(h.TestData)
+	h.MockDao.SQLMock.ExpectExec("INSERT INTO .*").
+		WithArgs(args[:len(args)-1]...). // adjusted for the amount of test data
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &httpcli.StdResult{}
+	err := httpcli.Post(result, h.GetRequestURL("Create"), testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("%+v", result)
+
+}
+
+func Test_marketSessionsHandler_DeleteByID(t *testing.T) {
+	h := newMarketSessionsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.MarketSessions)
+	expectedSQLForDeletion := "DELETE .*"
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(testData.ID, 1).
+		WillReturnRows(newMarketSessionsRows(testData))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec(expectedSQLForDeletion).
+		WithArgs(testData.ID). // adjusted for the amount of test data
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &httpcli.StdResult{}
+	err := httpcli.Delete(result, h.GetRequestURL("DeleteByID", testData.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// zero id error test
+	err = httpcli.Delete(result, h.GetRequestURL("DeleteByID", 0))
+	assert.NoError(t, err)
+
+	// delete error test
+	err = httpcli.Delete(result, h.GetRequestURL("DeleteByID", 111))
+	assert.Error(t, err)
+}
+
+func Test_marketSessionsHandler_UpdateByID(t *testing.T) {
+	h := newMarketSessionsHandler()
+	defer h.Close()
+	testData := &types.UpdateMarketSessionsByIDRequest{}
+	_ = copier.Copy(testData, h.TestData.(*model.MarketSessions))
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(testData.ID, 1).
+		WillReturnRows(newMarketSessionsRows(h.TestData.(*model.MarketSessions)))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs(testData.CloseTime, testData.Name, testData.OpenTime, testData.Timezone, sqlmock.AnyArg(), testData.ID). // adjusted for the amount of test data
+		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
+	h.MockDao.SQLMock.ExpectCommit()
+
+	result := &httpcli.StdResult{}
+	err := httpcli.Put(result, h.GetRequestURL("UpdateByID", testData.ID), testData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// zero id error test
+	err = httpcli.Put(result, h.GetRequestURL("UpdateByID", 0), testData)
+	assert.NoError(t, err)
+
+	// update error test
+	err = httpcli.Put(result, h.GetRequestURL("UpdateByID", 111), testData)
+	assert.Error(t, err)
+}
+
+func Test_marketSessionsHandler_GetByID(t *testing.T) {
+	h := newMarketSessionsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.MarketSessions)
+
+	// column names and corresponding data
+	rows := sqlmock.NewRows([]string{"id"}).
+		AddRow(testData.ID)
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").
+		WithArgs(testData.ID, 1).
+		WillReturnRows(rows)
+
+	result := &httpcli.StdResult{}
+	err := httpcli.Get(result, h.GetRequestURL("GetByID", testData.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// zero id error test
+	err = httpcli.Get(result, h.GetRequestURL("GetByID", 0))
+	assert.NoError(t, err)
+
+	// get error test
+	err = httpcli.Get(result, h.GetRequestURL("GetByID", 111))
+	assert.Error(t, err)
+}
+
+func Test_marketSessionsHandler_List(t *testing.T) {
+	h := newMarketSessionsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.MarketSessions)
+
+	// column names and corresponding data
+	rows := sqlmock.NewRows([]string{"id"}).
+		AddRow(testData.ID)
+
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WillReturnRows(rows)
+
+	result := &httpcli.StdResult{}
+	err := httpcli.Post(result, h.GetRequestURL("List"), &types.ListMarketSessionssRequest{Params: query.Params{
+		Page:  0,
+		Limit: 10,
+		Sort:  "ignore count", // ignore test count
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("%+v", result)
+	}
+
+	// nil params error test
+	err = httpcli.Post(result, h.GetRequestURL("List"), nil)
+	assert.NoError(t, err)
+
+	// get error test
+	err = httpcli.Post(result, h.GetRequestURL("List"), &types.ListMarketSessionssRequest{Params: query.Params{
+		Page:  0,
+		Limit: 10,
+		Sort:  "unknown-column",
+	}})
+	assert.Error(t, err)
+}
+
+func Test_marketSessionsHandler_Ownership(t *testing.T) {
+	h := newMarketSessionsHandler()
+	defer h.Close()
+	testData := h.TestData.(*model.MarketSessions)
+
+	// a session of another user
+	other := &model.MarketSessions{ID: 2, UserID: 9, Name: "Asia", OpenTime: "09:00", CloseTime: "15:00"}
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(other.ID, 1).WillReturnRows(newMarketSessionsRows(other))
+
+	result := &httpcli.StdResult{}
+	err := httpcli.Delete(result, h.GetRequestURL("DeleteByID", other.ID))
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.Forbidden.Code(), result.Code)
+
+	// an invalid window is rejected before it is written
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(testData.ID, 1).WillReturnRows(newMarketSessionsRows(testData))
+	err = httpcli.Put(result, h.GetRequestURL("UpdateByID", testData.ID), &types.UpdateMarketSessionsByIDRequest{OpenTime: "8am"})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrSessionMarketSessions.Code(), result.Code)
+}
+
+func TestNewMarketSessionsHandler(t *testing.T) {
+	defer func() {
+		recover()
+	}()
+	_ = NewMarketSessionsHandler()
+}
+
+// withClaims set the claims of a logged in user as the jwt auth middleware does
+func withClaims(uid string, fn gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("claims", &jwt.Claims{UID: uid})
+		fn(c)
+	}
+}
+
+func newMarketSessionsRows(v *model.MarketSessions) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "user_id", "name", "timezone", "open_time", "close_time"}).
+		AddRow(v.ID, v.UserID, v.Name, v.Timezone, v.OpenTime, v.CloseTime)
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"sort"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -18,6 +19,7 @@ import (
 	"helmsman/internal/database"
 	"helmsman/internal/ecode"
 	"helmsman/internal/model"
+	"helmsman/internal/stats"
 	"helmsman/internal/types"
 )
 
@@ -31,10 +33,13 @@ type StrategiesHandler interface {
 	GetByID(c *gin.Context)
 	List(c *gin.Context)
 	GetAll(c *gin.Context)
+	GetReport(c *gin.Context)
+	GetSignificance(c *gin.Context)
 }
 
 type strategiesHandler struct {
-	iDao dao.StrategiesDao
+	iDao      dao.StrategiesDao
+	tradesDao dao.TradesDao
 }
 
 // NewStrategiesHandler creating the handler interface
@@ -44,6 +49,10 @@ func NewStrategiesHandler() StrategiesHandler {
 			database.GetDB(), // db driver is sqlite
 			cache.NewStrategiesCache(database.GetCacheType()),
 		),
+		tradesDao: dao.NewTradesDao(
+			database.GetDB(), // db driver is sqlite
+			cache.NewTradesCache(database.GetCacheType()),
+		),
 	}
 }
 
@@ -274,6 +283,147 @@ func (h *strategiesHandler) GetAll(c *gin.Context) {
 	})
 }
 
+// GetReport compare the performance of all strategies of the current user
+// @Summary Compare the performance of strategies
+// @Description Groups the closed trades of the current user by strategy and returns trade count, win rate, expectancy in R, profit factor, average holding time and pnl per strategy, trades without a strategy of the user are reported as unassigned.
+// @Tags strategies
+// @Accept json
+// @Produce json
+// @Param accountID query int false "account id"
+// @Param startTime query string false "start of actual exit time, e.g. 2024-01-01"
+// @Param endTime query string false "end of actual exit time, a date without time includes the whole day"
+// @Success 200 {object} types.GetStrategiesReportReply{}
+// @Router /api/v1/strategies/report [get]
+// @Security BearerAuth
+func (h *strategiesHandler) GetReport(c *gin.Context) {
+	form := &types.GetStrategiesReportRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	strategies, groups, isAbort := h.getTradesByStrategy(c, form.AccountID, &form.TimeRangeParams)
+	if isAbort {
+		return
+	}
+
+	items := make([]*types.StrategiesReportItem, 0, len(strategies))
+	for _, v := range strategies {
+		items = append(items, &types.StrategiesReportItem{
+			StrategyID: v.ID,
+			Name:       v.Name,
+			Summary:    *stats.Summarize(groups[v.ID]),
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].NetPnl > items[j].NetPnl
+	})
+
+	response.Success(c, gin.H{
+		"strategies": items,
+		"unassigned": &types.StrategiesReportItem{Name: "unassigned", Summary: *stats.Summarize(groups[0])},
+	})
+}
+
+// GetSignificance test whether the edge of every strategy of the current user is statistically significant
+// @Summary Test the statistical significance of the edge of strategies
+// @Description Computes the System Quality Number, a t-test of the mean R against zero and 95% bootstrap intervals of the expectancy in pnl and R of the closed trades of every strategy of the current user, and flags strategies whose edge is not distinguishable from zero or whose sample is too small.
+// @Tags strategies
+// @Accept json
+// @Produce json
+// @Param accountID query int false "account id"
+// @Param startTime query string false "start of actual exit time, e.g. 2024-01-01"
+// @Param endTime query string false "end of actual exit time, a date without time includes the whole day"
+// @Param resamples query int false "bootstrap resamples, default 2000"
+// @Param seed query int false "random seed for reproducible results, 0 means random"
+// @Success 200 {object} types.GetStrategiesSignificanceReply{}
+// @Router /api/v1/strategies/significance [get]
+// @Security BearerAuth
+func (h *strategiesHandler) GetSignificance(c *gin.Context) {
+	form := &types.GetStrategiesSignificanceRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if form.Seed == 0 {
+		form.Seed = time.Now().UnixNano()
+	}
+
+	strategies, groups, isAbort := h.getTradesByStrategy(c, form.AccountID, &form.TimeRangeParams)
+	if isAbort {
+		return
+	}
+
+	items := make([]*types.StrategiesSignificanceItem, 0, len(strategies))
+	for _, v := range strategies {
+		items = append(items, &types.StrategiesSignificanceItem{
+			StrategyID:         v.ID,
+			Name:               v.Name,
+			SignificanceReport: *stats.AnalyzeSignificance(groups[v.ID], form.Resamples, form.Seed),
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].SQN > items[j].SQN
+	})
+
+	response.Success(c, gin.H{
+		"strategies": items,
+		"unassigned": &types.StrategiesSignificanceItem{
+			Name:               "unassigned",
+			SignificanceReport: *stats.AnalyzeSignificance(groups[0], form.Resamples, form.Seed),
+		},
+	})
+}
+
+// getTradesByStrategy get the strategies of the current user and their closed trades in the time range,
+// grouped by strategy id, trades without a strategy of the user are grouped under 0.
+// If an error occurs, the response is written and isAbort is true.
+func (h *strategiesHandler) getTradesByStrategy(c *gin.Context, accountID uint64, params *types.TimeRangeParams) ([]*model.Strategies, map[uint64][]*model.Trades, bool) {
+	timeRange, err := stats.ParseTimeRange(params.StartTime, params.EndTime, time.Local)
+	if err != nil {
+		logger.Warn("ParseTimeRange error: ", logger.Err(err), logger.Any("params", params), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return nil, nil, true
+	}
+	claim, ok := middleware.GetClaims(c)
+	if !ok {
+		response.Error(c, ecode.Unauthorized)
+		return nil, nil, true
+	}
+	userID := cast.ToInt(claim.UID)
+
+	ctx := middleware.WrapCtx(c)
+	strategies, err := h.iDao.GetAllByUserID(ctx, userID)
+	if err != nil {
+		logger.Error("GetAllByUserID error", logger.Err(err), logger.Any("userID", userID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return nil, nil, true
+	}
+	trades, err := h.tradesDao.GetClosed(ctx, &dao.ClosedTradesCondition{AccountID: accountID, UserID: userID})
+	if err != nil {
+		logger.Error("GetClosed error", logger.Err(err), logger.Any("userID", userID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return nil, nil, true
+	}
+	trades = stats.FilterByExitTime(trades, timeRange, time.Local)
+
+	owned := map[uint64]bool{}
+	for _, v := range strategies {
+		owned[v.ID] = true
+	}
+	groups, _ := stats.GroupBy(trades, func(t *model.Trades) uint64 {
+		if id := uint64(t.StrategyID); owned[id] {
+			return id
+		}
+		return 0
+	})
+	return strategies, groups, false
+}
+
 func getStrategiesIDFromPath(c *gin.Context) (string, uint64, bool) {
 	idStr := c.Param("id")
 	id, err := utils.StrToUint64E(idStr)
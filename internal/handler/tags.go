@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"sort"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -18,6 +19,7 @@ import (
 	"helmsman/internal/database"
 	"helmsman/internal/ecode"
 	"helmsman/internal/model"
+	"helmsman/internal/stats"
 	"helmsman/internal/types"
 )
 
@@ -31,10 +33,13 @@ type TagsHandler interface {
 	GetByID(c *gin.Context)
 	List(c *gin.Context)
 	ListAll(c *gin.Context)
+	GetPerformance(c *gin.Context)
 }
 
 type tagsHandler struct {
-	iDao dao.TagsDao
+	iDao         dao.TagsDao
+	tradesDao    dao.TradesDao
+	tradeTagsDao dao.TradeTagsDao
 }
 
 // NewTagsHandler creating the handler interface
@@ -44,6 +49,14 @@ func NewTagsHandler() TagsHandler {
 			database.GetDB(), // db driver is sqlite
 			cache.NewTagsCache(database.GetCacheType()),
 		),
+		tradesDao: dao.NewTradesDao(
+			database.GetDB(), // db driver is sqlite
+			cache.NewTradesCache(database.GetCacheType()),
+		),
+		tradeTagsDao: dao.NewTradeTagsDao(
+			database.GetDB(), // db driver is sqlite
+			cache.NewTradeTagsCache(database.GetCacheType()),
+		),
 	}
 }
 
@@ -281,6 +294,130 @@ func (h *tagsHandler) ListAll(c *gin.Context) {
 	})
 }
 
+// GetPerformance get the performance of trades per tag and per tag pair
+// @Summary Get the performance per tag and tag pair
+// @Description Joins the closed trades of the current user to their tags and returns trade count, win rate, average R and pnl per tag and per pair of tags.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param accountID query int false "account id"
+// @Param strategyID query int false "strategy id"
+// @Param startTime query string false "start of actual exit time, e.g. 2024-01-01"
+// @Param endTime query string false "end of actual exit time, a date without time includes the whole day"
+// @Param minPairTrades query int false "minimum number of trades of a reported tag pair"
+// @Success 200 {object} types.GetTagsPerformanceReply{}
+// @Router /api/v1/tags/performance [get]
+// @Security BearerAuth
+func (h *tagsHandler) GetPerformance(c *gin.Context) {
+	form := &types.GetTagsPerformanceRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	timeRange, err := stats.ParseTimeRange(form.StartTime, form.EndTime, time.Local)
+	if err != nil {
+		logger.Warn("ParseTimeRange error: ", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if form.MinPairTrades < 1 {
+		form.MinPairTrades = 1
+	}
+	claim, ok := middleware.GetClaims(c)
+	if !ok {
+		response.Error(c, ecode.Unauthorized)
+		return
+	}
+	userID := cast.ToInt(claim.UID)
+
+	ctx := middleware.WrapCtx(c)
+	tagss, err := h.iDao.GetAll(ctx, uint64(userID))
+	if err != nil {
+		logger.Error("GetAll error", logger.Err(err), logger.Any("userID", userID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	tagsByID := map[int]*model.Tags{}
+	tagIDs := make([]int, 0, len(tagss))
+	for _, v := range tagss {
+		tagsByID[int(v.ID)] = v
+		tagIDs = append(tagIDs, int(v.ID))
+	}
+	tradeTags, err := h.tradeTagsDao.GetByTagIDs(ctx, tagIDs)
+	if err != nil {
+		logger.Error("GetByTagIDs error", logger.Err(err), logger.Any("userID", userID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	trades, err := h.tradesDao.GetClosed(ctx, &dao.ClosedTradesCondition{
+		AccountID:  form.AccountID,
+		UserID:     userID,
+		StrategyID: form.StrategyID,
+	})
+	if err != nil {
+		logger.Error("GetClosed error", logger.Err(err), logger.Any("userID", userID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+	trades = stats.FilterByExitTime(trades, timeRange, time.Local)
+
+	tagIDsOfTrade := map[uint64][]int{}
+	for _, v := range tradeTags {
+		tagIDsOfTrade[uint64(v.TradeID)] = append(tagIDsOfTrade[uint64(v.TradeID)], v.TagID)
+	}
+	tagGroups := stats.GroupByMany(trades, func(t *model.Trades) []int {
+		return tagIDsOfTrade[t.ID]
+	})
+	pairGroups := stats.GroupByMany(trades, func(t *model.Trades) [][2]int {
+		return stats.Pairs(tagIDsOfTrade[t.ID])
+	})
+
+	tagItems := make([]*types.TagsPerformanceItem, 0, len(tagGroups))
+	for tagID, group := range tagGroups {
+		tag := tagsByID[tagID]
+		tagItems = append(tagItems, &types.TagsPerformanceItem{
+			TagID:   tag.ID,
+			Name:    tag.Name,
+			Color:   tag.Color,
+			Summary: *stats.Summarize(group),
+		})
+	}
+	pairItems := make([]*types.TagPairsPerformanceItem, 0, len(pairGroups))
+	for pair, group := range pairGroups {
+		if len(group) < form.MinPairTrades {
+			continue
+		}
+		a, b := tagsByID[pair[0]], tagsByID[pair[1]]
+		pairItems = append(pairItems, &types.TagPairsPerformanceItem{
+			TagIDs:  [2]uint64{a.ID, b.ID},
+			Names:   [2]string{a.Name, b.Name},
+			Summary: *stats.Summarize(group),
+		})
+	}
+	sort.Slice(tagItems, func(i, j int) bool {
+		if tagItems[i].NetPnl != tagItems[j].NetPnl {
+			return tagItems[i].NetPnl > tagItems[j].NetPnl
+		}
+		return tagItems[i].TagID < tagItems[j].TagID
+	})
+	sort.Slice(pairItems, func(i, j int) bool {
+		if pairItems[i].NetPnl != pairItems[j].NetPnl {
+			return pairItems[i].NetPnl > pairItems[j].NetPnl
+		}
+		if pairItems[i].TagIDs[0] != pairItems[j].TagIDs[0] {
+			return pairItems[i].TagIDs[0] < pairItems[j].TagIDs[0]
+		}
+		return pairItems[i].TagIDs[1] < pairItems[j].TagIDs[1]
+	})
+
+	response.Success(c, gin.H{
+		"tags":  tagItems,
+		"pairs": pairItems,
+	})
+}
+
 func getTagsIDFromPath(c *gin.Context) (string, uint64, bool) {
 	idStr := c.Param("id")
 	id, err := utils.StrToUint64E(idStr)
@@ -5,10 +5,15 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+
 	"github.com/go-dev-frame/sponge/pkg/copier"
+	"github.com/go-dev-frame/sponge/pkg/errcode"
 	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
 	"github.com/go-dev-frame/sponge/pkg/gin/response"
 	"github.com/go-dev-frame/sponge/pkg/logger"
+	"github.com/go-dev-frame/sponge/pkg/sgorm/query"
 	"github.com/go-dev-frame/sponge/pkg/utils"
 
 	"helmsman/internal/cache"
@@ -16,6 +21,7 @@ import (
 	"helmsman/internal/database"
 	"helmsman/internal/ecode"
 	"helmsman/internal/model"
+	"helmsman/internal/stats"
 	"helmsman/internal/types"
 )
 
@@ -28,25 +34,63 @@ type TradesHandler interface {
 	UpdateByID(c *gin.Context)
 	GetByID(c *gin.Context)
 	List(c *gin.Context)
+	GetSymbolStats(c *gin.Context)
+	GetRDistribution(c *gin.Context)
+	GetSlippage(c *gin.Context)
+	GetExecutionScore(c *gin.Context)
+	GetHoldingPeriods(c *gin.Context)
+	GetMonteCarlo(c *gin.Context)
+	GetRisk(c *gin.Context)
+	GetExcursions(c *gin.Context)
+	UpdateExcursion(c *gin.Context)
+	GetDirections(c *gin.Context)
+	GetRolling(c *gin.Context)
+	Activate(c *gin.Context)
+	Close(c *gin.Context)
+	Cancel(c *gin.Context)
+	Reopen(c *gin.Context)
+	GetPositionSize(c *gin.Context)
 }
 
 type tradesHandler struct {
-	iDao dao.TradesDao
+	db             *gorm.DB
+	iDao           dao.TradesDao
+	executionsDao  dao.ExecutionsDao
+	exitReasonsDao dao.ExitReasonsDao
+	accountsDao    dao.AccountsDao
+	strategiesDao  dao.StrategiesDao
 }
 
 // NewTradesHandler creating the handler interface
 func NewTradesHandler() TradesHandler {
 	return &tradesHandler{
+		db: database.GetDB(),
 		iDao: dao.NewTradesDao(
 			database.GetDB(), // db driver is sqlite
 			cache.NewTradesCache(database.GetCacheType()),
 		),
+		executionsDao: dao.NewExecutionsDao(
+			database.GetDB(), // db driver is sqlite
+			cache.NewExecutionsCache(database.GetCacheType()),
+		),
+		exitReasonsDao: dao.NewExitReasonsDao(
+			database.GetDB(), // db driver is sqlite
+			cache.NewExitReasonsCache(database.GetCacheType()),
+		),
+		accountsDao: dao.NewAccountsDao(
+			database.GetDB(), // db driver is sqlite
+			cache.NewAccountsCache(database.GetCacheType()),
+		),
+		strategiesDao: dao.NewStrategiesDao(
+			database.GetDB(), // db driver is sqlite
+			cache.NewStrategiesCache(database.GetCacheType()),
+		),
 	}
 }
 
 // Create a new trades
 // @Summary Create a new trades
-// @Description Creates a new trades entity using the provided data in the request body.
+// @Description Creates a new trades entity using the provided data in the request body. Unless draft is set, a plan that violates the plan rules or refers to a strategy or account of another user is rejected with the violations per field in the data, see types.TradesPlanErrorReply, a draft is checked once it is finalized or activated. The pnl of a closed trades is computed by the server and stored gross, before commission, its r_multiple stays empty without a stop loss or planned risk amount, overridePnl stores a broker-reported gross pnl instead and requires it.
 // @Tags trades
 // @Accept json
 // @Produce json
@@ -73,6 +117,44 @@ func (h *tradesHandler) Create(c *gin.Context) {
 	trades.CreatedAt = time.Now().Format("2006-01-02 15:04:05")
 	trades.UpdatedAt = trades.CreatedAt
 	// Note: if copier.Copy cannot assign a value to a field, add it here
+	trades.Direction = stats.NormalizeDirection(trades.Direction)
+	if trades.Direction == "" {
+		logger.Warn("unknown direction", logger.String("direction", form.Direction), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrDirectionTrades)
+		return
+	}
+	if trades.Status == "" {
+		trades.Status = model.TradesStatusPlanned
+	}
+	if e := checkTradesPnlOverride(form.Pnl, trades.RMultiple, form.OverridePnl); e != nil {
+		logger.Warn("checkTradesPnlOverride error: ", logger.Err(e.Err()), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, e)
+		return
+	}
+	if e := checkTradesPhase(trades); e != nil {
+		logger.Warn("checkTradesPhase error: ", logger.Err(e.Err()), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, e)
+		return
+	}
+	if !form.Draft && checkTradesPlan(c, h.strategiesDao, h.accountsDao, trades) {
+		return
+	}
+	switch trades.Status {
+	case model.TradesStatusActive:
+		trades.ActivatedAt = trades.CreatedAt
+	case model.TradesStatusClosed:
+		trades.ActivatedAt, trades.ClosedAt = trades.CreatedAt, trades.CreatedAt
+		if e := setTradesResult(trades, form.OverridePnl); e != nil {
+			logger.Warn("setTradesResult error: ", logger.Err(e.Err()), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, e)
+			return
+		}
+	}
+	exitReason, isAbort := h.resolveExitReason(c, trades.ExitReason)
+	if isAbort {
+		return
+	}
+	trades.ExitReason = exitReason
 
 	ctx := middleware.WrapCtx(c)
 	err = h.iDao.Create(ctx, trades)
@@ -87,7 +169,7 @@ func (h *tradesHandler) Create(c *gin.Context) {
 
 // DeleteByID delete a trades by id
 // @Summary Delete a trades by id
-// @Description Deletes a existing trades identified by the given id in the path.
+// @Description Deletes a existing trades identified by the given id in the path together with its executions.
 // @Tags trades
 // @Accept json
 // @Produce json
@@ -103,9 +185,14 @@ func (h *tradesHandler) DeleteByID(c *gin.Context) {
 	}
 
 	ctx := middleware.WrapCtx(c)
-	err := h.iDao.DeleteByID(ctx, id)
+	err := h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := h.executionsDao.DeleteByTradeIDByTx(ctx, tx, id); err != nil {
+			return err
+		}
+		return h.iDao.DeleteByTx(ctx, tx, id)
+	})
 	if err != nil {
-		logger.Error("DeleteByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		logger.Error("DeleteByTx error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
 		response.Output(c, ecode.InternalServerError.ToHTTPCode())
 		return
 	}
@@ -115,7 +202,7 @@ func (h *tradesHandler) DeleteByID(c *gin.Context) {
 
 // UpdateByID update a trades by id
 // @Summary Update a trades by id
-// @Description Updates the specified trades by given id in the path, support partial update.
+// @Description Updates the specified trades by given id in the path, support partial update. An update of the plan of a trades that is not a draft is checked against the plan rules like create, setting draft to false finalizes a draft and checks its plan. The pnl of a closed trades is computed by the server and stored gross, before commission, its r_multiple stays empty without a stop loss or planned risk amount, overridePnl stores a broker-reported gross pnl instead and requires it. The actual entry, actual exit, position size, commission and result of a trades with executions are derived from them and cannot be updated.
 // @Tags trades
 // @Accept json
 // @Produce json
@@ -147,12 +234,104 @@ func (h *tradesHandler) UpdateByID(c *gin.Context) {
 		return
 	}
 	// Note: if copier.Copy cannot assign a value to a field, add it here
+	if trades.Direction != "" {
+		trades.Direction = stats.NormalizeDirection(trades.Direction)
+		if trades.Direction == "" {
+			logger.Warn("unknown direction", logger.String("direction", form.Direction), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrDirectionTrades)
+			return
+		}
+	}
+
+	if e := checkTradesPnlOverride(form.Pnl, trades.RMultiple, form.OverridePnl); e != nil {
+		logger.Warn("checkTradesPnlOverride error: ", logger.Err(e.Err()), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, e)
+		return
+	}
 
 	ctx := middleware.WrapCtx(c)
-	err = h.iDao.UpdateByID(ctx, trades)
+	var current *model.Trades
+	// columns written with their zero values, which UpdateByTx skips
+	columns := map[string]interface{}{}
+	if trades.Status != "" || hasTradesEntry(trades) || hasTradesExit(trades) || changesTradesResult(trades) ||
+		form.OverridePnl || changesTradesPlan(trades) || form.Draft != nil {
+		current, err = h.iDao.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, database.ErrRecordNotFound) {
+				logger.Warn("GetByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+				response.Error(c, ecode.NotFound)
+			} else {
+				logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+				response.Output(c, ecode.InternalServerError.ToHTTPCode())
+			}
+			return
+		}
+		if trades.Status != "" && trades.Status != current.Status {
+			logger.Warn("status changed by update", logger.String("status", trades.Status), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrStatusTrades)
+			return
+		}
+		trades.Status = ""
+		isEntryAllowed := current.Status == model.TradesStatusActive || current.Status == model.TradesStatusClosed
+		isExit := hasTradesExit(trades) || form.Pnl != nil
+		if (hasTradesEntry(trades) && !isEntryAllowed) || (isExit && current.Status != model.TradesStatusClosed) {
+			logger.Warn("fields not allowed in status", logger.String("status", current.Status), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrPhaseTrades)
+			return
+		}
+		// a break-even pnl is only seen by the override
+		if (hasTradesPosition(trades) || form.OverridePnl) && h.checkTradesWithoutExecutions(c, id) {
+			return
+		}
+		// a draft is only checked against the plan rules once it is finalized
+		trades.Draft = current.Draft
+		if form.Draft != nil {
+			trades.Draft = *form.Draft
+		}
+		if trades.Draft != current.Draft {
+			columns["draft"] = trades.Draft
+		}
+		isPlanChecked := !trades.Draft && (current.Draft || changesTradesPlan(trades))
+		if isPlanChecked && checkTradesPlan(c, h.strategiesDao, h.accountsDao, mergeTradesPlanInputs(current, trades)) {
+			return
+		}
+		if current.Status == model.TradesStatusClosed {
+			// recompute the result from the updated trades
+			merged := mergeTradesResultInputs(current, trades)
+			if e := setTradesResult(merged, form.OverridePnl); e != nil {
+				logger.Warn("setTradesResult error: ", logger.Err(e.Err()), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+				response.Error(c, e)
+				return
+			}
+			// a break-even pnl or an empty r_multiple must overwrite the stored result
+			trades.Pnl, trades.RMultiple = 0, 0
+			columns["pnl"], columns["r_multiple"] = merged.Pnl, merged.RMultiple
+		}
+	}
+	exitReason, isAbort := h.resolveExitReason(c, trades.ExitReason)
+	if isAbort {
+		return
+	}
+	trades.ExitReason = exitReason
+
+	err = h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := h.iDao.UpdateByTx(ctx, tx, trades); err != nil {
+			return err
+		}
+		if len(columns) > 0 {
+			return h.iDao.UpdateStatusByTx(ctx, tx, id, current.Status, columns)
+		}
+		return nil
+	})
 	if err != nil {
-		logger.Error("UpdateByID error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
-		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		if errors.Is(err, database.ErrRecordNotFound) {
+			// the status was changed by another request in the meantime
+			logger.Warn("UpdateStatusByTx conflict", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrTransitionTrades)
+		} else {
+			logger.Error("UpdateByTx error", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
 		return
 	}
 
@@ -239,6 +418,1102 @@ func (h *tradesHandler) List(c *gin.Context) {
 	})
 }
 
+// GetSymbolStats get the performance per symbol
+// @Summary Get the performance per symbol
+// @Description Aggregates the closed trades of the current user per symbol into trade count, net pnl, win rate, average R, long vs short split and best/worst trade.
+// @Tags trades
+// @Accept json
+// @Produce json
+// @Param accountID query int false "account id"
+// @Param strategyID query int false "strategy id"
+// @Param startTime query string false "start of actual exit time, e.g. 2024-01-01"
+// @Param endTime query string false "end of actual exit time, a date without time includes the whole day"
+// @Param sort query string false "metric to sort by, a leading - means descending, default -netPnl"
+// @Success 200 {object} types.GetTradesSymbolStatsReply{}
+// @Router /api/v1/trades/symbols [get]
+// @Security BearerAuth
+func (h *tradesHandler) GetSymbolStats(c *gin.Context) {
+	form := &types.GetTradesSymbolStatsRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if form.Sort == "" {
+		form.Sort = "-netPnl"
+	}
+
+	trades, isAbort := h.getClosedTrades(c, form.AccountID, &form.TradesStatsParams, nil)
+	if isAbort {
+		return
+	}
+
+	items := stats.SymbolLeaderboard(trades)
+	err = stats.SortSymbolStats(items, form.Sort)
+	if err != nil {
+		logger.Warn("SortSymbolStats error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	response.Success(c, gin.H{"symbols": items})
+}
+
+// GetRDistribution get the distribution of r_multiple
+// @Summary Get the distribution of r_multiple
+// @Description Returns the histogram, percentiles, mean, standard deviation, skewness and largest winners/losers of the r_multiple of the closed trades of the current user, trades can be filtered with the same conditions as /trades/list.
+// @Tags trades
+// @Accept json
+// @Produce json
+// @Param data body types.GetTradesRDistributionRequest true "filter and histogram parameters"
+// @Success 200 {object} types.GetTradesRDistributionReply{}
+// @Router /api/v1/trades/rDistribution [post]
+// @Security BearerAuth
+func (h *tradesHandler) GetRDistribution(c *gin.Context) {
+	form := &types.GetTradesRDistributionRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if form.Top == 0 {
+		form.Top = 5
+	}
+	edges := form.Edges
+	if len(edges) == 0 {
+		if form.BucketSize == 0 {
+			form.BucketSize = 0.5
+		}
+		if form.Min == 0 && form.Max == 0 {
+			form.Min, form.Max = -3, 5
+		}
+		edges, err = stats.HistogramEdges(form.Min, form.Max, form.BucketSize)
+		if err != nil {
+			logger.Warn("HistogramEdges error: ", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+			return
+		}
+	}
+
+	trades, isAbort := h.getClosedTrades(c, 0, &form.TradesStatsParams, form.Columns)
+	if isAbort {
+		return
+	}
+
+	distribution, err := stats.DistributionOfR(trades, edges, form.Percentiles, form.Top)
+	if err != nil {
+		logger.Warn("DistributionOfR error: ", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	response.Success(c, gin.H{"distribution": distribution})
+}
+
+// GetSlippage get the planned vs actual execution slippage
+// @Summary Get the planned vs actual execution slippage
+// @Description Compares the planned entry, stop loss, take profit and risk of the closed trades of the current user with their actual execution, per trade and aggregated per account and strategy.
+// @Tags trades
+// @Accept json
+// @Produce json
+// @Param accountID query int false "account id"
+// @Param strategyID query int false "strategy id"
+// @Param startTime query string false "start of actual exit time, e.g. 2024-01-01"
+// @Param endTime query string false "end of actual exit time, a date without time includes the whole day"
+// @Success 200 {object} types.GetTradesSlippageReply{}
+// @Router /api/v1/trades/slippage [get]
+// @Security BearerAuth
+func (h *tradesHandler) GetSlippage(c *gin.Context) {
+	form := &types.GetTradesSlippageRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	trades, isAbort := h.getClosedTrades(c, form.AccountID, &form.TradesStatsParams, nil)
+	if isAbort {
+		return
+	}
+
+	response.Success(c, gin.H{"slippage": stats.AnalyzeSlippage(trades)})
+}
+
+// GetExecutionScore get the outcome of trades by execution score
+// @Summary Get the outcome of trades by execution score
+// @Description Groups the closed trades of the current user by execution_score (1-5) and reports the outcome per score, and the correlation between score and r_multiple overall and per week or month.
+// @Tags trades
+// @Accept json
+// @Produce json
+// @Param accountID query int false "account id"
+// @Param strategyID query int false "strategy id"
+// @Param startTime query string false "start of actual exit time, e.g. 2024-01-01"
+// @Param endTime query string false "end of actual exit time, a date without time includes the whole day"
+// @Param period query string false "period of the correlation series, week or month, default month"
+// @Param timezone query string false "IANA time zone, e.g. America/New_York"
+// @Success 200 {object} types.GetTradesExecutionScoreReply{}
+// @Router /api/v1/trades/executionScore [get]
+// @Security BearerAuth
+func (h *tradesHandler) GetExecutionScore(c *gin.Context) {
+	form := &types.GetTradesExecutionScoreRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	loc, err := stats.LoadLocation(form.Timezone)
+	if err != nil {
+		logger.Warn("LoadLocation error: ", logger.Err(err), logger.String("timezone", form.Timezone), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	trades, isAbort := h.getClosedTrades(c, form.AccountID, &form.TradesStatsParams, nil)
+	if isAbort {
+		return
+	}
+
+	report, err := stats.AnalyzeExecutionScore(trades, form.Period, loc)
+	if err != nil {
+		logger.Warn("AnalyzeExecutionScore error: ", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	response.Success(c, gin.H{"executionScore": report})
+}
+
+// GetHoldingPeriods get the performance by holding period
+// @Summary Get the performance by holding period
+// @Description Buckets the closed trades of the current user by the duration between actual entry and exit time (scalp/intraday/swing/position or custom edges) with the expectancy per bucket, trades whose times cannot be parsed are listed separately.
+// @Tags trades
+// @Accept json
+// @Produce json
+// @Param accountID query int false "account id"
+// @Param strategyID query int false "strategy id"
+// @Param startTime query string false "start of actual exit time, e.g. 2024-01-01"
+// @Param endTime query string false "end of actual exit time, a date without time includes the whole day"
+// @Param edges query []int false "ascending bucket edges in minutes" collectionFormat(multi)
+// @Param timezone query string false "IANA time zone, e.g. America/New_York"
+// @Success 200 {object} types.GetTradesHoldingPeriodsReply{}
+// @Router /api/v1/trades/holdingPeriods [get]
+// @Security BearerAuth
+func (h *tradesHandler) GetHoldingPeriods(c *gin.Context) {
+	form := &types.GetTradesHoldingPeriodsRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	loc, err := stats.LoadLocation(form.Timezone)
+	if err != nil {
+		logger.Warn("LoadLocation error: ", logger.Err(err), logger.String("timezone", form.Timezone), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	var buckets []stats.HoldingBucketConfig
+	if len(form.Edges) > 0 {
+		buckets, err = stats.HoldingBucketsFromEdges(form.Edges)
+		if err != nil {
+			logger.Warn("HoldingBucketsFromEdges error: ", logger.Err(err), logger.Any("edges", form.Edges), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+			return
+		}
+	}
+
+	trades, isAbort := h.getClosedTrades(c, form.AccountID, &form.TradesStatsParams, nil)
+	if isAbort {
+		return
+	}
+
+	result, err := stats.AnalyzeHolding(trades, buckets, loc)
+	if err != nil {
+		logger.Warn("AnalyzeHolding error: ", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	response.Success(c, gin.H{"holdingPeriods": result})
+}
+
+// GetMonteCarlo simulate future equity by resampling historical r_multiple
+// @Summary Simulate future equity by resampling historical r_multiple
+// @Description Bootstraps the r_multiple of the closed trades of the current user, optionally of an account or strategy, into equity paths and returns percentile equity bands, the probability of reaching a drawdown threshold and of hitting a profit target.
+// @Tags trades
+// @Accept json
+// @Produce json
+// @Param data body types.GetTradesMonteCarloRequest true "simulation parameters"
+// @Success 200 {object} types.GetTradesMonteCarloReply{}
+// @Router /api/v1/trades/monteCarlo [post]
+// @Security BearerAuth
+func (h *tradesHandler) GetMonteCarlo(c *gin.Context) {
+	form := &types.GetTradesMonteCarloRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	trades, isAbort := h.getClosedTrades(c, form.AccountID, &form.TradesStatsParams, nil)
+	if isAbort {
+		return
+	}
+	rs := make([]float64, 0, len(trades))
+	for _, t := range trades {
+		rs = append(rs, t.RMultiple)
+	}
+
+	result, err := stats.MonteCarlo(rs, stats.MonteCarloConfig{
+		Runs:                form.Runs,
+		Trades:              form.Trades,
+		RiskPercent:         form.RiskPercent,
+		DrawdownPercent:     form.DrawdownPercent,
+		ProfitTargetPercent: form.ProfitTargetPercent,
+		Percentiles:         form.Percentiles,
+		Seed:                form.Seed,
+	})
+	if err != nil {
+		logger.Warn("MonteCarlo error: ", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	response.Success(c, gin.H{"monteCarlo": result})
+}
+
+// GetRisk get the risk of ruin and Kelly fraction
+// @Summary Get the risk of ruin and Kelly fraction
+// @Description Computes the full/half Kelly fraction and the risk of ruin at a per-trade risk percent from the win rate and payoff ratio of the closed trades of the current user, optionally of an account or strategy, with confidence intervals and a warning if the sample is too small.
+// @Tags trades
+// @Accept json
+// @Produce json
+// @Param accountID query int false "account id"
+// @Param strategyID query int false "strategy id"
+// @Param startTime query string false "start of actual exit time, e.g. 2024-01-01"
+// @Param endTime query string false "end of actual exit time, a date without time includes the whole day"
+// @Param riskPercent query number false "percent of equity risked per trade, default 1"
+// @Param ruinPercent query number false "drawdown percent considered ruin, default 50"
+// @Success 200 {object} types.GetTradesRiskReply{}
+// @Router /api/v1/trades/risk [get]
+// @Security BearerAuth
+func (h *tradesHandler) GetRisk(c *gin.Context) {
+	form := &types.GetTradesRiskRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if form.RiskPercent == 0 {
+		form.RiskPercent = 1
+	}
+	if form.RuinPercent == 0 {
+		form.RuinPercent = 50
+	}
+
+	trades, isAbort := h.getClosedTrades(c, form.AccountID, &form.TradesStatsParams, nil)
+	if isAbort {
+		return
+	}
+
+	response.Success(c, gin.H{"risk": stats.AnalyzeRisk(trades, form.RiskPercent, form.RuinPercent)})
+}
+
+// GetExcursions get the MAE/MFE and efficiency metrics
+// @Summary Get the MAE/MFE and efficiency metrics
+// @Description Reports the maximum adverse and favorable excursion in R units and the entry, exit and total efficiency of the closed trades of the current user, per trade and aggregated overall and per strategy, trades without mae/mfe prices are counted but have no metrics.
+// @Tags trades
+// @Accept json
+// @Produce json
+// @Param accountID query int false "account id"
+// @Param strategyID query int false "strategy id"
+// @Param startTime query string false "start of actual exit time, e.g. 2024-01-01"
+// @Param endTime query string false "end of actual exit time, a date without time includes the whole day"
+// @Success 200 {object} types.GetTradesExcursionsReply{}
+// @Router /api/v1/trades/excursions [get]
+// @Security BearerAuth
+func (h *tradesHandler) GetExcursions(c *gin.Context) {
+	form := &types.GetTradesExcursionsRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	trades, isAbort := h.getClosedTrades(c, form.AccountID, &form.TradesStatsParams, nil)
+	if isAbort {
+		return
+	}
+
+	response.Success(c, gin.H{"excursions": stats.AnalyzeExcursion(trades)})
+}
+
+// UpdateExcursion compute the mae and mfe price of a trade from bars
+// @Summary Compute the mae and mfe price of a trade from bars
+// @Description Computes the mae and mfe price of the specified trade from the imported bars between its actual entry and exit time and saves them, the entry and exit price are always part of the price range.
+// @Tags trades
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param data body types.UpdateTradesExcursionRequest true "bars"
+// @Success 200 {object} types.UpdateTradesExcursionReply{}
+// @Router /api/v1/trades/{id}/excursion [post]
+// @Security BearerAuth
+func (h *tradesHandler) UpdateExcursion(c *gin.Context) {
+	_, id, isAbort := getTradesIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	form := &types.UpdateTradesExcursionRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	loc, err := stats.LoadLocation(form.Timezone)
+	if err != nil {
+		logger.Warn("LoadLocation error: ", logger.Err(err), logger.String("timezone", form.Timezone), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	trades, err := h.iDao.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			logger.Warn("GetByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	trades.MaePrice, trades.MfePrice, err = stats.ExcursionFromBars(trades, form.Bars, loc)
+	if err != nil {
+		logger.Warn("ExcursionFromBars error: ", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	err = h.iDao.UpdateByID(ctx, &model.Trades{ID: id, MaePrice: trades.MaePrice, MfePrice: trades.MfePrice})
+	if err != nil {
+		logger.Error("UpdateByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"maePrice":  trades.MaePrice,
+		"mfePrice":  trades.MfePrice,
+		"excursion": stats.Excursion(trades),
+	})
+}
+
+// GetDirections get the long vs short performance
+// @Summary Get the long vs short performance
+// @Description Splits the performance metrics of the closed trades of the current user by direction, overall and per account and strategy, trades with an unknown direction or a pnl/r_multiple that contradicts the direction and prices are listed separately.
+// @Tags trades
+// @Accept json
+// @Produce json
+// @Param accountID query int false "account id"
+// @Param strategyID query int false "strategy id"
+// @Param startTime query string false "start of actual exit time, e.g. 2024-01-01"
+// @Param endTime query string false "end of actual exit time, a date without time includes the whole day"
+// @Success 200 {object} types.GetTradesDirectionsReply{}
+// @Router /api/v1/trades/directions [get]
+// @Security BearerAuth
+func (h *tradesHandler) GetDirections(c *gin.Context) {
+	form := &types.GetTradesDirectionsRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	trades, isAbort := h.getClosedTrades(c, form.AccountID, &form.TradesStatsParams, nil)
+	if isAbort {
+		return
+	}
+
+	response.Success(c, gin.H{"directions": stats.AnalyzeDirection(trades)})
+}
+
+// GetRolling get the rolling window metrics
+// @Summary Get the rolling window metrics
+// @Description Returns the rolling N-trade and N-day series of win rate, expectancy, profit factor and average R of the closed trades of the current user, optionally of an account or strategy, to detect when the edge of a strategy decays.
+// @Tags trades
+// @Accept json
+// @Produce json
+// @Param accountID query int false "account id"
+// @Param strategyID query int false "strategy id"
+// @Param startTime query string false "start of actual exit time, e.g. 2024-01-01"
+// @Param endTime query string false "end of actual exit time, a date without time includes the whole day"
+// @Param trades query int false "size of the rolling trade window, default 20"
+// @Param days query int false "size of the rolling day window, default 30"
+// @Param timezone query string false "IANA time zone, e.g. America/New_York"
+// @Success 200 {object} types.GetTradesRollingReply{}
+// @Router /api/v1/trades/rolling [get]
+// @Security BearerAuth
+func (h *tradesHandler) GetRolling(c *gin.Context) {
+	form := &types.GetTradesRollingRequest{}
+	err := c.ShouldBindQuery(form)
+	if err != nil {
+		logger.Warn("ShouldBindQuery error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	loc, err := stats.LoadLocation(form.Timezone)
+	if err != nil {
+		logger.Warn("LoadLocation error: ", logger.Err(err), logger.String("timezone", form.Timezone), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	trades, isAbort := h.getClosedTrades(c, form.AccountID, &form.TradesStatsParams, nil)
+	if isAbort {
+		return
+	}
+
+	response.Success(c, gin.H{"rolling": stats.AnalyzeRolling(trades, form.Trades, form.Days, loc)})
+}
+
+// Activate a planned trades
+// @Summary Activate a planned trades
+// @Description Changes the status of the specified planned trades to active with its actual entry price and time, and records the time of the transition. A draft is checked against the plan rules with its actual entry. The entry of a trades with executions is derived from them and cannot be activated.
+// @Tags trades
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param data body types.ActivateTradesRequest true "actual entry"
+// @Success 200 {object} types.TransitionTradesReply{}
+// @Router /api/v1/trades/{id}/activate [post]
+// @Security BearerAuth
+func (h *tradesHandler) Activate(c *gin.Context) {
+	form := &types.ActivateTradesRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	h.transition(c, tradesActionActivate, func(trades *model.Trades, now string) (map[string]interface{}, *errcode.Error) {
+		trades.ActualEntryPrice, trades.ActualEntryTime = form.ActualEntryPrice, form.ActualEntryTime
+		if !hasCompleteTradesEntry(trades) {
+			return nil, ecode.ErrEntryRequiredTrades
+		}
+		return map[string]interface{}{
+			"actual_entry_price": trades.ActualEntryPrice,
+			"actual_entry_time":  trades.ActualEntryTime,
+			"activated_at":       now,
+		}, nil
+	})
+}
+
+// Close an active trades
+// @Summary Close an active trades
+// @Description Changes the status of the specified active trades to closed with its actual exit price and time and optionally the result, and records the time of the transition. The pnl of a closed trades is computed by the server and stored gross, before commission, its r_multiple stays empty without a stop loss or planned risk amount, overridePnl stores a broker-reported gross pnl instead and requires it. The exit of a trades with executions is derived from them and cannot be closed.
+// @Tags trades
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param data body types.CloseTradesRequest true "actual exit and result"
+// @Success 200 {object} types.TransitionTradesReply{}
+// @Router /api/v1/trades/{id}/close [post]
+// @Security BearerAuth
+func (h *tradesHandler) Close(c *gin.Context) {
+	form := &types.CloseTradesRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if e := checkTradesPnlOverride(form.Pnl, form.RMultiple, form.OverridePnl); e != nil {
+		logger.Warn("checkTradesPnlOverride error: ", logger.Err(e.Err()), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, e)
+		return
+	}
+	exitReason, isAbort := h.resolveExitReason(c, form.ExitReason)
+	if isAbort {
+		return
+	}
+
+	h.transition(c, tradesActionClose, func(trades *model.Trades, now string) (map[string]interface{}, *errcode.Error) {
+		trades.ActualExitPrice, trades.ActualExitTime = form.ActualExitPrice, form.ActualExitTime
+		if !hasCompleteTradesExit(trades) {
+			return nil, ecode.ErrExitRequiredTrades
+		}
+		if _, err := stats.HoldingTime(trades, time.Local); err != nil {
+			return nil, ecode.InvalidParams
+		}
+		if form.Commission != 0 {
+			trades.Commission = form.Commission
+		}
+		trades.RMultiple = form.RMultiple
+		if form.Pnl != nil {
+			trades.Pnl = *form.Pnl
+		}
+		if e := setTradesResult(trades, form.OverridePnl); e != nil {
+			return nil, e
+		}
+		columns := map[string]interface{}{
+			"actual_exit_price": trades.ActualExitPrice,
+			"actual_exit_time":  trades.ActualExitTime,
+			"commission":        trades.Commission,
+			"pnl":               trades.Pnl,
+			"r_multiple":        trades.RMultiple,
+			"closed_at":         now,
+		}
+		if exitReason != "" {
+			columns["exit_reason"] = exitReason
+		}
+		return columns, nil
+	})
+}
+
+// Cancel a planned trades
+// @Summary Cancel a planned trades
+// @Description Changes the status of the specified planned trades to cancelled and records the time of the transition.
+// @Tags trades
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.TransitionTradesReply{}
+// @Router /api/v1/trades/{id}/cancel [post]
+// @Security BearerAuth
+func (h *tradesHandler) Cancel(c *gin.Context) {
+	h.transition(c, tradesActionCancel, func(trades *model.Trades, now string) (map[string]interface{}, *errcode.Error) {
+		return map[string]interface{}{"cancelled_at": now}, nil
+	})
+}
+
+// Reopen a closed or cancelled trades
+// @Summary Reopen a closed or cancelled trades
+// @Description Changes the status of the specified closed trades back to active, clearing its actual exit and result, or of a cancelled trades back to planned. The exit of a trades with executions is derived from them and cannot be cleared.
+// @Tags trades
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.TransitionTradesReply{}
+// @Router /api/v1/trades/{id}/reopen [post]
+// @Security BearerAuth
+func (h *tradesHandler) Reopen(c *gin.Context) {
+	h.transition(c, tradesActionReopen, func(trades *model.Trades, now string) (map[string]interface{}, *errcode.Error) {
+		if trades.Status == model.TradesStatusCancelled {
+			return map[string]interface{}{"cancelled_at": ""}, nil
+		}
+		return map[string]interface{}{
+			"actual_exit_price": 0,
+			"actual_exit_time":  "",
+			"pnl":               0,
+			"r_multiple":        0,
+			"exit_reason":       "",
+			"closed_at":         "",
+		}, nil
+	})
+}
+
+// trades transition actions
+const (
+	tradesActionActivate = "activate"
+	tradesActionClose    = "close"
+	tradesActionCancel   = "cancel"
+	tradesActionReopen   = "reopen"
+)
+
+// tradesTransitions status after every transition action by the current status,
+// a status missing for an action means the transition is not allowed
+var tradesTransitions = map[string]map[string]string{
+	tradesActionActivate: {model.TradesStatusPlanned: model.TradesStatusActive},
+	tradesActionClose:    {model.TradesStatusActive: model.TradesStatusClosed},
+	tradesActionCancel:   {model.TradesStatusPlanned: model.TradesStatusCancelled},
+	tradesActionReopen: {
+		model.TradesStatusClosed:    model.TradesStatusActive,
+		model.TradesStatusCancelled: model.TradesStatusPlanned,
+	},
+}
+
+// transition change the status of the trades in the path by action, columns returns the other
+// columns to update or an error if the trades is not ready for the transition.
+func (h *tradesHandler) transition(c *gin.Context, action string,
+	columns func(trades *model.Trades, now string) (map[string]interface{}, *errcode.Error)) {
+	_, id, isAbort := getTradesIDFromPath(c)
+	if isAbort {
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	trades, err := h.iDao.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			logger.Warn("GetByID not found", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+	status, ok := tradesTransitions[action][trades.Status]
+	if !ok {
+		logger.Warn("transition not allowed", logger.String("action", action), logger.String("status", trades.Status), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrTransitionTrades)
+		return
+	}
+
+	// the entry and exit of a trades with executions are derived from them
+	isReopenClosed := action == tradesActionReopen && trades.Status == model.TradesStatusClosed
+	if (action == tradesActionActivate || action == tradesActionClose || isReopenClosed) && h.checkTradesWithoutExecutions(c, id) {
+		return
+	}
+
+	now := time.Now().Format("2006-01-02 15:04:05")
+	fromStatus := trades.Status
+	update, e := columns(trades, now)
+	if e != nil {
+		logger.Warn("transition error: ", logger.Err(e.Err()), logger.String("action", action), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Error(c, e)
+		return
+	}
+	if action == tradesActionActivate && trades.Draft {
+		// a draft is checked against the plan rules with its actual entry once it is activated
+		if checkTradesPlan(c, h.strategiesDao, h.accountsDao, trades) {
+			return
+		}
+		update["draft"] = false
+	}
+	update["status"] = status
+	update["updated_at"] = now
+
+	err = h.iDao.UpdateStatus(ctx, id, fromStatus, update)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			// the status was changed by another request in the meantime
+			logger.Warn("UpdateStatus conflict", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.ErrTransitionTrades)
+		} else {
+			logger.Error("UpdateStatus error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+
+	response.Success(c, gin.H{"status": status, "transitionAt": now})
+}
+
+// checkTradesPhase check that a new trades has the fields required by its status and none
+// of the fields of a later phase
+func checkTradesPhase(trades *model.Trades) *errcode.Error {
+	switch trades.Status {
+	case model.TradesStatusPlanned:
+		if hasTradesEntry(trades) || hasTradesExit(trades) {
+			return ecode.ErrPhaseTrades
+		}
+	case model.TradesStatusActive:
+		if !hasCompleteTradesEntry(trades) {
+			return ecode.ErrEntryRequiredTrades
+		}
+		if hasTradesExit(trades) {
+			return ecode.ErrPhaseTrades
+		}
+	case model.TradesStatusClosed:
+		if !hasCompleteTradesEntry(trades) {
+			return ecode.ErrEntryRequiredTrades
+		}
+		if !hasCompleteTradesExit(trades) {
+			return ecode.ErrExitRequiredTrades
+		}
+		if _, err := stats.HoldingTime(trades, time.Local); err != nil {
+			return ecode.InvalidParams
+		}
+	default:
+		return ecode.InvalidParams
+	}
+	return nil
+}
+
+// checkTradesPnlOverride check that pnl and r_multiple are only provided with override and that an
+// override provides the pnl, so that the stored pnl and the r_multiple computed from it agree, a
+// provided pnl of 0 is a break-even result
+func checkTradesPnlOverride(pnl *float64, rMultiple float64, override bool) *errcode.Error {
+	if !override {
+		if pnl != nil || rMultiple != 0 {
+			return ecode.ErrPnlOverrideTrades
+		}
+		return nil
+	}
+	if pnl == nil {
+		return ecode.ErrPnlRequiredTrades
+	}
+	return nil
+}
+
+// setTradesResult compute the pnl and r_multiple of a closed trades, with override the pnl of the
+// trades is kept and the r_multiple is only computed if it is empty. Without a stop loss or planned
+// risk amount the r_multiple is left empty, only a missing entry or exit is an error.
+func setTradesResult(trades *model.Trades, override bool) *errcode.Error {
+	if override {
+		if trades.RMultiple == 0 {
+			if risk, ok := stats.InitialRisk(trades); ok {
+				trades.RMultiple = stats.NetPnl(trades) / risk
+			}
+		}
+		return nil
+	}
+	pnl, r, err := stats.ComputeResult(trades)
+	if err != nil && !errors.Is(err, stats.ErrRiskInputs) {
+		return ecode.ErrResultTrades.WithDetails(err.Error())
+	}
+	trades.Pnl, trades.RMultiple = pnl, r
+	return nil
+}
+
+// changesTradesResult whether the update changes any input of the pnl and r_multiple
+func changesTradesResult(trades *model.Trades) bool {
+	return trades.Direction != "" || trades.ActualEntryPrice != 0 || trades.ActualExitPrice != 0 ||
+		trades.PositionSize != 0 || trades.Commission != 0 || trades.PlannedStopLoss != 0 ||
+		trades.PlannedRiskAmount != 0 || trades.Pnl != 0 || trades.RMultiple != 0
+}
+
+// mergeTradesResultInputs copy of current with the inputs of the pnl and r_multiple set in update
+func mergeTradesResultInputs(current *model.Trades, update *model.Trades) *model.Trades {
+	merged := *current
+	if update.Direction != "" {
+		merged.Direction = update.Direction
+	}
+	if update.ActualEntryPrice != 0 {
+		merged.ActualEntryPrice = update.ActualEntryPrice
+	}
+	if update.ActualExitPrice != 0 {
+		merged.ActualExitPrice = update.ActualExitPrice
+	}
+	if update.PositionSize != 0 {
+		merged.PositionSize = update.PositionSize
+	}
+	if update.Commission != 0 {
+		merged.Commission = update.Commission
+	}
+	if update.PlannedStopLoss != 0 {
+		merged.PlannedStopLoss = update.PlannedStopLoss
+	}
+	if update.PlannedRiskAmount != 0 {
+		merged.PlannedRiskAmount = update.PlannedRiskAmount
+	}
+	merged.Pnl, merged.RMultiple = update.Pnl, update.RMultiple
+	return &merged
+}
+
+// tradesPlanErrors error of every plan rule
+var tradesPlanErrors = map[string]*errcode.Error{
+	stats.PlanRuleStopSide:        ecode.ErrStopSideTrades,
+	stats.PlanRuleTakeProfitSide:  ecode.ErrTakeProfitSideTrades,
+	stats.PlanRuleRewardRisk:      ecode.ErrRewardRiskTrades,
+	stats.PlanRuleMaxPositionSize: ecode.ErrMaxPositionSizeTrades,
+}
+
+// checkTradesPlan check the plan of the trades against the plan rules with the limits of its
+// strategy and account, which must belong to the user. If a rule is violated, the violations per
+// field are written with ErrPlanTrades. If an error occurs, the response is written and isAbort is true.
+func checkTradesPlan(c *gin.Context, strategiesDao dao.StrategiesDao, accountsDao dao.AccountsDao, trades *model.Trades) bool {
+	ctx := middleware.WrapCtx(c)
+	limits := stats.PlanLimits{}
+	if trades.StrategyID > 0 || trades.AccountID > 0 {
+		claim, ok := middleware.GetClaims(c)
+		if !ok {
+			response.Error(c, ecode.Unauthorized)
+			return true
+		}
+		userID := cast.ToInt(claim.UID)
+
+		if trades.StrategyID > 0 {
+			strategies, err := strategiesDao.GetByID(ctx, uint64(trades.StrategyID))
+			if err != nil && !errors.Is(err, database.ErrRecordNotFound) {
+				logger.Error("GetByID error", logger.Err(err), logger.Any("strategyID", trades.StrategyID), middleware.GCtxRequestIDField(c))
+				response.Output(c, ecode.InternalServerError.ToHTTPCode())
+				return true
+			}
+			if err != nil || strategies.UserID != userID {
+				logger.Warn("unknown strategy", logger.Any("strategyID", trades.StrategyID), logger.Any("uid", claim.UID), middleware.GCtxRequestIDField(c))
+				response.Error(c, ecode.ErrStrategyTrades)
+				return true
+			}
+			limits.MinRewardRisk = strategies.MinRewardRisk
+		}
+		if trades.AccountID > 0 {
+			accounts, err := accountsDao.GetByID(ctx, uint64(trades.AccountID))
+			if err != nil && !errors.Is(err, database.ErrRecordNotFound) {
+				logger.Error("GetByID error", logger.Err(err), logger.Any("accountID", trades.AccountID), middleware.GCtxRequestIDField(c))
+				response.Output(c, ecode.InternalServerError.ToHTTPCode())
+				return true
+			}
+			if err != nil || accounts.UserID != userID {
+				logger.Warn("unknown account", logger.Any("accountID", trades.AccountID), logger.Any("uid", claim.UID), middleware.GCtxRequestIDField(c))
+				response.Error(c, ecode.ErrAccountTrades)
+				return true
+			}
+			limits.MaxPositionSize = accounts.MaxPositionSize
+		}
+	}
+
+	violations := stats.CheckPlan(trades, limits)
+	if len(violations) == 0 {
+		return false
+	}
+	data := make([]*types.TradesPlanViolation, 0, len(violations))
+	for _, v := range violations {
+		e := tradesPlanErrors[v.Rule]
+		data = append(data, &types.TradesPlanViolation{
+			Field: v.Field,
+			Rule:  v.Rule,
+			Code:  e.Code(),
+			Msg:   e.Msg(),
+			Value: v.Value,
+			Limit: v.Limit,
+		})
+	}
+	logger.Warn("plan rules violated", logger.Any("violations", violations), logger.Any("id", trades.ID), middleware.GCtxRequestIDField(c))
+	response.Error(c, ecode.ErrPlanTrades, gin.H{"violations": data})
+	return true
+}
+
+// changesTradesPlan whether the update changes any input of the plan rules
+func changesTradesPlan(trades *model.Trades) bool {
+	return trades.Direction != "" || trades.PlannedEntryPrice != 0 || trades.PlannedStopLoss != 0 ||
+		trades.PlannedTakeProfit != 0 || trades.PositionSize != 0 || trades.ActualEntryPrice != 0 ||
+		trades.AccountID != 0 || trades.StrategyID != 0
+}
+
+// mergeTradesPlanInputs copy of current with the inputs of the plan rules set in update
+func mergeTradesPlanInputs(current *model.Trades, update *model.Trades) *model.Trades {
+	merged := *current
+	if update.Direction != "" {
+		merged.Direction = update.Direction
+	}
+	if update.PlannedEntryPrice != 0 {
+		merged.PlannedEntryPrice = update.PlannedEntryPrice
+	}
+	if update.PlannedStopLoss != 0 {
+		merged.PlannedStopLoss = update.PlannedStopLoss
+	}
+	if update.PlannedTakeProfit != 0 {
+		merged.PlannedTakeProfit = update.PlannedTakeProfit
+	}
+	if update.PositionSize != 0 {
+		merged.PositionSize = update.PositionSize
+	}
+	if update.ActualEntryPrice != 0 {
+		merged.ActualEntryPrice = update.ActualEntryPrice
+	}
+	if update.AccountID != 0 {
+		merged.AccountID = update.AccountID
+	}
+	if update.StrategyID != 0 {
+		merged.StrategyID = update.StrategyID
+	}
+	return &merged
+}
+
+// hasTradesPosition whether any field derived from the executions of a trades is set
+func hasTradesPosition(trades *model.Trades) bool {
+	return trades.ActualEntryPrice != 0 || trades.ActualEntryTime != "" || trades.ActualExitPrice != 0 ||
+		trades.ActualExitTime != "" || trades.PositionSize != 0 || trades.Commission != 0 ||
+		trades.Pnl != 0 || trades.RMultiple != 0
+}
+
+// checkTradesWithoutExecutions check that the trades has no executions, the actual entry, actual exit,
+// position size, commission and result of a trades with executions are derived from them and cannot
+// be set directly.
+// If it has executions, ErrPhaseTrades is written. If an error occurs, the response is written and
+// isAbort is true.
+func (h *tradesHandler) checkTradesWithoutExecutions(c *gin.Context, id uint64) bool {
+	ctx := middleware.WrapCtx(c)
+	executionss, err := h.executionsDao.GetByTradeID(ctx, id)
+	if err != nil {
+		logger.Error("GetByTradeID error", logger.Err(err), logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return true
+	}
+	if len(executionss) > 0 {
+		logger.Warn("fields derived from executions", logger.Any("id", id), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrPhaseTrades)
+		return true
+	}
+	return false
+}
+
+// hasTradesEntry whether any field of the entry phase is set
+func hasTradesEntry(trades *model.Trades) bool {
+	return trades.ActualEntryPrice != 0 || trades.ActualEntryTime != ""
+}
+
+// hasTradesExit whether any field of the exit phase is set
+func hasTradesExit(trades *model.Trades) bool {
+	return trades.ActualExitPrice != 0 || trades.ActualExitTime != "" ||
+		trades.Pnl != 0 || trades.RMultiple != 0 || trades.ExitReason != ""
+}
+
+func hasCompleteTradesEntry(trades *model.Trades) bool {
+	_, err := stats.ParseTime(trades.ActualEntryTime, time.Local)
+	return trades.ActualEntryPrice > 0 && err == nil
+}
+
+func hasCompleteTradesExit(trades *model.Trades) bool {
+	_, err := stats.ParseTime(trades.ActualExitTime, time.Local)
+	return trades.ActualExitPrice > 0 && err == nil
+}
+
+// GetPositionSize compute the position size of a planned trades
+// @Summary Compute the position size of a planned trades
+// @Description Computes the largest position size whose loss at the stop loss is within the risk settings of the account, a percent of its current balance (initial balance plus the net pnl of the closed trades) or a fixed amount, capped at the max position size of the account, and the planned risk amount of that size, to fill in the planned fields of a new trades.
+// @Tags trades
+// @Accept json
+// @Produce json
+// @Param data body types.GetTradesPositionSizeRequest true "planned entry and stop loss"
+// @Success 200 {object} types.GetTradesPositionSizeReply{}
+// @Router /api/v1/trades/size [post]
+// @Security BearerAuth
+func (h *tradesHandler) GetPositionSize(c *gin.Context) {
+	form := &types.GetTradesPositionSizeRequest{}
+	err := c.ShouldBindJSON(form)
+	if err != nil {
+		logger.Warn("ShouldBindJSON error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return
+	}
+	if form.Direction != "" && stats.NormalizeDirection(form.Direction) == "" {
+		logger.Warn("unknown direction", logger.String("direction", form.Direction), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrDirectionTrades)
+		return
+	}
+	claim, ok := middleware.GetClaims(c)
+	if !ok {
+		response.Error(c, ecode.Unauthorized)
+		return
+	}
+
+	ctx := middleware.WrapCtx(c)
+	accounts, err := h.accountsDao.GetByID(ctx, form.AccountID)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			logger.Warn("GetByID not found", logger.Err(err), logger.Any("accountID", form.AccountID), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.NotFound)
+		} else {
+			logger.Error("GetByID error", logger.Err(err), logger.Any("accountID", form.AccountID), middleware.GCtxRequestIDField(c))
+			response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		}
+		return
+	}
+	if accounts.UserID != cast.ToInt(claim.UID) {
+		logger.Warn("account of another user", logger.Any("accountID", form.AccountID), logger.Any("uid", claim.UID), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.NotFound)
+		return
+	}
+
+	trades, err := h.iDao.GetClosed(ctx, &dao.ClosedTradesCondition{AccountID: form.AccountID})
+	if err != nil {
+		logger.Error("GetClosed error", logger.Err(err), logger.Any("accountID", form.AccountID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return
+	}
+
+	sizing, err := stats.SizePosition(accounts, stats.Balance(accounts.InitialBalance, trades), form.Direction,
+		form.EntryPrice, form.StopLoss, form.PointValue, form.QuantityStep)
+	if err != nil {
+		logger.Warn("SizePosition error: ", logger.Err(err), logger.Any("form", form), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrPositionSizeTrades.WithDetails(err.Error()))
+		return
+	}
+	sizing.Symbol = form.Symbol
+
+	response.Success(c, gin.H{"sizing": sizing})
+}
+
+// getClosedTrades get the closed trades of the current user filtered by account, params and
+// custom columns, ordered by exit time. If an error occurs, the response is written and isAbort is true.
+func (h *tradesHandler) getClosedTrades(c *gin.Context, accountID uint64, params *types.TradesStatsParams, columns []query.Column) ([]*model.Trades, bool) {
+	timeRange, err := stats.ParseTimeRange(params.StartTime, params.EndTime, time.Local)
+	if err != nil {
+		logger.Warn("ParseTimeRange error: ", logger.Err(err), logger.Any("params", params), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InvalidParams)
+		return nil, true
+	}
+	if len(columns) > 0 {
+		queryParams := &query.Params{Columns: columns}
+		_, _, err = queryParams.ConvertToGormConditions(query.WithWhitelistNames(model.TradesColumnNames))
+		if err != nil {
+			logger.Warn("ConvertToGormConditions error: ", logger.Err(err), logger.Any("columns", columns), middleware.GCtxRequestIDField(c))
+			response.Error(c, ecode.InvalidParams)
+			return nil, true
+		}
+	}
+	claim, ok := middleware.GetClaims(c)
+	if !ok {
+		response.Error(c, ecode.Unauthorized)
+		return nil, true
+	}
+	userID := cast.ToInt(claim.UID)
+
+	ctx := middleware.WrapCtx(c)
+	trades, err := h.iDao.GetClosed(ctx, &dao.ClosedTradesCondition{
+		AccountID:  accountID,
+		UserID:     userID,
+		StrategyID: params.StrategyID,
+		Columns:    columns,
+	})
+	if err != nil {
+		logger.Error("GetClosed error", logger.Err(err), logger.Any("userID", userID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return nil, true
+	}
+	trades = stats.FilterByExitTime(trades, timeRange, time.Local)
+	stats.SortByExitTime(trades, time.Local)
+
+	return trades, false
+}
+
+// resolveExitReason map an exit reason to a code of the vocabulary of the current user, an empty
+// exit reason is returned as is. If it is not in the vocabulary, the response is written and isAbort is true.
+func (h *tradesHandler) resolveExitReason(c *gin.Context, exitReason string) (string, bool) {
+	if exitReason == "" {
+		return "", false
+	}
+	claim, ok := middleware.GetClaims(c)
+	if !ok {
+		response.Error(c, ecode.Unauthorized)
+		return "", true
+	}
+
+	ctx := middleware.WrapCtx(c)
+	vocabulary, err := h.exitReasonsDao.GetAll(ctx, cast.ToUint64(claim.UID))
+	if err != nil {
+		logger.Error("GetAll error", logger.Err(err), logger.Any("uid", claim.UID), middleware.GCtxRequestIDField(c))
+		response.Output(c, ecode.InternalServerError.ToHTTPCode())
+		return "", true
+	}
+	code, ok := stats.ResolveExitReason(exitReason, vocabulary)
+	if !ok {
+		logger.Warn("unknown exit reason", logger.String("exitReason", exitReason), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.ErrExitReasonTrades)
+		return "", true
+	}
+	return code, false
+}
+
 func getTradesIDFromPath(c *gin.Context) (string, uint64, bool) {
 	idStr := c.Param("id")
 	id, err := utils.StrToUint64E(idStr)
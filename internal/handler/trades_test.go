@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/go-dev-frame/sponge/pkg/copier"
+	"github.com/go-dev-frame/sponge/pkg/errcode"
 	"github.com/go-dev-frame/sponge/pkg/gotest"
 	"github.com/go-dev-frame/sponge/pkg/httpcli"
 	"github.com/go-dev-frame/sponge/pkg/sgorm/query"
@@ -17,6 +18,7 @@ import (
 	"helmsman/internal/cache"
 	"helmsman/internal/dao"
 	"helmsman/internal/database"
+	"helmsman/internal/ecode"
 	"helmsman/internal/model"
 	"helmsman/internal/types"
 )
@@ -41,7 +43,13 @@ func newTradesHandler() *gotest.Handler {
 
 	// init mock handler
 	h := gotest.NewHandler(d, testData)
-	h.IHandler = &tradesHandler{iDao: d.IDao.(dao.TradesDao)}
+	h.IHandler = &tradesHandler{
+		db:            d.DB,
+		iDao:          d.IDao.(dao.TradesDao),
+		executionsDao: dao.NewExecutionsDao(d.DB, nil),
+		accountsDao:   dao.NewAccountsDao(d.DB, nil),
+		strategiesDao: dao.NewStrategiesDao(d.DB, nil),
+	}
 	iHandler := h.IHandler.(TradesHandler)
 
 	testFns := []gotest.RouterInfo{
@@ -49,7 +57,7 @@ func newTradesHandler() *gotest.Handler {
 			FuncName:    "Create",
 			Method:      http.MethodPost,
 			Path:        "/trades",
-			HandlerFunc: iHandler.Create,
+			HandlerFunc: withClaims("1", iHandler.Create),
 		},
 		{
 			FuncName:    "DeleteByID",
@@ -61,7 +69,7 @@ func newTradesHandler() *gotest.Handler {
 			FuncName:    "UpdateByID",
 			Method:      http.MethodPut,
 			Path:        "/trades/:id",
-			HandlerFunc: iHandler.UpdateByID,
+			HandlerFunc: withClaims("1", iHandler.UpdateByID),
 		},
 		{
 			FuncName:    "GetByID",
@@ -75,6 +83,30 @@ func newTradesHandler() *gotest.Handler {
 			Path:        "/trades/list",
 			HandlerFunc: iHandler.List,
 		},
+		{
+			FuncName:    "Activate",
+			Method:      http.MethodPost,
+			Path:        "/trades/:id/activate",
+			HandlerFunc: withClaims("1", iHandler.Activate),
+		},
+		{
+			FuncName:    "Close",
+			Method:      http.MethodPost,
+			Path:        "/trades/:id/close",
+			HandlerFunc: iHandler.Close,
+		},
+		{
+			FuncName:    "Cancel",
+			Method:      http.MethodPost,
+			Path:        "/trades/:id/cancel",
+			HandlerFunc: iHandler.Cancel,
+		},
+		{
+			FuncName:    "Reopen",
+			Method:      http.MethodPost,
+			Path:        "/trades/:id/reopen",
+			HandlerFunc: iHandler.Reopen,
+		},
 	}
 
 	h.GoRunHTTPServer(testFns)
@@ -112,7 +144,14 @@ func Test_tradesHandler_DeleteByID(t *testing.T) {
 	testData := h.TestData.(*model.Trades)
 	expectedSQLForDeletion := "DELETE .*"
 
+	// the executions are deleted with the trades
 	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectQuery("SELECT `id` FROM `executions` .*").
+		WithArgs(testData.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4).AddRow(5))
+	h.MockDao.SQLMock.ExpectExec(expectedSQLForDeletion).
+		WithArgs(4, 5).
+		WillReturnResult(sqlmock.NewResult(0, 2))
 	h.MockDao.SQLMock.ExpectExec(expectedSQLForDeletion).
 		WithArgs(testData.ID). // adjusted for the amount of test data
 		WillReturnResult(sqlmock.NewResult(int64(testData.ID), 1))
@@ -126,6 +165,7 @@ func Test_tradesHandler_DeleteByID(t *testing.T) {
 	if result.Code != 0 {
 		t.Fatalf("%+v", result)
 	}
+	assert.NoError(t, h.MockDao.SQLMock.ExpectationsWereMet())
 
 	// zero id error test
 	err = httpcli.Delete(result, h.GetRequestURL("DeleteByID", 0))
@@ -234,9 +274,323 @@ func Test_tradesHandler_List(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func Test_tradesTransitions(t *testing.T) {
+	statuses := []string{model.TradesStatusPlanned, model.TradesStatusActive, model.TradesStatusClosed, model.TradesStatusCancelled}
+	// status after the action by the current status, an empty string means not allowed
+	tests := []struct {
+		action string
+		want   []string
+	}{
+		{tradesActionActivate, []string{model.TradesStatusActive, "", "", ""}},
+		{tradesActionClose, []string{"", model.TradesStatusClosed, "", ""}},
+		{tradesActionCancel, []string{model.TradesStatusCancelled, "", "", ""}},
+		{tradesActionReopen, []string{"", "", model.TradesStatusActive, model.TradesStatusPlanned}},
+	}
+	for _, tt := range tests {
+		for i, from := range statuses {
+			status, ok := tradesTransitions[tt.action][from]
+			assert.Equal(t, tt.want[i] != "", ok, "%s from %s", tt.action, from)
+			assert.Equal(t, tt.want[i], status, "%s from %s", tt.action, from)
+		}
+	}
+}
+
+func Test_checkTradesPhase(t *testing.T) {
+	entry := model.Trades{ActualEntryPrice: 100, ActualEntryTime: "2024-01-02 09:30:00"}
+	tests := []struct {
+		name   string
+		trades model.Trades
+		want   *errcode.Error
+	}{
+		{"planned", model.Trades{Status: model.TradesStatusPlanned, PlannedEntryPrice: 100}, nil},
+		{"planned with entry", model.Trades{Status: model.TradesStatusPlanned, ActualEntryPrice: 100}, ecode.ErrPhaseTrades},
+		{"planned with exit reason", model.Trades{Status: model.TradesStatusPlanned, ExitReason: "manual"}, ecode.ErrPhaseTrades},
+		{"active", model.Trades{Status: model.TradesStatusActive, ActualEntryPrice: 100, ActualEntryTime: entry.ActualEntryTime}, nil},
+		{"active without entry time", model.Trades{Status: model.TradesStatusActive, ActualEntryPrice: 100}, ecode.ErrEntryRequiredTrades},
+		{"active with exit", model.Trades{Status: model.TradesStatusActive, ActualEntryPrice: 100, ActualEntryTime: entry.ActualEntryTime,
+			ActualExitPrice: 110}, ecode.ErrPhaseTrades},
+		{"closed", model.Trades{Status: model.TradesStatusClosed, ActualEntryPrice: 100, ActualEntryTime: entry.ActualEntryTime,
+			ActualExitPrice: 110, ActualExitTime: "2024-01-02 10:00:00"}, nil},
+		{"closed without entry", model.Trades{Status: model.TradesStatusClosed, ActualExitPrice: 110,
+			ActualExitTime: "2024-01-02 10:00:00"}, ecode.ErrEntryRequiredTrades},
+		{"closed without exit price", model.Trades{Status: model.TradesStatusClosed, ActualEntryPrice: 100, ActualEntryTime: entry.ActualEntryTime,
+			ActualExitTime: "2024-01-02 10:00:00"}, ecode.ErrExitRequiredTrades},
+		{"closed before entry", model.Trades{Status: model.TradesStatusClosed, ActualEntryPrice: 100, ActualEntryTime: entry.ActualEntryTime,
+			ActualExitPrice: 110, ActualExitTime: "2024-01-01 10:00:00"}, ecode.InvalidParams},
+		{"cancelled", model.Trades{Status: model.TradesStatusCancelled}, ecode.InvalidParams},
+		{"unknown status", model.Trades{Status: "open"}, ecode.InvalidParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, checkTradesPhase(&tt.trades))
+		})
+	}
+}
+
+func Test_hasTradesEntryExit(t *testing.T) {
+	tests := []struct {
+		name      string
+		trades    model.Trades
+		wantEntry bool
+		wantExit  bool
+	}{
+		{"empty", model.Trades{PlannedEntryPrice: 100, PlannedStopLoss: 95}, false, false},
+		{"entry price", model.Trades{ActualEntryPrice: 100}, true, false},
+		{"entry time", model.Trades{ActualEntryTime: "2024-01-02 09:30:00"}, true, false},
+		{"exit price", model.Trades{ActualExitPrice: 110}, false, true},
+		{"exit time", model.Trades{ActualExitTime: "2024-01-02 10:00:00"}, false, true},
+		{"pnl", model.Trades{Pnl: 10}, false, true},
+		{"r multiple", model.Trades{RMultiple: 1}, false, true},
+		{"exit reason", model.Trades{ExitReason: "manual"}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantEntry, hasTradesEntry(&tt.trades))
+			assert.Equal(t, tt.wantExit, hasTradesExit(&tt.trades))
+		})
+	}
+}
+
+func Test_checkTradesPnlOverride(t *testing.T) {
+	pnl, breakEven := 100.0, 0.0
+	assert.Nil(t, checkTradesPnlOverride(nil, 0, false))
+	assert.Equal(t, ecode.ErrPnlOverrideTrades, checkTradesPnlOverride(&pnl, 0, false))
+	assert.Equal(t, ecode.ErrPnlOverrideTrades, checkTradesPnlOverride(&breakEven, 0, false))
+	assert.Equal(t, ecode.ErrPnlOverrideTrades, checkTradesPnlOverride(nil, 1, false))
+	assert.Nil(t, checkTradesPnlOverride(&pnl, 0, true))
+	// a break-even pnl reported by the broker
+	assert.Nil(t, checkTradesPnlOverride(&breakEven, 0, true))
+	// an override without pnl would compute the r_multiple from a pnl of 0
+	assert.Equal(t, ecode.ErrPnlRequiredTrades, checkTradesPnlOverride(nil, 0, true))
+	assert.Equal(t, ecode.ErrPnlRequiredTrades, checkTradesPnlOverride(nil, 2, true))
+}
+
+func Test_setTradesResult(t *testing.T) {
+	trades := &model.Trades{Direction: model.TradesDirectionLong, PositionSize: 10, ActualEntryPrice: 100, ActualExitPrice: 110}
+	// without a stop loss or planned risk amount only the pnl is computed
+	assert.Nil(t, setTradesResult(trades, false))
+	assert.Equal(t, 100.0, trades.Pnl)
+	assert.Equal(t, 0.0, trades.RMultiple)
+
+	trades.PlannedStopLoss = 95
+	assert.Nil(t, setTradesResult(trades, false))
+	assert.Equal(t, 100.0, trades.Pnl)
+	assert.Equal(t, 2.0, trades.RMultiple)
+
+	trades.ActualExitPrice = 0
+	assert.Equal(t, ecode.ErrResultTrades.Code(), setTradesResult(trades, false).Code())
+}
+
+func Test_tradesHandler_Lifecycle(t *testing.T) {
+	h := newTradesHandler()
+	defer h.Close()
+	result := &httpcli.StdResult{}
+
+	// the status cannot be changed by an update
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(2, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 2, Status: model.TradesStatusActive}))
+	err := httpcli.Put(result, h.GetRequestURL("UpdateByID", 2), &types.UpdateTradesByIDRequest{Status: model.TradesStatusClosed})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrStatusTrades.Code(), result.Code)
+
+	// exit fields of an active trades
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(3, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 3, Status: model.TradesStatusActive}))
+	err = httpcli.Put(result, h.GetRequestURL("UpdateByID", 3), &types.UpdateTradesByIDRequest{ActualExitPrice: 110})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrPhaseTrades.Code(), result.Code)
+
+	// illegal transition
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(4, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 4, Status: model.TradesStatusClosed}))
+	err = httpcli.Post(result, h.GetRequestURL("Activate", 4), &types.ActivateTradesRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrTransitionTrades.Code(), result.Code)
+
+	// activate without the entry
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(5, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 5, Status: model.TradesStatusPlanned}))
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(5).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	err = httpcli.Post(result, h.GetRequestURL("Activate", 5), &types.ActivateTradesRequest{ActualEntryPrice: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrEntryRequiredTrades.Code(), result.Code)
+
+	// close without the exit
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(6, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 6, Status: model.TradesStatusActive,
+			ActualEntryPrice: 100, ActualEntryTime: "2024-01-02 09:30:00"}))
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(6).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	err = httpcli.Post(result, h.GetRequestURL("Close", 6), &types.CloseTradesRequest{ActualExitTime: "2024-01-02 10:00:00"})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrExitRequiredTrades.Code(), result.Code)
+
+	// a break-even result of a closed trades overwrites the stored result
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(8, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 8, Status: model.TradesStatusClosed, Direction: model.TradesDirectionLong,
+			PlannedStopLoss: 95, PositionSize: 10, ActualEntryPrice: 100, ActualEntryTime: "2024-01-02 09:30:00",
+			ActualExitPrice: 110, ActualExitTime: "2024-01-02 10:00:00"}))
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(8).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs(0.0, 0.0, 8, model.TradesStatusClosed).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+	err = httpcli.Put(result, h.GetRequestURL("UpdateByID", 8), &types.UpdateTradesByIDRequest{ActualExitPrice: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Code)
+
+	// close with a break-even pnl reported by the broker
+	breakEven := 0.0
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(9, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 9, Status: model.TradesStatusActive, Direction: model.TradesDirectionLong,
+			PositionSize: 10, ActualEntryPrice: 100, ActualEntryTime: "2024-01-02 09:30:00"}))
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(9).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs(110.0, "2024-01-02 10:00:00", sqlmock.AnyArg(), 0.0, 0.0, 0.0, model.TradesStatusClosed, sqlmock.AnyArg(), 9, model.TradesStatusActive).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+	err = httpcli.Post(result, h.GetRequestURL("Close", 9), &types.CloseTradesRequest{ActualExitPrice: 110,
+		ActualExitTime: "2024-01-02 10:00:00", Pnl: &breakEven, OverridePnl: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Code)
+
+	// cancel a planned trades
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(7, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 7, Status: model.TradesStatusPlanned}))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs(sqlmock.AnyArg(), model.TradesStatusCancelled, sqlmock.AnyArg(), 7, model.TradesStatusPlanned).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+	err = httpcli.Post(result, h.GetRequestURL("Cancel", 7), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Code)
+
+	assert.NoError(t, h.MockDao.SQLMock.ExpectationsWereMet())
+}
+
+func Test_tradesHandler_WithExecutions(t *testing.T) {
+	h := newTradesHandler()
+	defer h.Close()
+	result := &httpcli.StdResult{}
+	executionsRows := func(tradeID int) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "trade_id", "side", "quantity", "price", "executed_at"}).
+			AddRow(1, tradeID, model.ExecutionsSideBuy, 2, 100, "2024-01-02 09:30:00")
+	}
+
+	// the position size is derived from the executions
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(2, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 2, Status: model.TradesStatusActive, Direction: model.TradesDirectionLong}))
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(2).WillReturnRows(executionsRows(2))
+	err := httpcli.Put(result, h.GetRequestURL("UpdateByID", 2), &types.UpdateTradesByIDRequest{PositionSize: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrPhaseTrades.Code(), result.Code)
+
+	// the commission is derived from the executions
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(5, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 5, Status: model.TradesStatusActive, Direction: model.TradesDirectionLong}))
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(5).WillReturnRows(executionsRows(5))
+	err = httpcli.Put(result, h.GetRequestURL("UpdateByID", 5), &types.UpdateTradesByIDRequest{Commission: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrPhaseTrades.Code(), result.Code)
+
+	// the exit is derived from the executions
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(3, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 3, Status: model.TradesStatusActive, Direction: model.TradesDirectionLong,
+			ActualEntryPrice: 100, ActualEntryTime: "2024-01-02 09:30:00"}))
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(3).WillReturnRows(executionsRows(3))
+	err = httpcli.Post(result, h.GetRequestURL("Close", 3), &types.CloseTradesRequest{ActualExitPrice: 110, ActualExitTime: "2024-01-02 10:00:00"})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrPhaseTrades.Code(), result.Code)
+
+	// the exit derived from the executions cannot be cleared
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(4, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 4, Status: model.TradesStatusClosed, Direction: model.TradesDirectionLong,
+			ActualEntryPrice: 100, ActualEntryTime: "2024-01-02 09:30:00", ActualExitPrice: 110, ActualExitTime: "2024-01-02 10:00:00"}))
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(4).WillReturnRows(executionsRows(4))
+	err = httpcli.Post(result, h.GetRequestURL("Reopen", 4), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrPhaseTrades.Code(), result.Code)
+
+	assert.NoError(t, h.MockDao.SQLMock.ExpectationsWereMet())
+}
+
+func Test_tradesHandler_Plan(t *testing.T) {
+	h := newTradesHandler()
+	defer h.Close()
+	result := &httpcli.StdResult{}
+	finalize := false
+
+	// account of another user
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(3, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "max_position_size"}).AddRow(3, 2, 10))
+	err := httpcli.Post(result, h.GetRequestURL("Create"), &types.CreateTradesRequest{
+		AccountID: 3, Symbol: "BTC", Direction: "long", PlannedEntryPrice: 100, PlannedStopLoss: 95,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrAccountTrades.Code(), result.Code)
+
+	// unknown strategy
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(4, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "min_reward_risk"}))
+	err = httpcli.Post(result, h.GetRequestURL("Create"), &types.CreateTradesRequest{
+		StrategyID: 4, Symbol: "BTC", Direction: "long", PlannedEntryPrice: 100, PlannedStopLoss: 95,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrStrategyTrades.Code(), result.Code)
+
+	// finalizing a draft checks its plan
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(5, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 5, Status: model.TradesStatusPlanned, Direction: model.TradesDirectionLong,
+			PlannedEntryPrice: 100, PlannedStopLoss: 105, Draft: true}))
+	err = httpcli.Put(result, h.GetRequestURL("UpdateByID", 5), &types.UpdateTradesByIDRequest{Draft: &finalize})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrPlanTrades.Code(), result.Code)
+
+	// a valid draft is finalized by clearing the marker
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(6, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 6, Status: model.TradesStatusPlanned, Direction: model.TradesDirectionLong,
+			PlannedEntryPrice: 100, PlannedStopLoss: 95, Draft: true}))
+	h.MockDao.SQLMock.ExpectBegin()
+	h.MockDao.SQLMock.ExpectExec("UPDATE .*").
+		WithArgs(false, 6, model.TradesStatusPlanned).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	h.MockDao.SQLMock.ExpectCommit()
+	err = httpcli.Put(result, h.GetRequestURL("UpdateByID", 6), &types.UpdateTradesByIDRequest{Draft: &finalize})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Code)
+
+	// activating a draft checks its plan with the actual entry
+	h.MockDao.SQLMock.ExpectQuery("SELECT .*").WithArgs(7, 1).
+		WillReturnRows(newTradesRows(&model.Trades{ID: 7, Status: model.TradesStatusPlanned, Direction: model.TradesDirectionLong,
+			PlannedStopLoss: 101, Draft: true}))
+	h.MockDao.SQLMock.ExpectQuery("SELECT .* FROM `executions`").WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	err = httpcli.Post(result, h.GetRequestURL("Activate", 7), &types.ActivateTradesRequest{
+		ActualEntryPrice: 100, ActualEntryTime: "2024-01-02 09:30:00",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, ecode.ErrPlanTrades.Code(), result.Code)
+
+	assert.NoError(t, h.MockDao.SQLMock.ExpectationsWereMet())
+}
+
 func TestNewTradesHandler(t *testing.T) {
 	defer func() {
 		recover()
 	}()
 	_ = NewTradesHandler()
 }
+
+func newTradesRows(values ...*model.Trades) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "account_id", "strategy_id", "status", "direction",
+		"planned_entry_price", "planned_stop_loss", "planned_take_profit", "position_size", "draft",
+		"actual_entry_price", "actual_entry_time", "actual_exit_price", "actual_exit_time"})
+	for _, v := range values {
+		rows.AddRow(v.ID, v.AccountID, v.StrategyID, v.Status, v.Direction,
+			v.PlannedEntryPrice, v.PlannedStopLoss, v.PlannedTakeProfit, v.PositionSize, v.Draft,
+			v.ActualEntryPrice, v.ActualEntryTime, v.ActualExitPrice, v.ActualExitTime)
+	}
+	return rows
+}
@@ -1,22 +1,36 @@
 package model
 
+// accounts risk mode
+const (
+	AccountsRiskModePercent = "percent" // risk a percent of the current balance per trade
+	AccountsRiskModeAmount  = "amount"  // risk a fixed amount per trade
+)
+
 type Accounts struct {
-	ID             uint64  `gorm:"column:id;type:int(11);primary_key" json:"id"`
-	UserID         int     `gorm:"column:user_id;type:int(11);not null" json:"userID"`
-	Name           string  `gorm:"column:name;type:text;not null" json:"name"`
-	InitialBalance float64 `gorm:"column:initial_balance;type:float;not null" json:"initialBalance"`
-	Currency       string  `gorm:"column:currency;type:text" json:"currency"`
-	CreatedAt      string  `gorm:"column:created_at;type:varchar(100)" json:"createdAt"`
-	UpdatedAt      string  `gorm:"column:updated_at;type:varchar(100)" json:"updatedAt"`
+	ID              uint64  `gorm:"column:id;type:int(11);primary_key" json:"id"`
+	UserID          int     `gorm:"column:user_id;type:int(11);not null" json:"userID"`
+	Name            string  `gorm:"column:name;type:text;not null" json:"name"`
+	InitialBalance  float64 `gorm:"column:initial_balance;type:float;not null" json:"initialBalance"`
+	Currency        string  `gorm:"column:currency;type:text" json:"currency"`
+	RiskMode        string  `gorm:"column:risk_mode;type:text" json:"riskMode"`
+	RiskPercent     float64 `gorm:"column:risk_percent;type:float" json:"riskPercent"`
+	RiskAmount      float64 `gorm:"column:risk_amount;type:float" json:"riskAmount"`
+	MaxPositionSize float64 `gorm:"column:max_position_size;type:float" json:"maxPositionSize"`
+	CreatedAt       string  `gorm:"column:created_at;type:varchar(100)" json:"createdAt"`
+	UpdatedAt       string  `gorm:"column:updated_at;type:varchar(100)" json:"updatedAt"`
 }
 
 // AccountsColumnNames Whitelist for custom query fields to prevent sql injection attacks
 var AccountsColumnNames = map[string]bool{
-	"id":              true,
-	"user_id":         true,
-	"name":            true,
-	"initial_balance": true,
-	"currency":        true,
-	"created_at":      true,
-	"updated_at":      true,
+	"id":                true,
+	"user_id":           true,
+	"name":              true,
+	"initial_balance":   true,
+	"currency":          true,
+	"risk_mode":         true,
+	"risk_percent":      true,
+	"risk_amount":       true,
+	"max_position_size": true,
+	"created_at":        true,
+	"updated_at":        true,
 }
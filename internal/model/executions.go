@@ -0,0 +1,32 @@
+package model
+
+// executions side
+const (
+	ExecutionsSideBuy  = "buy"
+	ExecutionsSideSell = "sell"
+)
+
+type Executions struct {
+	ID         uint64  `gorm:"column:id;type:int(11);primary_key" json:"id"`
+	TradeID    int     `gorm:"column:trade_id;type:int(11);not null" json:"tradeID"`
+	Side       string  `gorm:"column:side;type:text;not null" json:"side"`
+	Quantity   float64 `gorm:"column:quantity;type:float;not null" json:"quantity"`
+	Price      float64 `gorm:"column:price;type:float;not null" json:"price"`
+	Fee        float64 `gorm:"column:fee;type:float" json:"fee"`
+	ExecutedAt string  `gorm:"column:executed_at;type:varchar(100);not null" json:"executedAt"`
+	CreatedAt  string  `gorm:"column:created_at;type:varchar(100)" json:"createdAt"`
+	UpdatedAt  string  `gorm:"column:updated_at;type:varchar(100)" json:"updatedAt"`
+}
+
+// ExecutionsColumnNames Whitelist for custom query fields to prevent sql injection attacks
+var ExecutionsColumnNames = map[string]bool{
+	"id":          true,
+	"trade_id":    true,
+	"side":        true,
+	"quantity":    true,
+	"price":       true,
+	"fee":         true,
+	"executed_at": true,
+	"created_at":  true,
+	"updated_at":  true,
+}
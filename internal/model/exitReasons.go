@@ -0,0 +1,34 @@
+package model
+
+// ExitReasonsDefaultUserID user id of the default exit reasons shared by all users
+const ExitReasonsDefaultUserID = 0
+
+// default exit reason codes
+const (
+	ExitReasonStopHit      = "stop_hit"
+	ExitReasonTargetHit    = "target_hit"
+	ExitReasonManual       = "manual"
+	ExitReasonTimeStop     = "time_stop"
+	ExitReasonTrailingStop = "trailing_stop"
+	ExitReasonBreakEven    = "break_even"
+	ExitReasonOther        = "other"
+)
+
+type ExitReasons struct {
+	ID        uint64 `gorm:"column:id;type:int(11);primary_key" json:"id"`
+	UserID    int    `gorm:"column:user_id;type:int(11);not null" json:"userID"`
+	Code      string `gorm:"column:code;type:text;not null" json:"code"`
+	Name      string `gorm:"column:name;type:text;not null" json:"name"`
+	CreatedAt string `gorm:"column:created_at;type:varchar(100)" json:"createdAt"`
+	UpdatedAt string `gorm:"column:updated_at;type:varchar(100)" json:"updatedAt"`
+}
+
+// ExitReasonsColumnNames Whitelist for custom query fields to prevent sql injection attacks
+var ExitReasonsColumnNames = map[string]bool{
+	"id":         true,
+	"user_id":    true,
+	"code":       true,
+	"name":       true,
+	"created_at": true,
+	"updated_at": true,
+}
@@ -0,0 +1,24 @@
+package model
+
+type MarketSessions struct {
+	ID        uint64 `gorm:"column:id;type:int(11);primary_key" json:"id"`
+	UserID    int    `gorm:"column:user_id;type:int(11);not null" json:"userID"`
+	Name      string `gorm:"column:name;type:text;not null" json:"name"`
+	Timezone  string `gorm:"column:timezone;type:text" json:"timezone"`
+	OpenTime  string `gorm:"column:open_time;type:text;not null" json:"openTime"`
+	CloseTime string `gorm:"column:close_time;type:text;not null" json:"closeTime"`
+	CreatedAt string `gorm:"column:created_at;type:varchar(100)" json:"createdAt"`
+	UpdatedAt string `gorm:"column:updated_at;type:varchar(100)" json:"updatedAt"`
+}
+
+// MarketSessionsColumnNames Whitelist for custom query fields to prevent sql injection attacks
+var MarketSessionsColumnNames = map[string]bool{
+	"id":         true,
+	"user_id":    true,
+	"name":       true,
+	"timezone":   true,
+	"open_time":  true,
+	"close_time": true,
+	"created_at": true,
+	"updated_at": true,
+}
@@ -1,5 +1,19 @@
 package model
 
+// trades status
+const (
+	TradesStatusPlanned   = "planned"
+	TradesStatusActive    = "active"
+	TradesStatusClosed    = "closed"
+	TradesStatusCancelled = "cancelled"
+)
+
+// trades direction
+const (
+	TradesDirectionLong  = "long"
+	TradesDirectionShort = "short"
+)
+
 type Trades struct {
 	ID                uint64  `gorm:"column:id;type:int(11);primary_key" json:"id"`
 	AccountID         int     `gorm:"column:account_id;type:int(11);not null" json:"accountID"`
@@ -13,16 +27,22 @@ type Trades struct {
 	PositionSize      float64 `gorm:"column:position_size;type:float" json:"positionSize"`
 	PlannedRiskAmount float64 `gorm:"column:planned_risk_amount;type:float" json:"plannedRiskAmount"`
 	PlanNotes         string  `gorm:"column:plan_notes;type:text" json:"planNotes"`
+	Draft             bool    `gorm:"column:draft;type:int(11)" json:"draft"` // the plan is saved without checking the plan rules until it is finalized or activated
 	ActualEntryTime   string  `gorm:"column:actual_entry_time;type:varchar(100)" json:"actualEntryTime"`
 	ActualEntryPrice  float64 `gorm:"column:actual_entry_price;type:float" json:"actualEntryPrice"`
 	ActualExitTime    string  `gorm:"column:actual_exit_time;type:varchar(100)" json:"actualExitTime"`
 	ActualExitPrice   float64 `gorm:"column:actual_exit_price;type:float" json:"actualExitPrice"`
+	MaePrice          float64 `gorm:"column:mae_price;type:float" json:"maePrice"` // maximum adverse excursion, the worst price reached while the trade was open
+	MfePrice          float64 `gorm:"column:mfe_price;type:float" json:"mfePrice"` // maximum favorable excursion, the best price reached while the trade was open
 	Commission        float64 `gorm:"column:commission;type:float" json:"commission"`
 	Pnl               float64 `gorm:"column:pnl;type:float" json:"pnl"`
 	RMultiple         float64 `gorm:"column:r_multiple;type:float" json:"rMultiple"`
 	ExitReason        string  `gorm:"column:exit_reason;type:text" json:"exitReason"`
 	ExecutionScore    int     `gorm:"column:execution_score;type:int(11)" json:"executionScore"`
 	ReflectionNotes   string  `gorm:"column:reflection_notes;type:text" json:"reflectionNotes"`
+	ActivatedAt       string  `gorm:"column:activated_at;type:varchar(100)" json:"activatedAt"` // time of the transition to active
+	ClosedAt          string  `gorm:"column:closed_at;type:varchar(100)" json:"closedAt"`       // time of the transition to closed
+	CancelledAt       string  `gorm:"column:cancelled_at;type:varchar(100)" json:"cancelledAt"` // time of the transition to cancelled
 	CreatedAt         string  `gorm:"column:created_at;type:varchar(100)" json:"createdAt"`
 	UpdatedAt         string  `gorm:"column:updated_at;type:varchar(100)" json:"updatedAt"`
 }
@@ -41,16 +61,22 @@ var TradesColumnNames = map[string]bool{
 	"position_size":       true,
 	"planned_risk_amount": true,
 	"plan_notes":          true,
+	"draft":               true,
 	"actual_entry_time":   true,
 	"actual_entry_price":  true,
 	"actual_exit_time":    true,
 	"actual_exit_price":   true,
+	"mae_price":           true,
+	"mfe_price":           true,
 	"commission":          true,
 	"pnl":                 true,
 	"r_multiple":          true,
 	"exit_reason":         true,
 	"execution_score":     true,
 	"reflection_notes":    true,
+	"activated_at":        true,
+	"closed_at":           true,
+	"cancelled_at":        true,
 	"created_at":          true,
 	"updated_at":          true,
 }
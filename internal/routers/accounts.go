@@ -24,9 +24,15 @@ func accountsRouter(group *gin.RouterGroup, h handler.AccountsHandler) {
 	// If jwt authentication is not required for all routes, authentication middleware can be added
 	// separately for only certain routes. In this case, g.Use(middleware.Auth()) above should not be used.
 
-	g.POST("/", h.Create)          // [post] /api/v1/accounts
-	g.DELETE("/:id", h.DeleteByID) // [delete] /api/v1/accounts/:id
-	g.PUT("/:id", h.UpdateByID)    // [put] /api/v1/accounts/:id
-	g.GET("/:id", h.GetByID)       // [get] /api/v1/accounts/:id
-	g.POST("/list", h.List)        // [post] /api/v1/accounts/list
+	g.POST("/", h.Create)                         // [post] /api/v1/accounts
+	g.DELETE("/:id", h.DeleteByID)                // [delete] /api/v1/accounts/:id
+	g.PUT("/:id", h.UpdateByID)                   // [put] /api/v1/accounts/:id
+	g.GET("/:id", h.GetByID)                      // [get] /api/v1/accounts/:id
+	g.POST("/list", h.List)                       // [post] /api/v1/accounts/list
+	g.GET("/:id/stats", h.GetStats)               // [get] /api/v1/accounts/:id/stats
+	g.GET("/:id/equity", h.GetEquityCurve)        // [get] /api/v1/accounts/:id/equity
+	g.GET("/:id/calendar", h.GetCalendar)         // [get] /api/v1/accounts/:id/calendar
+	g.GET("/:id/timeAnalysis", h.GetTimeAnalysis) // [get] /api/v1/accounts/:id/timeAnalysis
+	g.GET("/:id/streaks", h.GetStreaks)           // [get] /api/v1/accounts/:id/streaks
+	g.GET("/:id/compare", h.GetCompare)           // [get] /api/v1/accounts/:id/compare
 }
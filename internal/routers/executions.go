@@ -0,0 +1,31 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+
+	"helmsman/internal/handler"
+)
+
+func init() {
+	apiV1RouterFns = append(apiV1RouterFns, func(group *gin.RouterGroup) {
+		executionsRouter(group, handler.NewExecutionsHandler())
+	})
+}
+
+func executionsRouter(group *gin.RouterGroup, h handler.ExecutionsHandler) {
+	g := group.Group("/trades/:id/executions")
+
+	// JWT authentication reference: https://go-sponge.com/component/transport/gin.html#jwt-authorization-middleware
+
+	// All the following routes use jwt authentication, you also can use middleware.Auth(middleware.WithExtraVerify(fn))
+	g.Use(middleware.Auth())
+
+	// If jwt authentication is not required for all routes, authentication middleware can be added
+	// separately for only certain routes. In this case, g.Use(middleware.Auth()) above should not be used.
+
+	g.POST("/", h.Create)                   // [post] /api/v1/trades/:id/executions
+	g.DELETE("/:executionID", h.DeleteByID) // [delete] /api/v1/trades/:id/executions/:executionID
+	g.PUT("/:executionID", h.UpdateByID)    // [put] /api/v1/trades/:id/executions/:executionID
+	g.GET("/", h.List)                      // [get] /api/v1/trades/:id/executions
+}
@@ -0,0 +1,34 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+
+	"helmsman/internal/handler"
+)
+
+func init() {
+	apiV1RouterFns = append(apiV1RouterFns, func(group *gin.RouterGroup) {
+		exitReasonsRouter(group, handler.NewExitReasonsHandler())
+	})
+}
+
+func exitReasonsRouter(group *gin.RouterGroup, h handler.ExitReasonsHandler) {
+	g := group.Group("/exitReasons")
+
+	// JWT authentication reference: https://go-sponge.com/component/transport/gin.html#jwt-authorization-middleware
+
+	// All the following routes use jwt authentication, you also can use middleware.Auth(middleware.WithExtraVerify(fn))
+	g.Use(middleware.Auth())
+
+	// If jwt authentication is not required for all routes, authentication middleware can be added
+	// separately for only certain routes. In this case, g.Use(middleware.Auth()) above should not be used.
+
+	g.POST("/", h.Create)                   // [post] /api/v1/exitReasons
+	g.DELETE("/:id", h.DeleteByID)          // [delete] /api/v1/exitReasons/:id
+	g.PUT("/:id", h.UpdateByID)             // [put] /api/v1/exitReasons/:id
+	g.GET("/:id", h.GetByID)                // [get] /api/v1/exitReasons/:id
+	g.POST("/list", h.List)                 // [post] /api/v1/exitReasons/list
+	g.GET("/list/all", h.ListAll)           // [get] /api/v1/exitReasons/list/all
+	g.GET("/performance", h.GetPerformance) // [get] /api/v1/exitReasons/performance
+}
@@ -0,0 +1,33 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-dev-frame/sponge/pkg/gin/middleware"
+
+	"helmsman/internal/handler"
+)
+
+func init() {
+	apiV1RouterFns = append(apiV1RouterFns, func(group *gin.RouterGroup) {
+		marketSessionsRouter(group, handler.NewMarketSessionsHandler())
+	})
+}
+
+func marketSessionsRouter(group *gin.RouterGroup, h handler.MarketSessionsHandler) {
+	g := group.Group("/marketSessions")
+
+	// JWT authentication reference: https://go-sponge.com/component/transport/gin.html#jwt-authorization-middleware
+
+	// All the following routes use jwt authentication, you also can use middleware.Auth(middleware.WithExtraVerify(fn))
+	g.Use(middleware.Auth())
+
+	// If jwt authentication is not required for all routes, authentication middleware can be added
+	// separately for only certain routes. In this case, g.Use(middleware.Auth()) above should not be used.
+
+	g.POST("/", h.Create)          // [post] /api/v1/marketSessions
+	g.DELETE("/:id", h.DeleteByID) // [delete] /api/v1/marketSessions/:id
+	g.PUT("/:id", h.UpdateByID)    // [put] /api/v1/marketSessions/:id
+	g.GET("/:id", h.GetByID)       // [get] /api/v1/marketSessions/:id
+	g.POST("/list", h.List)        // [post] /api/v1/marketSessions/list
+	g.GET("/list/all", h.ListAll)  // [get] /api/v1/marketSessions/list/all
+}
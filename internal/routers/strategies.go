@@ -24,10 +24,12 @@ func strategiesRouter(group *gin.RouterGroup, h handler.StrategiesHandler) {
 	// If jwt authentication is not required for all routes, authentication middleware can be added
 	// separately for only certain routes. In this case, g.Use(middleware.Auth()) above should not be used.
 
-	g.POST("/", h.Create)          // [post] /api/v1/strategies
-	g.DELETE("/:id", h.DeleteByID) // [delete] /api/v1/strategies/:id
-	g.PUT("/:id", h.UpdateByID)    // [put] /api/v1/strategies/:id
-	g.GET("/:id", h.GetByID)       // [get] /api/v1/strategies/:id
-	g.POST("/list", h.List)        // [post] /api/v1/strategies/list
-	g.GET("/all", h.GetAll)        // [get] /api/v1/strategies/all
+	g.POST("/", h.Create)                     // [post] /api/v1/strategies
+	g.DELETE("/:id", h.DeleteByID)            // [delete] /api/v1/strategies/:id
+	g.PUT("/:id", h.UpdateByID)               // [put] /api/v1/strategies/:id
+	g.GET("/:id", h.GetByID)                  // [get] /api/v1/strategies/:id
+	g.POST("/list", h.List)                   // [post] /api/v1/strategies/list
+	g.GET("/all", h.GetAll)                   // [get] /api/v1/strategies/all
+	g.GET("/report", h.GetReport)             // [get] /api/v1/strategies/report
+	g.GET("/significance", h.GetSignificance) // [get] /api/v1/strategies/significance
 }
@@ -24,10 +24,11 @@ func tagsRouter(group *gin.RouterGroup, h handler.TagsHandler) {
 	// If jwt authentication is not required for all routes, authentication middleware can be added
 	// separately for only certain routes. In this case, g.Use(middleware.Auth()) above should not be used.
 
-	g.POST("/", h.Create)          // [post] /api/v1/tags
-	g.DELETE("/:id", h.DeleteByID) // [delete] /api/v1/tags/:id
-	g.PUT("/:id", h.UpdateByID)    // [put] /api/v1/tags/:id
-	g.GET("/:id", h.GetByID)       // [get] /api/v1/tags/:id
-	g.POST("/list", h.List)        // [post] /api/v1/tags/list
-	g.GET("/list/all", h.ListAll)  // [get] /api/v1/tags/list/all
+	g.POST("/", h.Create)                   // [post] /api/v1/tags
+	g.DELETE("/:id", h.DeleteByID)          // [delete] /api/v1/tags/:id
+	g.PUT("/:id", h.UpdateByID)             // [put] /api/v1/tags/:id
+	g.GET("/:id", h.GetByID)                // [get] /api/v1/tags/:id
+	g.POST("/list", h.List)                 // [post] /api/v1/tags/list
+	g.GET("/list/all", h.ListAll)           // [get] /api/v1/tags/list/all
+	g.GET("/performance", h.GetPerformance) // [get] /api/v1/tags/performance
 }
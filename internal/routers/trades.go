@@ -24,9 +24,25 @@ func tradesRouter(group *gin.RouterGroup, h handler.TradesHandler) {
 	// If jwt authentication is not required for all routes, authentication middleware can be added
 	// separately for only certain routes. In this case, g.Use(middleware.Auth()) above should not be used.
 
-	g.POST("/", h.Create)          // [post] /api/v1/trades
-	g.DELETE("/:id", h.DeleteByID) // [delete] /api/v1/trades/:id
-	g.PUT("/:id", h.UpdateByID)    // [put] /api/v1/trades/:id
-	g.GET("/:id", h.GetByID)       // [get] /api/v1/trades/:id
-	g.POST("/list", h.List)        // [post] /api/v1/trades/list
+	g.POST("/", h.Create)                         // [post] /api/v1/trades
+	g.DELETE("/:id", h.DeleteByID)                // [delete] /api/v1/trades/:id
+	g.PUT("/:id", h.UpdateByID)                   // [put] /api/v1/trades/:id
+	g.GET("/:id", h.GetByID)                      // [get] /api/v1/trades/:id
+	g.POST("/list", h.List)                       // [post] /api/v1/trades/list
+	g.GET("/symbols", h.GetSymbolStats)           // [get] /api/v1/trades/symbols
+	g.POST("/rDistribution", h.GetRDistribution)  // [post] /api/v1/trades/rDistribution
+	g.GET("/slippage", h.GetSlippage)             // [get] /api/v1/trades/slippage
+	g.GET("/executionScore", h.GetExecutionScore) // [get] /api/v1/trades/executionScore
+	g.GET("/holdingPeriods", h.GetHoldingPeriods) // [get] /api/v1/trades/holdingPeriods
+	g.POST("/monteCarlo", h.GetMonteCarlo)        // [post] /api/v1/trades/monteCarlo
+	g.GET("/risk", h.GetRisk)                     // [get] /api/v1/trades/risk
+	g.GET("/excursions", h.GetExcursions)         // [get] /api/v1/trades/excursions
+	g.POST("/:id/excursion", h.UpdateExcursion)   // [post] /api/v1/trades/:id/excursion
+	g.GET("/directions", h.GetDirections)         // [get] /api/v1/trades/directions
+	g.GET("/rolling", h.GetRolling)               // [get] /api/v1/trades/rolling
+	g.POST("/:id/activate", h.Activate)           // [post] /api/v1/trades/:id/activate
+	g.POST("/:id/close", h.Close)                 // [post] /api/v1/trades/:id/close
+	g.POST("/:id/cancel", h.Cancel)               // [post] /api/v1/trades/:id/cancel
+	g.POST("/:id/reopen", h.Reopen)               // [post] /api/v1/trades/:id/reopen
+	g.POST("/size", h.GetPositionSize)            // [post] /api/v1/trades/size
 }
@@ -0,0 +1,76 @@
+package stats
+
+import (
+	"errors"
+	"sort"
+	"time"
+	_ "time/tzdata" // embed the time zone database, the host may not have one
+
+	"helmsman/internal/model"
+)
+
+// LoadLocation load a location by IANA time zone name, e.g. Asia/Shanghai, an empty name means time.Local
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(name)
+}
+
+// ParsePeriod parse a calendar month (2006-01) or year (2006) into a time range in loc
+func ParsePeriod(period string, loc *time.Location) (TimeRange, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	if t, err := time.ParseInLocation("2006-01", period, loc); err == nil {
+		return TimeRange{Start: t, End: t.AddDate(0, 1, 0)}, nil
+	}
+	if t, err := time.ParseInLocation("2006", period, loc); err == nil {
+		return TimeRange{Start: t, End: t.AddDate(1, 0, 0)}, nil
+	}
+	return TimeRange{}, errors.New("period must be a month like 2006-01 or a year like 2006")
+}
+
+// CalendarDay result of the trades closed on a calendar day
+type CalendarDay struct {
+	Date       string  `json:"date"` // 2006-01-02
+	NetPnl     float64 `json:"netPnl"`
+	TradeCount int     `json:"tradeCount"`
+	WinCount   int     `json:"winCount"`
+	LossCount  int     `json:"lossCount"`
+}
+
+// DailyCalendar aggregate trades by the calendar day of their exit time in loc, days without
+// trades are omitted and trades with an unparsable exit time are ignored, sorted by date.
+func DailyCalendar(trades []*model.Trades, loc *time.Location) []*CalendarDay {
+	days := map[string]*CalendarDay{}
+	for _, t := range trades {
+		exitTime, err := ParseTime(t.ActualExitTime, loc)
+		if err != nil {
+			continue
+		}
+		date := exitTime.Format("2006-01-02")
+		day, ok := days[date]
+		if !ok {
+			day = &CalendarDay{Date: date}
+			days[date] = day
+		}
+		net := NetPnl(t)
+		day.NetPnl += net
+		day.TradeCount++
+		if net > 0 {
+			day.WinCount++
+		} else if net < 0 {
+			day.LossCount++
+		}
+	}
+
+	calendar := make([]*CalendarDay, 0, len(days))
+	for _, day := range days {
+		calendar = append(calendar, day)
+	}
+	sort.Slice(calendar, func(i, j int) bool {
+		return calendar[i].Date < calendar[j].Date
+	})
+	return calendar
+}
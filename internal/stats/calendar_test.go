@@ -0,0 +1,51 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestParsePeriod(t *testing.T) {
+	r, err := ParsePeriod("2024-02", time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), r.Start)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), r.End)
+
+	r, err = ParsePeriod("2024", time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), r.End)
+
+	_, err = ParsePeriod("2024-02-01", time.UTC)
+	assert.Error(t, err)
+}
+
+func TestDailyCalendar(t *testing.T) {
+	shanghai, err := LoadLocation("Asia/Shanghai")
+	assert.NoError(t, err)
+	trades := []*model.Trades{
+		{ID: 1, Pnl: 100, ActualExitTime: "2024-01-02T15:00:00Z"}, // 2024-01-02 23:00 in Shanghai
+		{ID: 2, Pnl: -50, ActualExitTime: "2024-01-02T17:00:00Z"}, // 2024-01-03 01:00 in Shanghai
+		{ID: 3, Pnl: 30, Commission: 5, ActualExitTime: "2024-01-02T18:00:00Z"},
+		{ID: 4, Pnl: 30, ActualExitTime: "invalid"},
+	}
+
+	days := DailyCalendar(trades, time.UTC)
+	assert.Len(t, days, 1)
+	assert.Equal(t, "2024-01-02", days[0].Date)
+	assert.Equal(t, 3, days[0].TradeCount)
+	assert.InDelta(t, 75, days[0].NetPnl, 1e-9)
+
+	days = DailyCalendar(trades, shanghai)
+	assert.Len(t, days, 2)
+	assert.Equal(t, "2024-01-03", days[1].Date)
+	assert.Equal(t, 1, days[1].WinCount)
+	assert.Equal(t, 1, days[1].LossCount)
+	assert.InDelta(t, -25, days[1].NetPnl, 1e-9)
+
+	_, err = LoadLocation("Mars/Olympus")
+	assert.Error(t, err)
+}
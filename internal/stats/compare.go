@@ -0,0 +1,81 @@
+package stats
+
+import (
+	"math"
+
+	"helmsman/internal/model"
+)
+
+// SummaryMetric a metric of Summary that can be compared and sorted by
+type SummaryMetric struct {
+	Name  string
+	Value func(s *Summary) (float64, bool) // false if the metric is undefined, e.g. profit factor without losses
+}
+
+func definedMetric(v float64) (float64, bool) { return v, true }
+
+// SummaryMetrics the core metrics of Summary in display order
+var SummaryMetrics = []SummaryMetric{
+	{"tradeCount", func(s *Summary) (float64, bool) { return definedMetric(float64(s.TradeCount)) }},
+	{"winRate", func(s *Summary) (float64, bool) { return definedMetric(s.WinRate) }},
+	{"avgWin", func(s *Summary) (float64, bool) { return definedMetric(s.AvgWin) }},
+	{"avgLoss", func(s *Summary) (float64, bool) { return definedMetric(s.AvgLoss) }},
+	{"expectancy", func(s *Summary) (float64, bool) { return definedMetric(s.Expectancy) }},
+	{"profitFactor", func(s *Summary) (float64, bool) {
+		if s.ProfitFactor == nil {
+			return 0, false
+		}
+		return *s.ProfitFactor, true
+	}},
+	{"avgR", func(s *Summary) (float64, bool) { return definedMetric(s.AvgR) }},
+	{"totalPnl", func(s *Summary) (float64, bool) { return definedMetric(s.TotalPnl) }},
+	{"totalCommission", func(s *Summary) (float64, bool) { return definedMetric(s.TotalCommission) }},
+	{"netPnl", func(s *Summary) (float64, bool) { return definedMetric(s.NetPnl) }},
+	{"avgHoldingTime", func(s *Summary) (float64, bool) { return definedMetric(float64(s.AvgHoldingTime)) }},
+}
+
+// MetricDelta change of a metric between two periods, a value is null if the metric is undefined
+type MetricDelta struct {
+	Metric       string   `json:"metric"`
+	Current      *float64 `json:"current"`
+	Previous     *float64 `json:"previous"`
+	Delta        *float64 `json:"delta"`        // current - previous, null if either is null
+	DeltaPercent *float64 `json:"deltaPercent"` // delta / |previous| * 100, null if previous is 0 or null
+}
+
+// PeriodComparison performance of two periods and the change of every core metric
+type PeriodComparison struct {
+	Current  *Summary       `json:"current"`
+	Previous *Summary       `json:"previous"`
+	Deltas   []*MetricDelta `json:"deltas"`
+}
+
+// ComparePeriods compare the performance of the trades of the current period with the previous period
+func ComparePeriods(current []*model.Trades, previous []*model.Trades) *PeriodComparison {
+	c := &PeriodComparison{
+		Current:  Summarize(current),
+		Previous: Summarize(previous),
+		Deltas:   make([]*MetricDelta, 0, len(SummaryMetrics)),
+	}
+	for _, metric := range SummaryMetrics {
+		d := &MetricDelta{Metric: metric.Name}
+		current, currentOK := metric.Value(c.Current)
+		previous, previousOK := metric.Value(c.Previous)
+		if currentOK {
+			d.Current = &current
+		}
+		if previousOK {
+			d.Previous = &previous
+		}
+		if currentOK && previousOK {
+			delta := current - previous
+			d.Delta = &delta
+			if previous != 0 {
+				percent := delta / math.Abs(previous) * 100
+				d.DeltaPercent = &percent
+			}
+		}
+		c.Deltas = append(c.Deltas, d)
+	}
+	return c
+}
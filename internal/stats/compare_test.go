@@ -0,0 +1,42 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestComparePeriods(t *testing.T) {
+	current := []*model.Trades{
+		{ID: 1, Pnl: 300, RMultiple: 3},
+		{ID: 2, Pnl: -100, RMultiple: -1},
+	}
+	previous := []*model.Trades{
+		{ID: 3, Pnl: 100, RMultiple: 1},
+	}
+	c := ComparePeriods(current, previous)
+	assert.Equal(t, 2, c.Current.TradeCount)
+	assert.Equal(t, 1, c.Previous.TradeCount)
+	assert.Len(t, c.Deltas, len(SummaryMetrics))
+
+	deltas := map[string]*MetricDelta{}
+	for _, d := range c.Deltas {
+		deltas[d.Metric] = d
+	}
+	assert.Equal(t, 1.0, *deltas["tradeCount"].Delta)
+	assert.InDelta(t, 100, *deltas["tradeCount"].DeltaPercent, 1e-9)
+	assert.InDelta(t, -0.5, *deltas["winRate"].Delta, 1e-9)
+	assert.InDelta(t, -50, *deltas["winRate"].DeltaPercent, 1e-9)
+	assert.InDelta(t, 100, *deltas["netPnl"].Delta, 1e-9)
+	// no losing trade in the previous period
+	assert.Equal(t, 3.0, *deltas["profitFactor"].Current)
+	assert.Nil(t, deltas["profitFactor"].Previous)
+	assert.Nil(t, deltas["profitFactor"].Delta)
+	assert.Nil(t, deltas["profitFactor"].DeltaPercent)
+
+	c = ComparePeriods(nil, nil)
+	assert.Equal(t, 0, c.Current.TradeCount)
+	assert.Nil(t, c.Deltas[0].DeltaPercent)
+}
@@ -0,0 +1,114 @@
+package stats
+
+import (
+	"strconv"
+	"strings"
+
+	"helmsman/internal/model"
+)
+
+// NormalizeDirection normalize a direction to long or short, buy and sell are accepted as
+// aliases, an empty string is returned if the direction is unknown.
+func NormalizeDirection(direction string) string {
+	switch strings.ToLower(strings.TrimSpace(direction)) {
+	case model.TradesDirectionLong, "buy":
+		return model.TradesDirectionLong
+	case model.TradesDirectionShort, "sell":
+		return model.TradesDirectionShort
+	}
+	return ""
+}
+
+// Direction normalized direction of a trade, an empty string is returned if the direction is unknown.
+func Direction(t *model.Trades) string {
+	return NormalizeDirection(t.Direction)
+}
+
+// DirectionSign 1 for long and -1 for short trades, a price move multiplied by the sign is
+// positive if it is in favor of the trade, 0 if the direction is unknown.
+func DirectionSign(t *model.Trades) float64 {
+	switch Direction(t) {
+	case model.TradesDirectionLong:
+		return 1
+	case model.TradesDirectionShort:
+		return -1
+	}
+	return 0
+}
+
+// GrossPnl pnl before commission implied by the direction, actual entry and exit price and
+// position size, false if any of them is missing.
+func GrossPnl(t *model.Trades) (float64, bool) {
+	sign := DirectionSign(t)
+	if sign == 0 || t.ActualEntryPrice <= 0 || t.ActualExitPrice <= 0 || t.PositionSize <= 0 {
+		return 0, false
+	}
+	return sign * (t.ActualExitPrice - t.ActualEntryPrice) * t.PositionSize, true
+}
+
+// PnlConsistent reports whether the recorded pnl and r_multiple of a trade have the same sign
+// as the price move in the direction of the trade, a trade that cannot be checked is consistent.
+func PnlConsistent(t *model.Trades) bool {
+	sign := DirectionSign(t)
+	if sign == 0 || t.ActualEntryPrice <= 0 || t.ActualExitPrice <= 0 {
+		return true
+	}
+	move := sign * (t.ActualExitPrice - t.ActualEntryPrice)
+	return move*t.Pnl >= 0 && move*t.RMultiple >= 0
+}
+
+// DirectionSplit performance of the long and short trades of a group
+type DirectionSplit struct {
+	Key          string   `json:"key"`
+	Long         *Summary `json:"long"`
+	Short        *Summary `json:"short"`
+	UnknownCount int      `json:"unknownCount"` // trades whose direction is neither long nor short
+}
+
+// SplitByDirection compute the performance metrics of the long and short trades separately
+func SplitByDirection(key string, trades []*model.Trades) *DirectionSplit {
+	directions, _ := GroupBy(trades, Direction)
+	return &DirectionSplit{
+		Key:          key,
+		Long:         Summarize(directions[model.TradesDirectionLong]),
+		Short:        Summarize(directions[model.TradesDirectionShort]),
+		UnknownCount: len(directions[""]),
+	}
+}
+
+// DirectionReport long vs short performance of trades
+type DirectionReport struct {
+	Overall         *DirectionSplit   `json:"overall"`
+	ByAccount       []*DirectionSplit `json:"byAccount"`       // key is the account id
+	ByStrategy      []*DirectionSplit `json:"byStrategy"`      // key is the strategy id, 0 means no strategy
+	UnknownTradeIDs []uint64          `json:"unknownTradeIDs"` // trades with an unknown direction
+	InconsistentIDs []uint64          `json:"inconsistentIDs"` // trades whose pnl or r_multiple contradicts the direction and prices
+}
+
+// AnalyzeDirection split the performance of trades by direction overall, per account and per strategy
+func AnalyzeDirection(trades []*model.Trades) *DirectionReport {
+	report := &DirectionReport{
+		Overall:         SplitByDirection("overall", trades),
+		ByAccount:       []*DirectionSplit{},
+		ByStrategy:      []*DirectionSplit{},
+		UnknownTradeIDs: []uint64{},
+		InconsistentIDs: []uint64{},
+	}
+	for _, t := range trades {
+		if Direction(t) == "" {
+			report.UnknownTradeIDs = append(report.UnknownTradeIDs, t.ID)
+		} else if !PnlConsistent(t) {
+			report.InconsistentIDs = append(report.InconsistentIDs, t.ID)
+		}
+	}
+
+	byAccount, accountIDs := GroupBy(trades, func(t *model.Trades) int { return t.AccountID })
+	for _, id := range accountIDs {
+		report.ByAccount = append(report.ByAccount, SplitByDirection(strconv.Itoa(id), byAccount[id]))
+	}
+	byStrategy, strategyIDs := GroupBy(trades, func(t *model.Trades) int { return t.StrategyID })
+	for _, id := range strategyIDs {
+		report.ByStrategy = append(report.ByStrategy, SplitByDirection(strconv.Itoa(id), byStrategy[id]))
+	}
+	return report
+}
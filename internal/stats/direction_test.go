@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestNormalizeDirection(t *testing.T) {
+	assert.Equal(t, "long", NormalizeDirection(" Long "))
+	assert.Equal(t, "long", NormalizeDirection("BUY"))
+	assert.Equal(t, "short", NormalizeDirection("sell"))
+	assert.Equal(t, "", NormalizeDirection("flat"))
+	assert.Equal(t, -1.0, DirectionSign(&model.Trades{Direction: "short"}))
+	assert.Equal(t, 0.0, DirectionSign(&model.Trades{}))
+}
+
+func TestGrossPnl(t *testing.T) {
+	pnl, ok := GrossPnl(&model.Trades{Direction: "long", ActualEntryPrice: 100, ActualExitPrice: 110, PositionSize: 2})
+	assert.True(t, ok)
+	assert.Equal(t, 20.0, pnl)
+	pnl, ok = GrossPnl(&model.Trades{Direction: "short", ActualEntryPrice: 100, ActualExitPrice: 110, PositionSize: 2})
+	assert.True(t, ok)
+	assert.Equal(t, -20.0, pnl)
+	_, ok = GrossPnl(&model.Trades{Direction: "short", ActualEntryPrice: 100})
+	assert.False(t, ok)
+
+	assert.True(t, PnlConsistent(&model.Trades{Direction: "short", ActualEntryPrice: 100, ActualExitPrice: 90, Pnl: 10, RMultiple: 1}))
+	assert.False(t, PnlConsistent(&model.Trades{Direction: "short", ActualEntryPrice: 100, ActualExitPrice: 90, Pnl: -10, RMultiple: -1}))
+	assert.True(t, PnlConsistent(&model.Trades{Direction: "short", Pnl: -10}))
+}
+
+func TestAnalyzeDirection(t *testing.T) {
+	trades := []*model.Trades{
+		{ID: 1, AccountID: 1, StrategyID: 1, Direction: "long", ActualEntryPrice: 100, ActualExitPrice: 110, Pnl: 100, RMultiple: 2},
+		{ID: 2, AccountID: 1, StrategyID: 2, Direction: "short", ActualEntryPrice: 100, ActualExitPrice: 110, Pnl: 100, RMultiple: 2},
+		{ID: 3, AccountID: 2, StrategyID: 1, Direction: "sell", Pnl: -50, RMultiple: -1},
+		{ID: 4, AccountID: 2, StrategyID: 1, Direction: "?", Pnl: 10},
+	}
+	report := AnalyzeDirection(trades)
+	assert.Equal(t, 1, report.Overall.Long.TradeCount)
+	assert.Equal(t, 2, report.Overall.Short.TradeCount)
+	assert.Equal(t, 1, report.Overall.UnknownCount)
+	assert.Equal(t, []uint64{4}, report.UnknownTradeIDs)
+	assert.Equal(t, []uint64{2}, report.InconsistentIDs)
+	assert.Len(t, report.ByAccount, 2)
+	assert.Equal(t, "2", report.ByAccount[1].Key)
+	assert.Equal(t, 1, report.ByAccount[1].Short.TradeCount)
+	assert.Len(t, report.ByStrategy, 2)
+	assert.Equal(t, 1, report.ByStrategy[0].Long.TradeCount)
+	assert.Equal(t, 1, report.ByStrategy[0].Short.TradeCount)
+}
@@ -0,0 +1,151 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"helmsman/internal/model"
+)
+
+// DefaultPercentiles percentiles reported when none is requested
+var DefaultPercentiles = []float64{5, 10, 25, 50, 75, 90, 95}
+
+// HistogramBucket number of values in [From, To), a nil bound means the bucket is open on that side
+type HistogramBucket struct {
+	From      *float64 `json:"from"`
+	To        *float64 `json:"to"`
+	Count     int      `json:"count"`
+	Frequency float64  `json:"frequency"` // count / total count
+}
+
+// PercentileValue value at a percentile
+type PercentileValue struct {
+	Percentile float64 `json:"percentile"` // 0~100
+	Value      float64 `json:"value"`
+}
+
+// RankedTrade a trade in a top list
+type RankedTrade struct {
+	TradeID   uint64  `json:"tradeID"`
+	Symbol    string  `json:"symbol"`
+	RMultiple float64 `json:"rMultiple"`
+	NetPnl    float64 `json:"netPnl"`
+}
+
+// RDistribution distribution of the r_multiple of trades
+type RDistribution struct {
+	Count       int                `json:"count"`
+	Mean        float64            `json:"mean"`
+	StdDev      float64            `json:"stdDev"`
+	Skewness    float64            `json:"skewness"`
+	Min         float64            `json:"min"`
+	Max         float64            `json:"max"`
+	Percentiles []*PercentileValue `json:"percentiles"`
+	Histogram   []*HistogramBucket `json:"histogram"`
+	TopWinners  []*RankedTrade     `json:"topWinners"` // largest r_multiple first
+	TopLosers   []*RankedTrade     `json:"topLosers"`  // smallest r_multiple first
+}
+
+// HistogramEdges build ascending bucket edges from min to max with a step of size
+func HistogramEdges(min float64, max float64, size float64) ([]float64, error) {
+	if size <= 0 || max <= min {
+		return nil, errors.New("bucket size must be positive and max must be greater than min")
+	}
+	if (max-min)/size > 1000 {
+		return nil, errors.New("too many buckets")
+	}
+	edges := []float64{}
+	for i := 0; ; i++ {
+		edge := min + float64(i)*size
+		if edge > max+size/1e6 {
+			break
+		}
+		edges = append(edges, math.Round(edge*1e9)/1e9)
+	}
+	return edges, nil
+}
+
+// Histogram count values into buckets split by ascending edges, values below the first edge
+// and at or above the last edge are counted in open buckets at both ends.
+func Histogram(values []float64, edges []float64) ([]*HistogramBucket, error) {
+	if len(edges) == 0 {
+		return nil, errors.New("edges cannot be empty")
+	}
+	for i := 1; i < len(edges); i++ {
+		if edges[i] <= edges[i-1] {
+			return nil, fmt.Errorf("edges must be in ascending order, got %v after %v", edges[i], edges[i-1])
+		}
+	}
+
+	buckets := make([]*HistogramBucket, 0, len(edges)+1)
+	buckets = append(buckets, &HistogramBucket{To: &edges[0]})
+	for i := 1; i < len(edges); i++ {
+		buckets = append(buckets, &HistogramBucket{From: &edges[i-1], To: &edges[i]})
+	}
+	buckets = append(buckets, &HistogramBucket{From: &edges[len(edges)-1]})
+
+	for _, v := range values {
+		i := sort.Search(len(edges), func(i int) bool { return edges[i] > v }) // number of edges <= v
+		buckets[i].Count++
+	}
+	if len(values) > 0 {
+		for _, b := range buckets {
+			b.Frequency = float64(b.Count) / float64(len(values))
+		}
+	}
+	return buckets, nil
+}
+
+// DistributionOfR compute the distribution of the r_multiple of trades
+func DistributionOfR(trades []*model.Trades, edges []float64, percentiles []float64, top int) (*RDistribution, error) {
+	values := make([]float64, 0, len(trades))
+	for _, t := range trades {
+		values = append(values, t.RMultiple)
+	}
+	histogram, err := Histogram(values, edges)
+	if err != nil {
+		return nil, err
+	}
+	if len(percentiles) == 0 {
+		percentiles = DefaultPercentiles
+	}
+
+	sorted := Sorted(values)
+	d := &RDistribution{
+		Count:       len(values),
+		Mean:        Mean(values),
+		StdDev:      StdDev(values),
+		Skewness:    Skewness(values),
+		Percentiles: make([]*PercentileValue, 0, len(percentiles)),
+		Histogram:   histogram,
+		TopWinners:  []*RankedTrade{},
+		TopLosers:   []*RankedTrade{},
+	}
+	if len(sorted) > 0 {
+		d.Min, d.Max = sorted[0], sorted[len(sorted)-1]
+	}
+	for _, p := range percentiles {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("percentile %v is out of range 0~100", p)
+		}
+		d.Percentiles = append(d.Percentiles, &PercentileValue{Percentile: p, Value: Percentile(sorted, p)})
+	}
+
+	ranked := make([]*model.Trades, len(trades))
+	copy(ranked, trades)
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].RMultiple > ranked[j].RMultiple })
+	for i := 0; i < len(ranked) && i < top && ranked[i].RMultiple > 0; i++ {
+		d.TopWinners = append(d.TopWinners, newRankedTrade(ranked[i]))
+	}
+	for i := len(ranked) - 1; i >= 0 && len(ranked)-1-i < top && ranked[i].RMultiple < 0; i-- {
+		d.TopLosers = append(d.TopLosers, newRankedTrade(ranked[i]))
+	}
+
+	return d, nil
+}
+
+func newRankedTrade(t *model.Trades) *RankedTrade {
+	return &RankedTrade{TradeID: t.ID, Symbol: t.Symbol, RMultiple: t.RMultiple, NetPnl: NetPnl(t)}
+}
@@ -0,0 +1,78 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestHistogramEdges(t *testing.T) {
+	edges, err := HistogramEdges(-1, 1, 0.5)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{-1, -0.5, 0, 0.5, 1}, edges)
+
+	edges, err = HistogramEdges(0, 1, 0.1)
+	assert.NoError(t, err)
+	assert.Len(t, edges, 11)
+
+	_, err = HistogramEdges(1, 0, 0.5)
+	assert.Error(t, err)
+	_, err = HistogramEdges(0, 1, 0)
+	assert.Error(t, err)
+	_, err = HistogramEdges(0, 10000, 1)
+	assert.Error(t, err)
+}
+
+func TestHistogram(t *testing.T) {
+	buckets, err := Histogram([]float64{-2, -1, 0, 0.5, 1, 3}, []float64{-1, 0, 1})
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 4)
+	assert.Nil(t, buckets[0].From)
+	assert.Equal(t, 1, buckets[0].Count) // -2
+	assert.Equal(t, 1, buckets[1].Count) // -1
+	assert.Equal(t, 2, buckets[2].Count) // 0, 0.5
+	assert.Equal(t, 2, buckets[3].Count) // 1, 3
+	assert.Nil(t, buckets[3].To)
+	assert.InDelta(t, 1.0/3, buckets[2].Frequency, 1e-9)
+
+	_, err = Histogram(nil, nil)
+	assert.Error(t, err)
+	_, err = Histogram(nil, []float64{1, 1})
+	assert.Error(t, err)
+}
+
+func TestDistributionOfR(t *testing.T) {
+	trades := []*model.Trades{
+		{ID: 1, Symbol: "AAPL", RMultiple: 3, Pnl: 300},
+		{ID: 2, Symbol: "AAPL", RMultiple: -1, Pnl: -100},
+		{ID: 3, Symbol: "MSFT", RMultiple: 2, Pnl: 200},
+		{ID: 4, Symbol: "MSFT", RMultiple: -0.5, Pnl: -50},
+		{ID: 5, Symbol: "TSLA", RMultiple: 0, Pnl: 0},
+	}
+	d, err := DistributionOfR(trades, []float64{-1, 0, 1, 2}, []float64{50}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, d.Count)
+	assert.InDelta(t, 0.7, d.Mean, 1e-9)
+	assert.Equal(t, -1.0, d.Min)
+	assert.Equal(t, 3.0, d.Max)
+	assert.Len(t, d.Percentiles, 1)
+	assert.Equal(t, 0.0, d.Percentiles[0].Value)
+	assert.Len(t, d.Histogram, 5)
+
+	assert.Len(t, d.TopWinners, 2)
+	assert.Equal(t, uint64(1), d.TopWinners[0].TradeID)
+	assert.Equal(t, uint64(3), d.TopWinners[1].TradeID)
+	assert.Len(t, d.TopLosers, 2)
+	assert.Equal(t, uint64(2), d.TopLosers[0].TradeID)
+	assert.Equal(t, uint64(4), d.TopLosers[1].TradeID)
+
+	d, err = DistributionOfR(nil, []float64{0}, nil, 5)
+	assert.NoError(t, err)
+	assert.Len(t, d.Percentiles, len(DefaultPercentiles))
+	assert.Empty(t, d.TopWinners)
+
+	_, err = DistributionOfR(trades, []float64{0}, []float64{101}, 5)
+	assert.Error(t, err)
+}
@@ -0,0 +1,127 @@
+package stats
+
+import (
+	"time"
+
+	"helmsman/internal/model"
+)
+
+// EquityPoint equity of the account after a closed trade
+type EquityPoint struct {
+	TradeID         uint64  `json:"tradeID"`
+	Time            string  `json:"time"`            // actual exit time of the trade
+	Pnl             float64 `json:"pnl"`             // net pnl of the trade
+	Equity          float64 `json:"equity"`          // equity after the trade
+	Peak            float64 `json:"peak"`            // running peak of equity
+	Drawdown        float64 `json:"drawdown"`        // peak - equity
+	DrawdownPercent float64 `json:"drawdownPercent"` // drawdown / peak * 100
+}
+
+// EquityCurve equity series and drawdown metrics of an account
+type EquityCurve struct {
+	StartingEquity     float64        `json:"startingEquity"`
+	EndingEquity       float64        `json:"endingEquity"`
+	Points             []*EquityPoint `json:"points"`
+	MaxDrawdown        float64        `json:"maxDrawdown"`        // largest peak to trough decline
+	MaxDrawdownPercent float64        `json:"maxDrawdownPercent"` // largest peak to trough decline in percent of the peak
+	MaxDrawdownTradeID uint64         `json:"maxDrawdownTradeID"` // the trade at the trough of the max drawdown
+
+	// longest period below a previous peak, measured from the peak to the recovery,
+	// or to the last trade if equity has not recovered yet, the longest in seconds and
+	// the longest in trades may be different periods
+	MaxDrawdownDuration       int64 `json:"maxDrawdownDuration"`       // seconds
+	MaxDrawdownDurationTrades int   `json:"maxDrawdownDurationTrades"` // trades
+
+	// time from the trough of the max drawdown until equity is back at the previous peak
+	Recovered      bool  `json:"recovered"`
+	RecoveryTime   int64 `json:"recoveryTime"` // seconds, 0 if not recovered
+	RecoveryTrades int   `json:"recoveryTrades"`
+}
+
+// BuildEquityCurve build the equity curve from the initial balance and closed trades sorted
+// by exit time. Trades exiting before r.Start are added to the starting equity without being
+// emitted as points, trades exiting at or after r.End are ignored.
+func BuildEquityCurve(initialBalance float64, trades []*model.Trades, r TimeRange, loc *time.Location) *EquityCurve {
+	curve := &EquityCurve{StartingEquity: initialBalance, Points: []*EquityPoint{}}
+	times := []time.Time{}
+	for _, t := range trades {
+		exitTime, err := ParseTime(t.ActualExitTime, loc)
+		if !r.IsZero() {
+			if err != nil || !r.End.IsZero() && !exitTime.Before(r.End) {
+				continue
+			}
+			if !r.Start.IsZero() && exitTime.Before(r.Start) {
+				curve.StartingEquity += NetPnl(t)
+				continue
+			}
+		}
+		curve.Points = append(curve.Points, &EquityPoint{TradeID: t.ID, Time: t.ActualExitTime, Pnl: NetPnl(t)})
+		times = append(times, exitTime)
+	}
+
+	equity, peak := curve.StartingEquity, curve.StartingEquity
+	peakIndex := -1 // -1 means the starting equity
+	troughIndex := -1
+	inMaxDrawdown := false
+	// the starting equity has no time, the first trade is used as the start of a drawdown from it
+	timeOf := func(i int) time.Time {
+		if i < 0 {
+			i = 0
+		}
+		return times[i]
+	}
+	seconds := func(from int, to int) int64 {
+		a, b := timeOf(from), timeOf(to)
+		if a.IsZero() || b.IsZero() {
+			return 0
+		}
+		return int64(b.Sub(a).Seconds())
+	}
+	// the longest drawdown in time and in trades may be different drawdowns
+	recordDuration := func(end int) {
+		if n := end - peakIndex; n > curve.MaxDrawdownDurationTrades {
+			curve.MaxDrawdownDurationTrades = n
+		}
+		if d := seconds(peakIndex, end); d > curve.MaxDrawdownDuration {
+			curve.MaxDrawdownDuration = d
+		}
+	}
+
+	for i, p := range curve.Points {
+		equity += p.Pnl
+		p.Equity = equity
+		if equity >= peak {
+			if i-peakIndex > 1 {
+				recordDuration(i)
+			}
+			if inMaxDrawdown {
+				curve.Recovered = true
+				curve.RecoveryTime = seconds(troughIndex, i)
+				curve.RecoveryTrades = i - troughIndex
+				inMaxDrawdown = false
+			}
+			peak, peakIndex = equity, i
+		}
+		p.Peak = peak
+		p.Drawdown = peak - equity
+		if peak > 0 {
+			p.DrawdownPercent = p.Drawdown / peak * 100
+		}
+		if p.Drawdown > curve.MaxDrawdown {
+			curve.MaxDrawdown = p.Drawdown
+			curve.MaxDrawdownTradeID = p.TradeID
+			curve.Recovered, curve.RecoveryTime, curve.RecoveryTrades = false, 0, 0
+			troughIndex = i
+			inMaxDrawdown = true
+		}
+		if p.DrawdownPercent > curve.MaxDrawdownPercent {
+			curve.MaxDrawdownPercent = p.DrawdownPercent
+		}
+	}
+	if n := len(curve.Points); n > 0 && curve.Points[n-1].Drawdown > 0 {
+		recordDuration(n - 1) // still below the peak, measured to the last trade
+	}
+	curve.EndingEquity = equity
+
+	return curve
+}
@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestBuildEquityCurve(t *testing.T) {
+	// net pnl: +198, -102, +298, -102
+	curve := BuildEquityCurve(1000, newTestTrades(), TimeRange{}, nil)
+	assert.Len(t, curve.Points, 4)
+	assert.InDelta(t, 1000, curve.StartingEquity, 1e-9)
+	assert.InDelta(t, 1292, curve.EndingEquity, 1e-9)
+	assert.InDelta(t, 1198, curve.Points[0].Peak, 1e-9)
+	assert.InDelta(t, 102, curve.Points[1].Drawdown, 1e-9)
+	assert.InDelta(t, 102.0/1198*100, curve.Points[1].DrawdownPercent, 1e-9)
+	assert.InDelta(t, 102, curve.MaxDrawdown, 1e-9)
+	assert.Equal(t, uint64(2), curve.MaxDrawdownTradeID)
+	assert.True(t, curve.Recovered)
+	assert.Equal(t, 1, curve.RecoveryTrades)
+	assert.Equal(t, int64(86400), curve.RecoveryTime)
+	assert.Equal(t, 2, curve.MaxDrawdownDurationTrades)
+	assert.Equal(t, int64(2*86400), curve.MaxDrawdownDuration)
+
+	r, err := ParseTimeRange("2024-01-03", "", nil)
+	assert.NoError(t, err)
+	curve = BuildEquityCurve(1000, newTestTrades(), r, nil)
+	assert.Len(t, curve.Points, 3)
+	assert.InDelta(t, 1198, curve.StartingEquity, 1e-9)
+	assert.InDelta(t, 1292, curve.EndingEquity, 1e-9)
+
+	// the last trade is still in drawdown
+	trades := newTestTrades()[:2]
+	curve = BuildEquityCurve(1000, trades, TimeRange{}, nil)
+	assert.False(t, curve.Recovered)
+	assert.Equal(t, 1, curve.MaxDrawdownDurationTrades)
+	assert.Equal(t, int64(86400), curve.MaxDrawdownDuration)
+
+	// the longest drawdown in trades is shorter in time than a later one
+	trades = []*model.Trades{
+		{ID: 1, Pnl: 100, ActualExitTime: "2024-01-01 10:00:00"},
+		{ID: 2, Pnl: -10, ActualExitTime: "2024-01-01 10:10:00"},
+		{ID: 3, Pnl: -10, ActualExitTime: "2024-01-01 10:20:00"},
+		{ID: 4, Pnl: 50, ActualExitTime: "2024-01-01 10:30:00"},
+		{ID: 5, Pnl: -10, ActualExitTime: "2024-01-11 10:30:00"},
+		{ID: 6, Pnl: 50, ActualExitTime: "2024-01-21 10:30:00"},
+	}
+	curve = BuildEquityCurve(1000, trades, TimeRange{}, nil)
+	assert.Equal(t, 3, curve.MaxDrawdownDurationTrades)
+	assert.Equal(t, int64(20*86400), curve.MaxDrawdownDuration)
+
+	assert.Empty(t, BuildEquityCurve(1000, nil, TimeRange{}, nil).Points)
+}
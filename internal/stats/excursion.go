@@ -0,0 +1,192 @@
+package stats
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"helmsman/internal/model"
+)
+
+// Bar price range of a period of a symbol
+type Bar struct {
+	Time string  `json:"time"` // open time of the bar
+	High float64 `json:"high"`
+	Low  float64 `json:"low"`
+}
+
+// ExcursionFromBars compute the mae and mfe price of a trade from the bars opened between its
+// actual entry and exit time in loc, the entry and exit price are always part of the range
+func ExcursionFromBars(t *model.Trades, bars []*Bar, loc *time.Location) (maePrice float64, mfePrice float64, err error) {
+	sign := DirectionSign(t)
+	if sign == 0 {
+		return 0, 0, errors.New("unknown direction " + t.Direction)
+	}
+	if t.ActualEntryPrice <= 0 || t.ActualExitPrice <= 0 {
+		return 0, 0, errors.New("actual entry and exit price are required")
+	}
+	entryTime, err := ParseTime(t.ActualEntryTime, loc)
+	if err != nil {
+		return 0, 0, err
+	}
+	exitTime, err := ParseTime(t.ActualExitTime, loc)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	high := math.Max(t.ActualEntryPrice, t.ActualExitPrice)
+	low := math.Min(t.ActualEntryPrice, t.ActualExitPrice)
+	for _, bar := range bars {
+		barTime, err := ParseTime(bar.Time, loc)
+		if err != nil {
+			return 0, 0, err
+		}
+		if barTime.Before(entryTime.Truncate(time.Minute)) || barTime.After(exitTime) {
+			continue
+		}
+		if bar.High < bar.Low {
+			return 0, 0, errors.New("bar high is lower than low at " + bar.Time)
+		}
+		high = math.Max(high, bar.High)
+		low = math.Min(low, bar.Low)
+	}
+
+	if sign > 0 {
+		return low, high, nil
+	}
+	return high, low, nil
+}
+
+// TradeExcursion maximum adverse and favorable excursion of a trade, a metric is nil if the
+// prices it depends on are missing
+type TradeExcursion struct {
+	TradeID         uint64   `json:"tradeID"`
+	StrategyID      int      `json:"strategyID"`
+	Symbol          string   `json:"symbol"`
+	Direction       string   `json:"direction"`
+	MaeR            *float64 `json:"maeR"`            // adverse excursion from the entry in R, a positive value
+	MfeR            *float64 `json:"mfeR"`            // favorable excursion from the entry in R, a positive value
+	EntryEfficiency *float64 `json:"entryEfficiency"` // share of the price range left in the trade direction after the entry, 1 is the best possible entry
+	ExitEfficiency  *float64 `json:"exitEfficiency"`  // share of the price range captured at the exit, 1 is the best possible exit
+	TotalEfficiency *float64 `json:"totalEfficiency"` // (exit - entry) / range in the trade direction, entry + exit - 1
+	RealizedR       float64  `json:"realizedR"`       // r_multiple of the trade
+}
+
+// Excursion compute the mae/mfe metrics of a trade, R is the distance between the actual entry and
+// the planned stop loss
+func Excursion(t *model.Trades) *TradeExcursion {
+	e := &TradeExcursion{
+		TradeID:    t.ID,
+		StrategyID: t.StrategyID,
+		Symbol:     t.Symbol,
+		Direction:  Direction(t),
+		RealizedR:  t.RMultiple,
+	}
+	sign := DirectionSign(t)
+	if sign == 0 {
+		return e
+	}
+	if t.ActualEntryPrice <= 0 || t.MaePrice <= 0 || t.MfePrice <= 0 {
+		return e
+	}
+	adverse := sign * (t.ActualEntryPrice - t.MaePrice)
+	favorable := sign * (t.MfePrice - t.ActualEntryPrice)
+	if adverse < 0 || favorable < 0 {
+		return e // inconsistent with the entry price
+	}
+
+	if risk := sign * (t.ActualEntryPrice - t.PlannedStopLoss); t.PlannedStopLoss > 0 && risk > 0 {
+		maeR, mfeR := adverse/risk, favorable/risk
+		e.MaeR, e.MfeR = &maeR, &mfeR
+	}
+	if priceRange := adverse + favorable; priceRange > 0 {
+		entry := favorable / priceRange
+		e.EntryEfficiency = &entry
+		if t.ActualExitPrice > 0 {
+			exit := sign * (t.ActualExitPrice - t.MaePrice) / priceRange
+			total := entry + exit - 1
+			e.ExitEfficiency, e.TotalEfficiency = &exit, &total
+		}
+	}
+	return e
+}
+
+// ExcursionSummary aggregated mae/mfe metrics of a group of trades, every average only counts
+// the trades where the metric could be computed
+type ExcursionSummary struct {
+	Key                string  `json:"key"`
+	TradeCount         int     `json:"tradeCount"`
+	ExcursionCount     int     `json:"excursionCount"` // trades with mae/mfe in R
+	AvgMaeR            float64 `json:"avgMaeR"`
+	AvgMfeR            float64 `json:"avgMfeR"`
+	WinnersMaeRP90     float64 `json:"winnersMaeRP90"` // 90% of the winning trades went less against the entry, a hint for the stop distance
+	LosersMfeRP50      float64 `json:"losersMfeRP50"`  // median favorable excursion of the losing trades, profit left before the loss
+	AvgEntryEfficiency float64 `json:"avgEntryEfficiency"`
+	AvgExitEfficiency  float64 `json:"avgExitEfficiency"`
+	AvgTotalEfficiency float64 `json:"avgTotalEfficiency"`
+}
+
+// SummarizeExcursion aggregate the excursion of trades under key
+func SummarizeExcursion(key string, items []*TradeExcursion) *ExcursionSummary {
+	s := &ExcursionSummary{Key: key, TradeCount: len(items)}
+	var maeRs, mfeRs, winnersMaeRs, losersMfeRs, entries, exits, totals []float64
+	for _, e := range items {
+		if e.MaeR != nil && e.MfeR != nil {
+			maeRs = append(maeRs, *e.MaeR)
+			mfeRs = append(mfeRs, *e.MfeR)
+			if e.RealizedR > 0 {
+				winnersMaeRs = append(winnersMaeRs, *e.MaeR)
+			} else if e.RealizedR < 0 {
+				losersMfeRs = append(losersMfeRs, *e.MfeR)
+			}
+		}
+		if e.EntryEfficiency != nil {
+			entries = append(entries, *e.EntryEfficiency)
+		}
+		if e.ExitEfficiency != nil {
+			exits = append(exits, *e.ExitEfficiency)
+			totals = append(totals, *e.TotalEfficiency)
+		}
+	}
+	s.ExcursionCount = len(maeRs)
+	s.AvgMaeR = Mean(maeRs)
+	s.AvgMfeR = Mean(mfeRs)
+	s.WinnersMaeRP90 = Percentile(Sorted(winnersMaeRs), 90)
+	s.LosersMfeRP50 = Percentile(Sorted(losersMfeRs), 50)
+	s.AvgEntryEfficiency = Mean(entries)
+	s.AvgExitEfficiency = Mean(exits)
+	s.AvgTotalEfficiency = Mean(totals)
+	return s
+}
+
+// ExcursionReport mae/mfe metrics of trades
+type ExcursionReport struct {
+	Overall    *ExcursionSummary   `json:"overall"`
+	ByStrategy []*ExcursionSummary `json:"byStrategy"` // key is the strategy id, 0 means no strategy
+	Trades     []*TradeExcursion   `json:"trades"`
+}
+
+// AnalyzeExcursion compute the mae/mfe metrics of every trade and aggregate them overall and per strategy
+func AnalyzeExcursion(trades []*model.Trades) *ExcursionReport {
+	report := &ExcursionReport{
+		ByStrategy: []*ExcursionSummary{},
+		Trades:     make([]*TradeExcursion, 0, len(trades)),
+	}
+	excursions := make(map[*model.Trades]*TradeExcursion, len(trades))
+	for _, t := range trades {
+		excursions[t] = Excursion(t)
+		report.Trades = append(report.Trades, excursions[t])
+	}
+	report.Overall = SummarizeExcursion("overall", report.Trades)
+
+	groups, strategyIDs := GroupBy(trades, func(t *model.Trades) int { return t.StrategyID })
+	for _, id := range strategyIDs {
+		items := make([]*TradeExcursion, 0, len(groups[id]))
+		for _, t := range groups[id] {
+			items = append(items, excursions[t])
+		}
+		report.ByStrategy = append(report.ByStrategy, SummarizeExcursion(strconv.Itoa(id), items))
+	}
+	return report
+}
@@ -0,0 +1,94 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestExcursionFromBars(t *testing.T) {
+	trade := &model.Trades{
+		Direction:        "long",
+		ActualEntryTime:  "2024-01-02 09:30:20",
+		ActualEntryPrice: 100,
+		ActualExitTime:   "2024-01-02 09:33:00",
+		ActualExitPrice:  103,
+	}
+	bars := []*Bar{
+		{Time: "2024-01-02 09:29:00", High: 110, Low: 90}, // before entry
+		{Time: "2024-01-02 09:30:00", High: 101, Low: 98},
+		{Time: "2024-01-02 09:31:00", High: 104, Low: 99},
+		{Time: "2024-01-02 09:32:00", High: 105, Low: 101},
+		{Time: "2024-01-02 09:34:00", High: 120, Low: 80}, // after exit
+	}
+	mae, mfe, err := ExcursionFromBars(trade, bars, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, 98.0, mae)
+	assert.Equal(t, 105.0, mfe)
+
+	trade.Direction = "short"
+	mae, mfe, err = ExcursionFromBars(trade, bars, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, 105.0, mae)
+	assert.Equal(t, 98.0, mfe)
+
+	// no bars, the range is the entry and exit price
+	mae, mfe, err = ExcursionFromBars(trade, nil, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, 103.0, mae)
+	assert.Equal(t, 100.0, mfe)
+
+	_, _, err = ExcursionFromBars(trade, []*Bar{{Time: "2024-01-02 09:31:00", High: 1, Low: 2}}, time.UTC)
+	assert.Error(t, err)
+	_, _, err = ExcursionFromBars(trade, []*Bar{{Time: "bad"}}, time.UTC)
+	assert.Error(t, err)
+	_, _, err = ExcursionFromBars(&model.Trades{Direction: "?"}, nil, time.UTC)
+	assert.Error(t, err)
+	_, _, err = ExcursionFromBars(&model.Trades{Direction: "long"}, nil, time.UTC)
+	assert.Error(t, err)
+}
+
+func TestExcursion(t *testing.T) {
+	long := &model.Trades{
+		ID: 1, Direction: "long", RMultiple: 1.5,
+		PlannedStopLoss: 98, ActualEntryPrice: 100, ActualExitPrice: 103,
+		MaePrice: 99, MfePrice: 104,
+	}
+	e := Excursion(long)
+	assert.InDelta(t, 0.5, *e.MaeR, 1e-9)
+	assert.InDelta(t, 2, *e.MfeR, 1e-9)
+	assert.InDelta(t, 0.8, *e.EntryEfficiency, 1e-9)
+	assert.InDelta(t, 0.8, *e.ExitEfficiency, 1e-9)
+	assert.InDelta(t, 0.6, *e.TotalEfficiency, 1e-9)
+
+	short := &model.Trades{
+		ID: 2, Direction: "short", RMultiple: -1,
+		PlannedStopLoss: 52, ActualEntryPrice: 50, ActualExitPrice: 52,
+		MaePrice: 52, MfePrice: 49,
+	}
+	e = Excursion(short)
+	assert.InDelta(t, 1, *e.MaeR, 1e-9)
+	assert.InDelta(t, 0.5, *e.MfeR, 1e-9)
+	assert.InDelta(t, 1.0/3, *e.EntryEfficiency, 1e-9)
+	assert.InDelta(t, 0, *e.ExitEfficiency, 1e-9)
+
+	e = Excursion(&model.Trades{Direction: "long", ActualEntryPrice: 100})
+	assert.Nil(t, e.MaeR)
+	assert.Nil(t, e.EntryEfficiency)
+	// mae above the entry of a long trade is inconsistent
+	e = Excursion(&model.Trades{Direction: "long", ActualEntryPrice: 100, MaePrice: 101, MfePrice: 105})
+	assert.Nil(t, e.EntryEfficiency)
+
+	report := AnalyzeExcursion([]*model.Trades{long, short, {ID: 3, StrategyID: 1, Direction: "long"}})
+	assert.Equal(t, 3, report.Overall.TradeCount)
+	assert.Equal(t, 2, report.Overall.ExcursionCount)
+	assert.InDelta(t, 0.75, report.Overall.AvgMaeR, 1e-9)
+	assert.InDelta(t, 0.5, report.Overall.WinnersMaeRP90, 1e-9)
+	assert.InDelta(t, 0.5, report.Overall.LosersMfeRP50, 1e-9)
+	assert.Len(t, report.ByStrategy, 2)
+	assert.Equal(t, "0", report.ByStrategy[0].Key)
+	assert.Equal(t, 1, report.ByStrategy[1].TradeCount)
+}
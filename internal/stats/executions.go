@@ -0,0 +1,118 @@
+package stats
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"helmsman/internal/model"
+)
+
+// errors of DerivePosition
+var (
+	ErrPositionDirection = errors.New("direction of the trade must be long or short to derive its position from executions")
+	ErrExecutionSide     = errors.New("side of an execution must be buy or sell")
+	ErrOverfilled        = errors.New("an exit execution is larger than the open quantity")
+)
+
+// quantityEpsilon open quantities below it are treated as flat to absorb floating point errors
+const quantityEpsilon = 1e-9
+
+// Position state of a trade derived from its executions
+type Position struct {
+	Direction     string  `json:"direction"`
+	EntryQuantity float64 `json:"entryQuantity"` // total quantity of the fills on the side of the trade
+	ExitQuantity  float64 `json:"exitQuantity"`  // total quantity of the fills on the other side
+	OpenQuantity  float64 `json:"openQuantity"`
+	AvgEntryPrice float64 `json:"avgEntryPrice"` // 0 without entries
+	AvgExitPrice  float64 `json:"avgExitPrice"`  // 0 without exits
+	RealizedPnl   float64 `json:"realizedPnl"`   // before fees, the exits are matched against the average cost of the open quantity
+	Fees          float64 `json:"fees"`
+	EntryTime     string  `json:"entryTime"` // time of the first entry
+	ExitTime      string  `json:"exitTime"`  // time of the last exit
+	Status        string  `json:"status"`    // planned without executions, active while open and closed when flat
+}
+
+// NormalizeExecutionSide normalize a side to buy or sell, an empty string is returned if the side is unknown.
+func NormalizeExecutionSide(side string) string {
+	switch strings.ToLower(strings.TrimSpace(side)) {
+	case model.ExecutionsSideBuy:
+		return model.ExecutionsSideBuy
+	case model.ExecutionsSideSell:
+		return model.ExecutionsSideSell
+	}
+	return ""
+}
+
+// DerivePosition replay the executions of a trade in the order of their time, the fills on the side
+// of the direction add to the position and the others reduce it. An error is returned if an
+// execution has an unknown side or time or reduces the position below zero.
+func DerivePosition(direction string, executions []*model.Executions, loc *time.Location) (*Position, error) {
+	direction = NormalizeDirection(direction)
+	entrySide := model.ExecutionsSideBuy
+	sign := 1.0
+	switch direction {
+	case model.TradesDirectionLong:
+	case model.TradesDirectionShort:
+		entrySide, sign = model.ExecutionsSideSell, -1
+	default:
+		return nil, ErrPositionDirection
+	}
+
+	type fill struct {
+		*model.Executions
+		at time.Time
+	}
+	fills := make([]fill, 0, len(executions))
+	for _, e := range executions {
+		if NormalizeExecutionSide(e.Side) == "" {
+			return nil, ErrExecutionSide
+		}
+		at, err := ParseTime(e.ExecutedAt, loc)
+		if err != nil {
+			return nil, err
+		}
+		fills = append(fills, fill{Executions: e, at: at})
+	}
+	sort.SliceStable(fills, func(i, j int) bool { return fills[i].at.Before(fills[j].at) })
+
+	p := &Position{Direction: direction, Status: model.TradesStatusPlanned}
+	var entryValue, exitValue, avgCost float64
+	for _, f := range fills {
+		p.Fees += f.Fee
+		if NormalizeExecutionSide(f.Side) == entrySide {
+			avgCost = (avgCost*p.OpenQuantity + f.Price*f.Quantity) / (p.OpenQuantity + f.Quantity)
+			p.OpenQuantity += f.Quantity
+			p.EntryQuantity += f.Quantity
+			entryValue += f.Price * f.Quantity
+			if p.EntryTime == "" {
+				p.EntryTime = f.ExecutedAt
+			}
+			continue
+		}
+		if f.Quantity > p.OpenQuantity+quantityEpsilon {
+			return nil, ErrOverfilled
+		}
+		p.RealizedPnl += sign * (f.Price - avgCost) * f.Quantity
+		p.OpenQuantity -= f.Quantity
+		if p.OpenQuantity < quantityEpsilon {
+			p.OpenQuantity, avgCost = 0, 0
+		}
+		p.ExitQuantity += f.Quantity
+		exitValue += f.Price * f.Quantity
+		p.ExitTime = f.ExecutedAt
+	}
+
+	if p.EntryQuantity > 0 {
+		p.AvgEntryPrice = entryValue / p.EntryQuantity
+		p.Status = model.TradesStatusActive
+		if p.OpenQuantity == 0 {
+			p.Status = model.TradesStatusClosed
+		}
+	}
+	if p.ExitQuantity > 0 {
+		p.AvgExitPrice = exitValue / p.ExitQuantity
+	}
+	return p, nil
+}
@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestNormalizeExecutionSide(t *testing.T) {
+	assert.Equal(t, model.ExecutionsSideBuy, NormalizeExecutionSide(" Buy "))
+	assert.Equal(t, model.ExecutionsSideSell, NormalizeExecutionSide("SELL"))
+	assert.Equal(t, "", NormalizeExecutionSide("long"))
+}
+
+func TestDerivePosition(t *testing.T) {
+	// no executions
+	p, err := DerivePosition("long", nil, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TradesStatusPlanned, p.Status)
+
+	// scale in twice, partial exit, given out of order
+	executions := []*model.Executions{
+		{Side: "sell", Quantity: 10, Price: 110, Fee: 1, ExecutedAt: "2024-01-03 10:00:00"},
+		{Side: "buy", Quantity: 10, Price: 100, Fee: 1, ExecutedAt: "2024-01-01 10:00:00"},
+		{Side: "buy", Quantity: 10, Price: 104, Fee: 1, ExecutedAt: "2024-01-02 10:00:00"},
+	}
+	p, err = DerivePosition("long", executions, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TradesStatusActive, p.Status)
+	assert.Equal(t, 20.0, p.EntryQuantity)
+	assert.Equal(t, 10.0, p.OpenQuantity)
+	assert.InDelta(t, 102, p.AvgEntryPrice, 1e-9)
+	assert.InDelta(t, 110, p.AvgExitPrice, 1e-9)
+	assert.InDelta(t, 80, p.RealizedPnl, 1e-9)
+	assert.Equal(t, 3.0, p.Fees)
+	assert.Equal(t, "2024-01-01 10:00:00", p.EntryTime)
+
+	// flat after the last exit
+	executions = append(executions, &model.Executions{Side: "sell", Quantity: 10, Price: 98, ExecutedAt: "2024-01-04 10:00:00"})
+	p, err = DerivePosition("long", executions, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TradesStatusClosed, p.Status)
+	assert.Equal(t, 0.0, p.OpenQuantity)
+	assert.InDelta(t, 104, p.AvgExitPrice, 1e-9)
+	assert.InDelta(t, 40, p.RealizedPnl, 1e-9)
+	assert.Equal(t, "2024-01-04 10:00:00", p.ExitTime)
+
+	// short trade
+	p, err = DerivePosition("short", []*model.Executions{
+		{Side: "sell", Quantity: 5, Price: 100, ExecutedAt: "2024-01-01 10:00:00"},
+		{Side: "buy", Quantity: 5, Price: 90, ExecutedAt: "2024-01-01 11:00:00"},
+	}, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TradesStatusClosed, p.Status)
+	assert.InDelta(t, 50, p.RealizedPnl, 1e-9)
+
+	// exit larger than the open quantity
+	_, err = DerivePosition("long", []*model.Executions{
+		{Side: "buy", Quantity: 5, Price: 100, ExecutedAt: "2024-01-01 10:00:00"},
+		{Side: "sell", Quantity: 6, Price: 90, ExecutedAt: "2024-01-01 11:00:00"},
+	}, time.UTC)
+	assert.ErrorIs(t, err, ErrOverfilled)
+
+	_, err = DerivePosition("", executions, time.UTC)
+	assert.ErrorIs(t, err, ErrPositionDirection)
+	_, err = DerivePosition("long", []*model.Executions{{Side: "hold", ExecutedAt: "2024-01-01"}}, time.UTC)
+	assert.ErrorIs(t, err, ErrExecutionSide)
+	_, err = DerivePosition("long", []*model.Executions{{Side: "buy", ExecutedAt: "yesterday"}}, time.UTC)
+	assert.ErrorIs(t, err, ErrInvalidTime)
+}
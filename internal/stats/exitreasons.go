@@ -0,0 +1,105 @@
+package stats
+
+import (
+	"regexp"
+	"strings"
+
+	"helmsman/internal/model"
+)
+
+var nonCodeChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// NormalizeExitReasonCode normalize a code to lower snake case, e.g. "Stop Hit" to stop_hit
+func NormalizeExitReasonCode(code string) string {
+	return strings.Trim(nonCodeChars.ReplaceAllString(strings.ToLower(code), "_"), "_")
+}
+
+// keywords of free text exit reasons mapped to default codes, checked in order
+var exitReasonKeywords = []struct {
+	code     string
+	keywords []string
+}{
+	{model.ExitReasonTrailingStop, []string{"trail", "移动止损", "跟踪止损"}},
+	{model.ExitReasonTimeStop, []string{"time", "expire", "eod", "end of day", "时间"}},
+	{model.ExitReasonBreakEven, []string{"break even", "breakeven", "break_even", "保本"}},
+	{model.ExitReasonStopHit, []string{"stop", "sl", "止损"}},
+	{model.ExitReasonTargetHit, []string{"target", "tp", "take profit", "止盈", "目标"}},
+	{model.ExitReasonManual, []string{"manual", "discretion", "手动", "主动"}},
+}
+
+// NormalizeExitReason map a free text exit reason to a default code by keywords,
+// an empty string is returned if no keyword matches
+func NormalizeExitReason(text string) string {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return ""
+	}
+	words := strings.Fields(nonCodeChars.ReplaceAllString(text, " "))
+	for _, rule := range exitReasonKeywords {
+		for _, keyword := range rule.keywords {
+			if len(keyword) <= 2 { // short abbreviations must be whole words
+				for _, word := range words {
+					if word == keyword {
+						return rule.code
+					}
+				}
+				continue
+			}
+			if strings.Contains(text, keyword) {
+				return rule.code
+			}
+		}
+	}
+	return ""
+}
+
+// ResolveExitReason find the code in vocabulary matching text by code, by name or by the keywords
+// of NormalizeExitReason, ok is false if there is no match
+func ResolveExitReason(text string, vocabulary []*model.ExitReasons) (string, bool) {
+	code := NormalizeExitReasonCode(text)
+	if code == "" {
+		return "", false
+	}
+	for _, v := range vocabulary {
+		if v.Code == code || strings.EqualFold(strings.TrimSpace(v.Name), strings.TrimSpace(text)) {
+			return v.Code, true
+		}
+	}
+	if code = NormalizeExitReason(text); code != "" {
+		for _, v := range vocabulary {
+			if v.Code == code {
+				return v.Code, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ExitReasonStats performance of the trades closed for an exit reason
+type ExitReasonStats struct {
+	ExitReason *model.ExitReasons
+	Summary    *Summary
+}
+
+// ExitReasonBreakdown summarize trades per exit reason of vocabulary in its order, trades whose
+// exit_reason is not a code of vocabulary are summarized per stored value in unmapped
+func ExitReasonBreakdown(trades []*model.Trades, vocabulary []*model.ExitReasons) ([]*ExitReasonStats, []*Bucket) {
+	groups, keys := GroupBy(trades, func(t *model.Trades) string { return t.ExitReason })
+
+	items := make([]*ExitReasonStats, 0, len(vocabulary))
+	known := map[string]bool{}
+	for _, v := range vocabulary {
+		if known[v.Code] {
+			continue
+		}
+		known[v.Code] = true
+		items = append(items, &ExitReasonStats{ExitReason: v, Summary: Summarize(groups[v.Code])})
+	}
+	unmapped := []*Bucket{}
+	for _, key := range keys {
+		if !known[key] {
+			unmapped = append(unmapped, &Bucket{Key: key, Summary: *Summarize(groups[key])})
+		}
+	}
+	return items, unmapped
+}
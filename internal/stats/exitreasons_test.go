@@ -0,0 +1,76 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestNormalizeExitReasonCode(t *testing.T) {
+	assert.Equal(t, "stop_hit", NormalizeExitReasonCode(" Stop Hit "))
+	assert.Equal(t, "news_exit", NormalizeExitReasonCode("news-exit!"))
+	assert.Equal(t, "", NormalizeExitReasonCode("止损"))
+}
+
+func TestNormalizeExitReason(t *testing.T) {
+	cases := map[string]string{
+		"Stopped out":           model.ExitReasonStopHit,
+		"SL":                    model.ExitReasonStopHit,
+		"hit TP":                model.ExitReasonTargetHit,
+		"took profit at target": model.ExitReasonTargetHit,
+		"trailing stop":         model.ExitReasonTrailingStop,
+		"time stop, EOD":        model.ExitReasonTimeStop,
+		"moved to breakeven":    model.ExitReasonBreakEven,
+		"manual close":          model.ExitReasonManual,
+		"止损":                    model.ExitReasonStopHit,
+		"slippage":              "",
+		"":                      "",
+		// any non-alphanumeric character separates words, migrations/001_exit_reasons.sql matches the same way
+		"SL-hit":     model.ExitReasonStopHit,
+		"tp达到":       model.ExitReasonTargetHit,
+		"break-even": "",
+	}
+	for text, code := range cases {
+		assert.Equal(t, code, NormalizeExitReason(text), text)
+	}
+}
+
+func TestResolveExitReason(t *testing.T) {
+	vocabulary := []*model.ExitReasons{
+		{ID: 1, Code: model.ExitReasonStopHit, Name: "Stop hit"},
+		{ID: 2, Code: "news_exit", Name: "News exit"},
+	}
+	code, ok := ResolveExitReason("News Exit", vocabulary)
+	assert.True(t, ok)
+	assert.Equal(t, "news_exit", code)
+	code, ok = ResolveExitReason("stopped out", vocabulary)
+	assert.True(t, ok)
+	assert.Equal(t, model.ExitReasonStopHit, code)
+	_, ok = ResolveExitReason("target", vocabulary)
+	assert.False(t, ok)
+	_, ok = ResolveExitReason("", vocabulary)
+	assert.False(t, ok)
+}
+
+func TestExitReasonBreakdown(t *testing.T) {
+	vocabulary := []*model.ExitReasons{
+		{ID: 1, Code: model.ExitReasonStopHit},
+		{ID: 2, Code: model.ExitReasonTargetHit},
+	}
+	trades := []*model.Trades{
+		{ID: 1, ExitReason: model.ExitReasonStopHit, Pnl: -100},
+		{ID: 2, ExitReason: model.ExitReasonStopHit, Pnl: -50},
+		{ID: 3, ExitReason: "gut feeling", Pnl: 20},
+		{ID: 4, Pnl: 10},
+	}
+	items, unmapped := ExitReasonBreakdown(trades, vocabulary)
+	assert.Len(t, items, 2)
+	assert.Equal(t, 2, items[0].Summary.TradeCount)
+	assert.Equal(t, -150.0, items[0].Summary.NetPnl)
+	assert.Equal(t, 0, items[1].Summary.TradeCount)
+	assert.Len(t, unmapped, 2)
+	assert.Equal(t, "gut feeling", unmapped[0].Key)
+	assert.Equal(t, "", unmapped[1].Key)
+}
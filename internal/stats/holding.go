@@ -0,0 +1,130 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"helmsman/internal/model"
+)
+
+// DefaultHoldingBuckets holding period buckets used when no custom edges are given:
+// scalp under 30 minutes, intraday under 1 day, swing under 14 days and position beyond
+var DefaultHoldingBuckets = []HoldingBucketConfig{
+	{Name: "scalp", To: 30 * time.Minute},
+	{Name: "intraday", To: 24 * time.Hour},
+	{Name: "swing", To: 14 * 24 * time.Hour},
+	{Name: "position"},
+}
+
+// HoldingBucketConfig a holding period bucket ending at To (exclusive), the bucket starts at the
+// end of the previous one, a zero To means no upper bound and is only allowed for the last bucket
+type HoldingBucketConfig struct {
+	Name string
+	To   time.Duration
+}
+
+// HoldingBucketsFromEdges build buckets from ascending edges in minutes, named after their bounds
+func HoldingBucketsFromEdges(edgeMinutes []int) ([]HoldingBucketConfig, error) {
+	if len(edgeMinutes) == 0 {
+		return nil, errors.New("edges cannot be empty")
+	}
+	configs := make([]HoldingBucketConfig, 0, len(edgeMinutes)+1)
+	from := time.Duration(0)
+	for i, minutes := range edgeMinutes {
+		to := time.Duration(minutes) * time.Minute
+		if to <= from {
+			return nil, fmt.Errorf("edges must be positive and in ascending order, got %d at index %d", minutes, i)
+		}
+		configs = append(configs, HoldingBucketConfig{Name: formatDuration(from) + "-" + formatDuration(to), To: to})
+		from = to
+	}
+	configs = append(configs, HoldingBucketConfig{Name: formatDuration(from) + "+"})
+	return configs, nil
+}
+
+// formatDuration format a whole number of minutes in the largest exact unit, e.g. 90m, 4h, 2d
+func formatDuration(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	switch {
+	case minutes == 0:
+		return "0"
+	case minutes%(24*60) == 0:
+		return fmt.Sprintf("%dd", minutes/(24*60))
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
+// HoldingBucket performance of the trades held for [FromSeconds, ToSeconds)
+type HoldingBucket struct {
+	Name        string `json:"name"`
+	FromSeconds int64  `json:"fromSeconds"`
+	ToSeconds   *int64 `json:"toSeconds"` // nil means no upper bound
+	Summary
+}
+
+// UnparsableTrade a trade whose holding period cannot be derived
+type UnparsableTrade struct {
+	TradeID         uint64 `json:"tradeID"`
+	ActualEntryTime string `json:"actualEntryTime"`
+	ActualExitTime  string `json:"actualExitTime"`
+	Error           string `json:"error"`
+}
+
+// HoldingAnalysis performance of trades by holding period
+type HoldingAnalysis struct {
+	Buckets    []*HoldingBucket   `json:"buckets"`
+	Unparsable []*UnparsableTrade `json:"unparsable"` // excluded from the buckets
+}
+
+// AnalyzeHolding bucket trades by the duration between their actual entry and exit time in loc
+func AnalyzeHolding(trades []*model.Trades, configs []HoldingBucketConfig, loc *time.Location) (*HoldingAnalysis, error) {
+	if len(configs) == 0 {
+		configs = DefaultHoldingBuckets
+	}
+	for i, config := range configs {
+		if config.To == 0 && i != len(configs)-1 {
+			return nil, fmt.Errorf("only the last bucket can have no upper bound, bucket %s", config.Name)
+		}
+		if i > 0 && config.To != 0 && config.To <= configs[i-1].To {
+			return nil, fmt.Errorf("bucket %s must end after bucket %s", config.Name, configs[i-1].Name)
+		}
+	}
+
+	result := &HoldingAnalysis{Unparsable: []*UnparsableTrade{}}
+	groups := make([][]*model.Trades, len(configs))
+	for _, t := range trades {
+		d, err := HoldingTime(t, loc)
+		if err != nil {
+			result.Unparsable = append(result.Unparsable, &UnparsableTrade{
+				TradeID:         t.ID,
+				ActualEntryTime: t.ActualEntryTime,
+				ActualExitTime:  t.ActualExitTime,
+				Error:           err.Error(),
+			})
+			continue
+		}
+		i := 0
+		for i < len(configs)-1 && d >= configs[i].To {
+			i++
+		}
+		if configs[i].To != 0 && d >= configs[i].To {
+			continue // beyond the last bounded bucket
+		}
+		groups[i] = append(groups[i], t)
+	}
+
+	from := time.Duration(0)
+	for i, config := range configs {
+		bucket := &HoldingBucket{Name: config.Name, FromSeconds: int64(from / time.Second), Summary: *Summarize(groups[i])}
+		if config.To != 0 {
+			to := int64(config.To / time.Second)
+			bucket.ToSeconds = &to
+		}
+		result.Buckets = append(result.Buckets, bucket)
+		from = config.To
+	}
+	return result, nil
+}
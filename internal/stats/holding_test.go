@@ -0,0 +1,62 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestHoldingBucketsFromEdges(t *testing.T) {
+	configs, err := HoldingBucketsFromEdges([]int{15, 240, 2880})
+	assert.NoError(t, err)
+	assert.Equal(t, []HoldingBucketConfig{
+		{Name: "0-15m", To: 15 * time.Minute},
+		{Name: "15m-4h", To: 4 * time.Hour},
+		{Name: "4h-2d", To: 48 * time.Hour},
+		{Name: "2d+"},
+	}, configs)
+
+	_, err = HoldingBucketsFromEdges(nil)
+	assert.Error(t, err)
+	_, err = HoldingBucketsFromEdges([]int{60, 30})
+	assert.Error(t, err)
+	_, err = HoldingBucketsFromEdges([]int{0})
+	assert.Error(t, err)
+}
+
+func TestAnalyzeHolding(t *testing.T) {
+	trades := []*model.Trades{
+		{ID: 1, Pnl: 10, ActualEntryTime: "2024-01-02 09:30:00", ActualExitTime: "2024-01-02 09:35:00"},
+		{ID: 2, Pnl: -20, ActualEntryTime: "2024-01-02 09:30:00", ActualExitTime: "2024-01-02 15:00:00"},
+		{ID: 3, Pnl: 300, ActualEntryTime: "2024-01-02 09:30:00", ActualExitTime: "2024-01-05 15:00:00"},
+		{ID: 4, Pnl: 500, ActualEntryTime: "2024-01-02 09:30:00", ActualExitTime: "2024-03-01 15:00:00"},
+		{ID: 5, Pnl: 1, ActualEntryTime: "yesterday", ActualExitTime: "2024-01-02 15:00:00"},
+		{ID: 6, Pnl: 1, ActualEntryTime: "2024-01-03 09:30:00", ActualExitTime: "2024-01-02 15:00:00"},
+	}
+	result, err := AnalyzeHolding(trades, nil, time.UTC)
+	assert.NoError(t, err)
+	assert.Len(t, result.Buckets, 4)
+	for i, name := range []string{"scalp", "intraday", "swing", "position"} {
+		assert.Equal(t, name, result.Buckets[i].Name)
+		assert.Equal(t, 1, result.Buckets[i].TradeCount)
+	}
+	assert.Equal(t, int64(1800), *result.Buckets[0].ToSeconds)
+	assert.Equal(t, int64(1800), result.Buckets[1].FromSeconds)
+	assert.Nil(t, result.Buckets[3].ToSeconds)
+	assert.Len(t, result.Unparsable, 2)
+	assert.Equal(t, uint64(5), result.Unparsable[0].TradeID)
+	assert.NotEmpty(t, result.Unparsable[1].Error)
+
+	// bounded last bucket drops longer trades
+	result, err = AnalyzeHolding(trades, []HoldingBucketConfig{{Name: "day", To: 24 * time.Hour}}, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Buckets[0].TradeCount)
+
+	_, err = AnalyzeHolding(trades, []HoldingBucketConfig{{Name: "a"}, {Name: "b", To: time.Hour}}, time.UTC)
+	assert.Error(t, err)
+	_, err = AnalyzeHolding(trades, []HoldingBucketConfig{{Name: "a", To: time.Hour}, {Name: "b", To: time.Minute}}, time.UTC)
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+
+	"helmsman/internal/model"
+)
+
+// MinMeaningfulSample fewest decisive trades for the sizing estimates to be meaningful
+const MinMeaningfulSample = 30
+
+// z score of a 95% two-sided confidence interval
+const z95 = 1.959964
+
+// Interval a confidence interval
+type Interval struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// WilsonInterval 95% Wilson score interval of a proportion of successes in n trials
+func WilsonInterval(successes int, n int) Interval {
+	if n <= 0 {
+		return Interval{}
+	}
+	p := float64(successes) / float64(n)
+	nf := float64(n)
+	denominator := 1 + z95*z95/nf
+	center := (p + z95*z95/(2*nf)) / denominator
+	margin := z95 * math.Sqrt(p*(1-p)/nf+z95*z95/(4*nf*nf)) / denominator
+	return Interval{Lower: math.Max(0, center-margin), Upper: math.Min(1, center+margin)}
+}
+
+// Kelly optimal fraction of equity to risk per trade for win probability p and payoff ratio b,
+// 0 if there is no edge
+func Kelly(p float64, b float64) float64 {
+	if b <= 0 {
+		return 0
+	}
+	return math.Max(0, p-(1-p)/b)
+}
+
+// RiskOfRuin probability of losing ruinPercent of equity when every trade risks riskPercent of it
+// (a fixed amount, 1R) and wins b R with probability p or loses 1R otherwise. It uses the diffusion
+// approximation exp(-2 * mean * units / variance) where units = ruinPercent / riskPercent.
+func RiskOfRuin(p float64, b float64, riskPercent float64, ruinPercent float64) float64 {
+	if riskPercent <= 0 {
+		return 0
+	}
+	mean := p*b - (1 - p)
+	if mean <= 0 {
+		return 1
+	}
+	variance := p*b*b + (1 - p) - mean*mean
+	if variance <= 0 {
+		return 0
+	}
+	units := ruinPercent / riskPercent
+	return math.Min(1, math.Exp(-2*mean*units/variance))
+}
+
+// RiskReport position sizing statistics of a group of trades
+type RiskReport struct {
+	SampleSize   int      `json:"sampleSize"`   // winning and losing trades, break-even trades are ignored
+	WinRate      float64  `json:"winRate"`      // wins / (wins + losses)
+	WinRateCI    Interval `json:"winRateCI"`    // 95% Wilson interval
+	PayoffRatio  float64  `json:"payoffRatio"`  // average win / average loss in net pnl
+	Kelly        float64  `json:"kelly"`        // full Kelly fraction of equity to risk per trade, 0~1
+	HalfKelly    float64  `json:"halfKelly"`    // half of the full Kelly fraction
+	KellyCI      Interval `json:"kellyCI"`      // full Kelly at the bounds of the win rate interval
+	RiskPercent  float64  `json:"riskPercent"`  // percent of equity risked per trade
+	RuinPercent  float64  `json:"ruinPercent"`  // drawdown percent considered ruin
+	RiskOfRuin   float64  `json:"riskOfRuin"`   // probability of reaching the ruin drawdown, 0~1
+	RiskOfRuinCI Interval `json:"riskOfRuinCI"` // risk of ruin at the bounds of the win rate interval
+	Warnings     []string `json:"warnings"`
+}
+
+// AnalyzeRisk compute the Kelly fraction and risk of ruin from the win rate and payoff ratio of trades
+func AnalyzeRisk(trades []*model.Trades, riskPercent float64, ruinPercent float64) *RiskReport {
+	s := Summarize(trades)
+	r := &RiskReport{
+		SampleSize:  s.WinCount + s.LossCount,
+		RiskPercent: riskPercent,
+		RuinPercent: ruinPercent,
+		Warnings:    []string{},
+	}
+	if r.SampleSize < MinMeaningfulSample {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("sample of %d decisive trades is too small, at least %d are needed for meaningful estimates",
+			r.SampleSize, MinMeaningfulSample))
+	}
+	if s.WinCount == 0 || s.LossCount == 0 {
+		r.Warnings = append(r.Warnings, "payoff ratio needs both winning and losing trades")
+		return r
+	}
+
+	r.WinRate = float64(s.WinCount) / float64(r.SampleSize)
+	r.WinRateCI = WilsonInterval(s.WinCount, r.SampleSize)
+	r.PayoffRatio = s.AvgWin / -s.AvgLoss
+	r.Kelly = Kelly(r.WinRate, r.PayoffRatio)
+	r.HalfKelly = r.Kelly / 2
+	r.KellyCI = Interval{Lower: Kelly(r.WinRateCI.Lower, r.PayoffRatio), Upper: Kelly(r.WinRateCI.Upper, r.PayoffRatio)}
+	r.RiskOfRuin = RiskOfRuin(r.WinRate, r.PayoffRatio, riskPercent, ruinPercent)
+	r.RiskOfRuinCI = Interval{
+		Lower: RiskOfRuin(r.WinRateCI.Upper, r.PayoffRatio, riskPercent, ruinPercent),
+		Upper: RiskOfRuin(r.WinRateCI.Lower, r.PayoffRatio, riskPercent, ruinPercent),
+	}
+	if r.Kelly == 0 {
+		r.Warnings = append(r.Warnings, "no edge, the Kelly fraction is 0")
+	} else if riskPercent/100 > r.Kelly {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("risk per trade of %v%% exceeds the full Kelly fraction of %.2f%%", riskPercent, r.Kelly*100))
+	}
+	return r
+}
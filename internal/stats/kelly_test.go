@@ -0,0 +1,65 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestWilsonInterval(t *testing.T) {
+	assert.Equal(t, Interval{}, WilsonInterval(0, 0))
+
+	ci := WilsonInterval(50, 100)
+	assert.InDelta(t, 0.404, ci.Lower, 0.001)
+	assert.InDelta(t, 0.596, ci.Upper, 0.001)
+
+	ci = WilsonInterval(10, 10)
+	assert.Less(t, ci.Lower, 1.0)
+	assert.InDelta(t, 1, ci.Upper, 1e-9)
+}
+
+func TestKelly(t *testing.T) {
+	assert.InDelta(t, 0.25, Kelly(0.5, 2), 1e-9)
+	assert.Equal(t, 0.0, Kelly(0.3, 1))
+	assert.Equal(t, 0.0, Kelly(0.5, 0))
+}
+
+func TestRiskOfRuin(t *testing.T) {
+	assert.Equal(t, 1.0, RiskOfRuin(0.4, 1, 1, 50))
+	assert.Equal(t, 0.0, RiskOfRuin(0.5, 2, 0, 50))
+
+	low := RiskOfRuin(0.5, 2, 1, 50)
+	high := RiskOfRuin(0.5, 2, 10, 50)
+	assert.Greater(t, low, 0.0)
+	assert.Less(t, low, high)
+	assert.LessOrEqual(t, high, 1.0)
+}
+
+func TestAnalyzeRisk(t *testing.T) {
+	trades := []*model.Trades{}
+	for i := 0; i < 20; i++ {
+		trades = append(trades, &model.Trades{Pnl: 200}, &model.Trades{Pnl: -100})
+	}
+	trades = append(trades, &model.Trades{Pnl: 0})
+
+	r := AnalyzeRisk(trades, 2, 50)
+	assert.Equal(t, 40, r.SampleSize)
+	assert.Equal(t, 0.5, r.WinRate)
+	assert.Equal(t, 2.0, r.PayoffRatio)
+	assert.InDelta(t, 0.25, r.Kelly, 1e-9)
+	assert.InDelta(t, 0.125, r.HalfKelly, 1e-9)
+	assert.Less(t, r.KellyCI.Lower, r.Kelly)
+	assert.Greater(t, r.KellyCI.Upper, r.Kelly)
+	assert.LessOrEqual(t, r.RiskOfRuinCI.Lower, r.RiskOfRuin)
+	assert.GreaterOrEqual(t, r.RiskOfRuinCI.Upper, r.RiskOfRuin)
+	assert.Empty(t, r.Warnings)
+
+	r = AnalyzeRisk(trades[:4], 30, 50)
+	assert.Len(t, r.Warnings, 2) // small sample, risk above Kelly
+
+	r = AnalyzeRisk([]*model.Trades{{Pnl: 10}}, 1, 50)
+	assert.Len(t, r.Warnings, 2)
+	assert.Equal(t, 0.0, r.Kelly)
+}
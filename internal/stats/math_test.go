@@ -0,0 +1,43 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeanStdDev(t *testing.T) {
+	assert.Equal(t, 0.0, Mean(nil))
+	assert.Equal(t, 0.0, StdDev([]float64{1}))
+
+	values := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	assert.Equal(t, 5.0, Mean(values))
+	assert.InDelta(t, 2.138, StdDev(values), 0.001)
+}
+
+func TestSkewness(t *testing.T) {
+	assert.Equal(t, 0.0, Skewness([]float64{1, 2}))
+	assert.Equal(t, 0.0, Skewness([]float64{3, 3, 3}))
+	assert.InDelta(t, 0, Skewness([]float64{1, 2, 3, 4, 5}), 1e-9)
+	assert.Greater(t, Skewness([]float64{-1, -1, -1, -1, 5}), 0.0)
+	assert.Less(t, Skewness([]float64{1, 1, 1, 1, -5}), 0.0)
+}
+
+func TestPercentile(t *testing.T) {
+	assert.Equal(t, 0.0, Percentile(nil, 50))
+
+	sorted := Sorted([]float64{4, 1, 3, 2, 5})
+	assert.Equal(t, []float64{1, 2, 3, 4, 5}, sorted)
+	assert.Equal(t, 1.0, Percentile(sorted, 0))
+	assert.Equal(t, 3.0, Percentile(sorted, 50))
+	assert.Equal(t, 5.0, Percentile(sorted, 100))
+	assert.InDelta(t, 1.4, Percentile(sorted, 10), 1e-9)
+}
+
+func TestCorrelation(t *testing.T) {
+	assert.Equal(t, 0.0, Correlation([]float64{1}, []float64{1}))
+	assert.Equal(t, 0.0, Correlation([]float64{1, 2}, []float64{1}))
+	assert.Equal(t, 0.0, Correlation([]float64{1, 1, 1}, []float64{1, 2, 3}))
+	assert.InDelta(t, 1, Correlation([]float64{1, 2, 3}, []float64{2, 4, 6}), 1e-9)
+	assert.InDelta(t, -1, Correlation([]float64{1, 2, 3}, []float64{3, 2, 1}), 1e-9)
+}
@@ -0,0 +1,186 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// limits of a monte carlo simulation
+const (
+	MaxMonteCarloRuns   = 10000
+	MaxMonteCarloTrades = 1000
+	maxMonteCarloSteps  = 2000000 // runs * trades
+)
+
+// DefaultBandPercentiles percentiles of the equity bands when none is requested
+var DefaultBandPercentiles = []float64{5, 25, 50, 75, 95}
+
+// MonteCarloConfig parameters of a monte carlo simulation, zero values use the defaults
+type MonteCarloConfig struct {
+	Runs                int       // number of simulated paths, default 1000
+	Trades              int       // future trades per path, default 100
+	RiskPercent         float64   // percent of current equity risked per trade (1R), default 1
+	DrawdownPercent     float64   // drawdown threshold in percent, default 20
+	ProfitTargetPercent float64   // profit target in percent of the starting equity, default 20
+	Percentiles         []float64 // percentiles of the equity bands, default DefaultBandPercentiles
+	Seed                int64     // random seed for reproducible results, 0 means random
+}
+
+// EquityBand percentiles of the equity of all paths after a number of trades
+type EquityBand struct {
+	Trade  int                `json:"trade"`  // number of trades taken, 0 is the start
+	Values []*PercentileValue `json:"values"` // equity as a multiple of the starting equity
+}
+
+// MonteCarloResult result of a monte carlo simulation, equity is a multiple of the starting equity
+type MonteCarloResult struct {
+	SampleSize              int                `json:"sampleSize"` // number of historical r_multiple resampled
+	Runs                    int                `json:"runs"`
+	Trades                  int                `json:"trades"`
+	RiskPercent             float64            `json:"riskPercent"`
+	Bands                   []*EquityBand      `json:"bands"`
+	FinalEquity             []*PercentileValue `json:"finalEquity"`
+	MaxDrawdownPercent      []*PercentileValue `json:"maxDrawdownPercent"`
+	DrawdownPercent         float64            `json:"drawdownPercent"`
+	DrawdownProbability     float64            `json:"drawdownProbability"` // share of paths whose drawdown reached drawdownPercent
+	ProfitTargetPercent     float64            `json:"profitTargetPercent"`
+	ProfitTargetProbability float64            `json:"profitTargetProbability"` // share of paths that reached the profit target at any time
+	LossProbability         float64            `json:"lossProbability"`         // share of paths ending below the starting equity
+	RuinProbability         float64            `json:"ruinProbability"`         // share of paths losing all equity
+}
+
+func (c *MonteCarloConfig) setDefaults() error {
+	if c.Runs == 0 {
+		c.Runs = 1000
+	}
+	if c.Trades == 0 {
+		c.Trades = 100
+	}
+	if c.RiskPercent == 0 {
+		c.RiskPercent = 1
+	}
+	if c.DrawdownPercent == 0 {
+		c.DrawdownPercent = 20
+	}
+	if c.ProfitTargetPercent == 0 {
+		c.ProfitTargetPercent = 20
+	}
+	if len(c.Percentiles) == 0 {
+		c.Percentiles = DefaultBandPercentiles
+	}
+
+	switch {
+	case c.Runs < 0 || c.Runs > MaxMonteCarloRuns:
+		return fmt.Errorf("runs must be between 1 and %d", MaxMonteCarloRuns)
+	case c.Trades < 0 || c.Trades > MaxMonteCarloTrades:
+		return fmt.Errorf("trades must be between 1 and %d", MaxMonteCarloTrades)
+	case c.Runs*c.Trades > maxMonteCarloSteps:
+		return fmt.Errorf("runs * trades cannot exceed %d", maxMonteCarloSteps)
+	case c.RiskPercent < 0 || c.RiskPercent > 100:
+		return errors.New("riskPercent must be between 0 and 100")
+	case c.DrawdownPercent < 0 || c.DrawdownPercent > 100:
+		return errors.New("drawdownPercent must be between 0 and 100")
+	case c.ProfitTargetPercent < 0:
+		return errors.New("profitTargetPercent cannot be negative")
+	}
+	for _, p := range c.Percentiles {
+		if p < 0 || p > 100 {
+			return fmt.Errorf("percentile %v is out of range 0~100", p)
+		}
+	}
+	return nil
+}
+
+// MonteCarlo simulate future equity paths by resampling rs with replacement, every trade risks
+// RiskPercent of the current equity, so a trade of r multiplies equity by 1 + r * RiskPercent / 100
+func MonteCarlo(rs []float64, config MonteCarloConfig) (*MonteCarloResult, error) {
+	if len(rs) == 0 {
+		return nil, errors.New("no r_multiple to resample")
+	}
+	if err := config.setDefaults(); err != nil {
+		return nil, err
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed)) //nolint
+
+	// equity[step][run]
+	equity := make([][]float64, config.Trades+1)
+	for step := range equity {
+		equity[step] = make([]float64, config.Runs)
+	}
+	maxDrawdowns := make([]float64, config.Runs)
+	var drawdownHits, targetHits, losses, ruins int
+	risk := config.RiskPercent / 100
+	target := 1 + config.ProfitTargetPercent/100
+	for run := 0; run < config.Runs; run++ {
+		current, peak, maxDrawdown := 1.0, 1.0, 0.0
+		hitTarget := false
+		equity[0][run] = current
+		for step := 1; step <= config.Trades; step++ {
+			if current > 0 {
+				current *= 1 + rs[rng.Intn(len(rs))]*risk
+				if current < 0 {
+					current = 0
+				}
+			}
+			equity[step][run] = current
+			if current > peak {
+				peak = current
+			}
+			if drawdown := (peak - current) / peak * 100; drawdown > maxDrawdown {
+				maxDrawdown = drawdown
+			}
+			if current >= target {
+				hitTarget = true
+			}
+		}
+		maxDrawdowns[run] = maxDrawdown
+		if maxDrawdown >= config.DrawdownPercent {
+			drawdownHits++
+		}
+		if hitTarget {
+			targetHits++
+		}
+		if current < 1 {
+			losses++
+		}
+		if current == 0 {
+			ruins++
+		}
+	}
+
+	runs := float64(config.Runs)
+	result := &MonteCarloResult{
+		SampleSize:              len(rs),
+		Runs:                    config.Runs,
+		Trades:                  config.Trades,
+		RiskPercent:             config.RiskPercent,
+		Bands:                   make([]*EquityBand, 0, len(equity)),
+		MaxDrawdownPercent:      percentileValues(Sorted(maxDrawdowns), config.Percentiles),
+		DrawdownPercent:         config.DrawdownPercent,
+		DrawdownProbability:     float64(drawdownHits) / runs,
+		ProfitTargetPercent:     config.ProfitTargetPercent,
+		ProfitTargetProbability: float64(targetHits) / runs,
+		LossProbability:         float64(losses) / runs,
+		RuinProbability:         float64(ruins) / runs,
+	}
+	for step, values := range equity {
+		result.Bands = append(result.Bands, &EquityBand{Trade: step, Values: percentileValues(Sorted(values), config.Percentiles)})
+	}
+	result.FinalEquity = result.Bands[len(result.Bands)-1].Values
+
+	return result, nil
+}
+
+func percentileValues(sorted []float64, percentiles []float64) []*PercentileValue {
+	values := make([]*PercentileValue, 0, len(percentiles))
+	for _, p := range percentiles {
+		values = append(values, &PercentileValue{Percentile: p, Value: Percentile(sorted, p)})
+	}
+	return values
+}
@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonteCarlo(t *testing.T) {
+	rs := []float64{2, -1, 2, -1, -1, 3}
+	result, err := MonteCarlo(rs, MonteCarloConfig{Runs: 500, Trades: 50, Seed: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, result.SampleSize)
+	assert.Len(t, result.Bands, 51)
+	assert.Len(t, result.Bands[0].Values, len(DefaultBandPercentiles))
+	assert.Equal(t, 1.0, result.Bands[0].Values[0].Value)
+	assert.Equal(t, result.Bands[50].Values, result.FinalEquity)
+	// positive expectancy, the median path grows
+	assert.Greater(t, result.FinalEquity[2].Value, 1.0)
+	assert.LessOrEqual(t, result.FinalEquity[0].Value, result.FinalEquity[4].Value)
+	assert.Greater(t, result.ProfitTargetProbability, 0.5)
+	assert.Less(t, result.LossProbability, 0.5)
+	assert.Equal(t, 0.0, result.RuinProbability)
+
+	// same seed, same result
+	again, err := MonteCarlo(rs, MonteCarloConfig{Runs: 500, Trades: 50, Seed: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, result.FinalEquity, again.FinalEquity)
+
+	// always losing at 100% risk ruins every path on the first trade
+	result, err = MonteCarlo([]float64{-1}, MonteCarloConfig{Runs: 10, Trades: 5, RiskPercent: 100, Seed: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, result.RuinProbability)
+	assert.Equal(t, 1.0, result.DrawdownProbability)
+	assert.Equal(t, 0.0, result.ProfitTargetProbability)
+	assert.Equal(t, 100.0, result.MaxDrawdownPercent[0].Value)
+
+	_, err = MonteCarlo(nil, MonteCarloConfig{})
+	assert.Error(t, err)
+	_, err = MonteCarlo(rs, MonteCarloConfig{Runs: MaxMonteCarloRuns + 1})
+	assert.Error(t, err)
+	_, err = MonteCarlo(rs, MonteCarloConfig{Runs: MaxMonteCarloRuns, Trades: MaxMonteCarloTrades})
+	assert.Error(t, err)
+	_, err = MonteCarlo(rs, MonteCarloConfig{RiskPercent: 101})
+	assert.Error(t, err)
+	_, err = MonteCarlo(rs, MonteCarloConfig{Percentiles: []float64{-1}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package stats
+
+import (
+	"helmsman/internal/model"
+)
+
+// rules of a trade plan
+const (
+	PlanRuleStopSide        = "stop_side"         // the stop loss is on the losing side of the entry
+	PlanRuleTakeProfitSide  = "take_profit_side"  // the take profit is on the winning side of the entry
+	PlanRuleRewardRisk      = "reward_risk"       // the reward:risk meets the minimum of the strategy
+	PlanRuleMaxPositionSize = "max_position_size" // the position size is within the maximum of the account
+)
+
+// PlanLimits limits of the strategy and account of a trade, 0 means no limit
+type PlanLimits struct {
+	MinRewardRisk   float64
+	MaxPositionSize float64
+}
+
+// PlanViolation a rule of the trade plan violated by a field
+type PlanViolation struct {
+	Field string  `json:"field"` // json name of the field in the request
+	Rule  string  `json:"rule"`
+	Value float64 `json:"value"` // value of the field, the planned reward:risk for the reward_risk rule
+	Limit float64 `json:"limit"` // entry price for the side rules, the minimum or maximum otherwise
+}
+
+// PlanEntry entry price a plan is checked against, the planned entry price or the actual entry
+// price if no entry was planned, 0 if neither is set
+func PlanEntry(t *model.Trades) float64 {
+	if t.PlannedEntryPrice > 0 {
+		return t.PlannedEntryPrice
+	}
+	return t.ActualEntryPrice
+}
+
+// PlannedRewardRisk distance from the entry to the take profit divided by the distance to the
+// stop loss, false if either is missing or on the wrong side of the entry
+func PlannedRewardRisk(t *model.Trades) (float64, bool) {
+	sign, entry := DirectionSign(t), PlanEntry(t)
+	if sign == 0 || entry <= 0 || t.PlannedStopLoss <= 0 || t.PlannedTakeProfit <= 0 {
+		return 0, false
+	}
+	risk := sign * (entry - t.PlannedStopLoss)
+	reward := sign * (t.PlannedTakeProfit - entry)
+	if risk <= 0 || reward <= 0 {
+		return 0, false
+	}
+	return reward / risk, true
+}
+
+// CheckPlan violations of the plan rules by a trade, a rule is skipped if the fields it needs
+// are not set, the reward:risk is only checked if the stop loss and take profit are on their sides
+func CheckPlan(t *model.Trades, limits PlanLimits) []*PlanViolation {
+	violations := []*PlanViolation{}
+	sign, entry := DirectionSign(t), PlanEntry(t)
+	if sign != 0 && entry > 0 {
+		if t.PlannedStopLoss > 0 && sign*(entry-t.PlannedStopLoss) <= 0 {
+			violations = append(violations, &PlanViolation{
+				Field: "plannedStopLoss", Rule: PlanRuleStopSide, Value: t.PlannedStopLoss, Limit: entry,
+			})
+		}
+		if t.PlannedTakeProfit > 0 && sign*(t.PlannedTakeProfit-entry) <= 0 {
+			violations = append(violations, &PlanViolation{
+				Field: "plannedTakeProfit", Rule: PlanRuleTakeProfitSide, Value: t.PlannedTakeProfit, Limit: entry,
+			})
+		}
+	}
+	if limits.MinRewardRisk > 0 {
+		// the epsilon keeps a plan exactly at the minimum from failing by floating point errors
+		if rr, ok := PlannedRewardRisk(t); ok && rr < limits.MinRewardRisk-1e-9 {
+			violations = append(violations, &PlanViolation{
+				Field: "plannedTakeProfit", Rule: PlanRuleRewardRisk, Value: rr, Limit: limits.MinRewardRisk,
+			})
+		}
+	}
+	if limits.MaxPositionSize > 0 && t.PositionSize > limits.MaxPositionSize {
+		violations = append(violations, &PlanViolation{
+			Field: "positionSize", Rule: PlanRuleMaxPositionSize, Value: t.PositionSize, Limit: limits.MaxPositionSize,
+		})
+	}
+	return violations
+}
@@ -0,0 +1,57 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestPlannedRewardRisk(t *testing.T) {
+	rr, ok := PlannedRewardRisk(&model.Trades{Direction: "long", PlannedEntryPrice: 100, PlannedStopLoss: 95, PlannedTakeProfit: 110})
+	assert.True(t, ok)
+	assert.Equal(t, 2.0, rr)
+	// actual entry if no entry was planned
+	rr, ok = PlannedRewardRisk(&model.Trades{Direction: "short", ActualEntryPrice: 100, PlannedStopLoss: 102, PlannedTakeProfit: 97})
+	assert.True(t, ok)
+	assert.Equal(t, 1.5, rr)
+
+	_, ok = PlannedRewardRisk(&model.Trades{Direction: "long", PlannedEntryPrice: 100, PlannedStopLoss: 105, PlannedTakeProfit: 110})
+	assert.False(t, ok)
+	_, ok = PlannedRewardRisk(&model.Trades{Direction: "long", PlannedEntryPrice: 100, PlannedStopLoss: 95})
+	assert.False(t, ok)
+}
+
+func TestCheckPlan(t *testing.T) {
+	limits := PlanLimits{MinRewardRisk: 2, MaxPositionSize: 100}
+
+	// valid plan, exactly at the limits
+	trade := &model.Trades{Direction: "long", PlannedEntryPrice: 100, PlannedStopLoss: 95, PlannedTakeProfit: 110, PositionSize: 100}
+	assert.Empty(t, CheckPlan(trade, limits))
+
+	// stop loss and take profit swapped
+	trade = &model.Trades{Direction: "long", PlannedEntryPrice: 100, PlannedStopLoss: 110, PlannedTakeProfit: 95}
+	violations := CheckPlan(trade, limits)
+	if assert.Len(t, violations, 2) {
+		assert.Equal(t, PlanRuleStopSide, violations[0].Rule)
+		assert.Equal(t, "plannedStopLoss", violations[0].Field)
+		assert.Equal(t, 100.0, violations[0].Limit)
+		assert.Equal(t, PlanRuleTakeProfitSide, violations[1].Rule)
+		assert.Equal(t, "plannedTakeProfit", violations[1].Field)
+	}
+
+	// short with a low reward:risk and an oversized position
+	trade = &model.Trades{Direction: "short", PlannedEntryPrice: 100, PlannedStopLoss: 104, PlannedTakeProfit: 96, PositionSize: 150}
+	violations = CheckPlan(trade, limits)
+	if assert.Len(t, violations, 2) {
+		assert.Equal(t, PlanRuleRewardRisk, violations[0].Rule)
+		assert.Equal(t, 1.0, violations[0].Value)
+		assert.Equal(t, 2.0, violations[0].Limit)
+		assert.Equal(t, PlanRuleMaxPositionSize, violations[1].Rule)
+		assert.Equal(t, "positionSize", violations[1].Field)
+	}
+
+	// rules without their fields or limits are skipped
+	assert.Empty(t, CheckPlan(&model.Trades{Direction: "long", PlannedStopLoss: 110, PositionSize: 1000}, PlanLimits{}))
+}
@@ -0,0 +1,43 @@
+package stats
+
+import (
+	"errors"
+
+	"helmsman/internal/model"
+)
+
+// errors of ComputeResult
+var (
+	ErrPnlInputs  = errors.New("direction, position size and actual entry and exit price are required to compute pnl")
+	ErrRiskInputs = errors.New("planned risk amount or a stop loss on the losing side of the entry is required to compute r_multiple")
+)
+
+// InitialRisk amount at risk when the trade was entered, planned_risk_amount if it is set,
+// otherwise the distance from the actual entry price to the planned stop loss times the position size
+func InitialRisk(t *model.Trades) (float64, bool) {
+	if t.PlannedRiskAmount > 0 {
+		return t.PlannedRiskAmount, true
+	}
+	sign := DirectionSign(t)
+	if sign == 0 || t.PlannedStopLoss <= 0 || t.ActualEntryPrice <= 0 || t.PositionSize <= 0 {
+		return 0, false
+	}
+	risk := sign * (t.ActualEntryPrice - t.PlannedStopLoss) * t.PositionSize
+	return risk, risk > 0
+}
+
+// ComputeResult pnl and r_multiple of a closed trade. The pnl is before commission like the
+// recorded pnl, the commission is deducted by NetPnl, and the r_multiple is the net result
+// divided by the initial risk. Without the initial risk the pnl is still returned with
+// ErrRiskInputs.
+func ComputeResult(t *model.Trades) (pnl float64, r float64, err error) {
+	pnl, ok := GrossPnl(t)
+	if !ok {
+		return 0, 0, ErrPnlInputs
+	}
+	risk, ok := InitialRisk(t)
+	if !ok {
+		return pnl, 0, ErrRiskInputs
+	}
+	return pnl, (pnl - t.Commission) / risk, nil
+}
@@ -0,0 +1,49 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestInitialRisk(t *testing.T) {
+	risk, ok := InitialRisk(&model.Trades{PlannedRiskAmount: 200, Direction: "long", PlannedStopLoss: 95, ActualEntryPrice: 100, PositionSize: 10})
+	assert.True(t, ok)
+	assert.Equal(t, 200.0, risk)
+	risk, ok = InitialRisk(&model.Trades{Direction: "short", PlannedStopLoss: 105, ActualEntryPrice: 100, PositionSize: 10})
+	assert.True(t, ok)
+	assert.Equal(t, 50.0, risk)
+	// stop loss on the winning side
+	_, ok = InitialRisk(&model.Trades{Direction: "long", PlannedStopLoss: 105, ActualEntryPrice: 100, PositionSize: 10})
+	assert.False(t, ok)
+	_, ok = InitialRisk(&model.Trades{Direction: "long", ActualEntryPrice: 100, PositionSize: 10})
+	assert.False(t, ok)
+}
+
+func TestComputeResult(t *testing.T) {
+	trade := &model.Trades{
+		Direction: "short", PositionSize: 10, Commission: 10,
+		PlannedStopLoss: 105, ActualEntryPrice: 100, ActualExitPrice: 90,
+	}
+	pnl, r, err := ComputeResult(trade)
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, pnl)
+	assert.InDelta(t, 1.8, r, 1e-9)
+
+	trade.ActualExitPrice = 105
+	pnl, r, err = ComputeResult(trade)
+	assert.NoError(t, err)
+	assert.Equal(t, -50.0, pnl)
+	assert.InDelta(t, -1.2, r, 1e-9)
+
+	trade.PlannedStopLoss = 0
+	pnl, r, err = ComputeResult(trade)
+	assert.ErrorIs(t, err, ErrRiskInputs)
+	assert.Equal(t, -50.0, pnl)
+	assert.Equal(t, 0.0, r)
+	trade.PositionSize = 0
+	_, _, err = ComputeResult(trade)
+	assert.ErrorIs(t, err, ErrPnlInputs)
+}
@@ -0,0 +1,122 @@
+package stats
+
+import (
+	"time"
+
+	"helmsman/internal/model"
+)
+
+// default sizes of the rolling windows
+const (
+	DefaultRollingTrades = 20
+	DefaultRollingDays   = 30
+)
+
+// RollingPoint performance of the trades in a window ending at End
+type RollingPoint struct {
+	End          string   `json:"end"`     // exit time of the last trade of a trade window, or the last day of a day window
+	TradeID      uint64   `json:"tradeID"` // last trade of a trade window, 0 for a day window
+	TradeCount   int      `json:"tradeCount"`
+	WinRate      float64  `json:"winRate"`
+	Expectancy   float64  `json:"expectancy"`
+	ProfitFactor *float64 `json:"profitFactor"` // null if there is no losing trade in the window
+	AvgR         float64  `json:"avgR"`
+}
+
+func newRollingPoint(end string, tradeID uint64, trades []*model.Trades) *RollingPoint {
+	s := Summarize(trades)
+	return &RollingPoint{
+		End:          end,
+		TradeID:      tradeID,
+		TradeCount:   s.TradeCount,
+		WinRate:      s.WinRate,
+		Expectancy:   s.Expectancy,
+		ProfitFactor: s.ProfitFactor,
+		AvgR:         s.AvgR,
+	}
+}
+
+// RollingByTrades metrics of every window of n consecutive trades, trades must be ordered by
+// exit time, the first point is at the n-th trade.
+func RollingByTrades(trades []*model.Trades, n int) []*RollingPoint {
+	points := []*RollingPoint{}
+	if n <= 0 {
+		return points
+	}
+	for i := n; i <= len(trades); i++ {
+		last := trades[i-1]
+		points = append(points, newRollingPoint(last.ActualExitTime, last.ID, trades[i-n:i]))
+	}
+	return points
+}
+
+// RollingByDays metrics of the trades closed in the n calendar days up to and including every day
+// in loc, trades must be ordered by exit time and have a parsable exit time. The first point is at
+// the n-th day after the first trade, days without trades are included so that gaps are visible.
+func RollingByDays(trades []*model.Trades, n int, loc *time.Location) []*RollingPoint {
+	points := []*RollingPoint{}
+	if n <= 0 || len(trades) == 0 {
+		return points
+	}
+	days := make([]time.Time, 0, len(trades))
+	for _, t := range trades {
+		exitTime, err := ParseTime(t.ActualExitTime, loc)
+		if err != nil {
+			return points
+		}
+		days = append(days, startOfDay(exitTime))
+	}
+
+	first, last := days[0], days[len(days)-1]
+	from, to := 0, 0 // trades[from:to] are in the window
+	for day := first.AddDate(0, 0, n-1); !day.After(last); day = day.AddDate(0, 0, 1) {
+		windowStart := day.AddDate(0, 0, 1-n)
+		for to < len(days) && !days[to].After(day) {
+			to++
+		}
+		for from < to && days[from].Before(windowStart) {
+			from++
+		}
+		points = append(points, newRollingPoint(day.Format("2006-01-02"), 0, trades[from:to]))
+	}
+	return points
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// RollingReport rolling window series to detect the decay of an edge
+type RollingReport struct {
+	Trades          int             `json:"trades"` // size of the trade window
+	Days            int             `json:"days"`   // size of the day window
+	ByTrades        []*RollingPoint `json:"byTrades"`
+	ByDays          []*RollingPoint `json:"byDays"`
+	UnparsableCount int             `json:"unparsableCount"` // trades excluded because of an unparsable exit time
+}
+
+// AnalyzeRolling compute the rolling n-trade and n-day series of trades in loc, a non-positive
+// window size uses the default.
+func AnalyzeRolling(trades []*model.Trades, nTrades int, nDays int, loc *time.Location) *RollingReport {
+	if nTrades <= 0 {
+		nTrades = DefaultRollingTrades
+	}
+	if nDays <= 0 {
+		nDays = DefaultRollingDays
+	}
+	parsable := make([]*model.Trades, 0, len(trades))
+	for _, t := range trades {
+		if _, err := ParseTime(t.ActualExitTime, loc); err == nil {
+			parsable = append(parsable, t)
+		}
+	}
+	SortByExitTime(parsable, loc)
+
+	return &RollingReport{
+		Trades:          nTrades,
+		Days:            nDays,
+		ByTrades:        RollingByTrades(parsable, nTrades),
+		ByDays:          RollingByDays(parsable, nDays, loc),
+		UnparsableCount: len(trades) - len(parsable),
+	}
+}
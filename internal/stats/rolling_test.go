@@ -0,0 +1,66 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestRollingByTrades(t *testing.T) {
+	trades := []*model.Trades{
+		{ID: 1, Pnl: 100, RMultiple: 1},
+		{ID: 2, Pnl: -50, RMultiple: -0.5},
+		{ID: 3, Pnl: -50, RMultiple: -0.5},
+		{ID: 4, Pnl: -100, RMultiple: -1},
+	}
+	points := RollingByTrades(trades, 2)
+	assert.Len(t, points, 3)
+	assert.Equal(t, uint64(2), points[0].TradeID)
+	assert.Equal(t, 0.5, points[0].WinRate)
+	assert.Equal(t, 25.0, points[0].Expectancy)
+	assert.Equal(t, 2.0, *points[0].ProfitFactor)
+	assert.Equal(t, 0.0, points[2].WinRate)
+	assert.Equal(t, -0.75, points[2].AvgR)
+
+	assert.Empty(t, RollingByTrades(trades, 5))
+	assert.Empty(t, RollingByTrades(trades, 0))
+}
+
+func TestRollingByDays(t *testing.T) {
+	trades := []*model.Trades{
+		{ID: 1, ActualExitTime: "2024-01-01 10:00:00", Pnl: 100, RMultiple: 1},
+		{ID: 2, ActualExitTime: "2024-01-02 23:59:00", Pnl: -100, RMultiple: -1},
+		{ID: 3, ActualExitTime: "2024-01-05 09:00:00", Pnl: 200, RMultiple: 2},
+	}
+	points := RollingByDays(trades, 2, time.UTC)
+	assert.Len(t, points, 4) // 01-02 ~ 01-05
+	assert.Equal(t, "2024-01-02", points[0].End)
+	assert.Equal(t, 2, points[0].TradeCount)
+	assert.Equal(t, 1, points[1].TradeCount)
+	assert.Equal(t, 0, points[2].TradeCount)
+	assert.Equal(t, 1, points[3].TradeCount)
+	assert.Equal(t, 2.0, points[3].AvgR)
+
+	assert.Empty(t, RollingByDays(trades, 10, time.UTC))
+	assert.Empty(t, RollingByDays(nil, 2, time.UTC))
+}
+
+func TestAnalyzeRolling(t *testing.T) {
+	trades := []*model.Trades{
+		{ID: 2, ActualExitTime: "2024-01-02 10:00:00", Pnl: -100, RMultiple: -1},
+		{ID: 1, ActualExitTime: "2024-01-01 10:00:00", Pnl: 100, RMultiple: 1},
+		{ID: 3, ActualExitTime: "unknown", Pnl: 100, RMultiple: 1},
+	}
+	report := AnalyzeRolling(trades, 2, 1, time.UTC)
+	assert.Equal(t, 1, report.UnparsableCount)
+	assert.Len(t, report.ByTrades, 1)
+	assert.Equal(t, uint64(2), report.ByTrades[0].TradeID)
+	assert.Len(t, report.ByDays, 2)
+
+	report = AnalyzeRolling(trades, 0, 0, time.UTC)
+	assert.Equal(t, DefaultRollingTrades, report.Trades)
+	assert.Equal(t, DefaultRollingDays, report.Days)
+}
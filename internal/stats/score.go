@@ -0,0 +1,112 @@
+package stats
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"helmsman/internal/model"
+)
+
+// execution score range
+const (
+	MinExecutionScore = 1
+	MaxExecutionScore = 5
+)
+
+// score correlation periods
+const (
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// ScoreCorrelationPoint correlation between execution score and r_multiple of the trades closed in a period
+type ScoreCorrelationPoint struct {
+	Period      string  `json:"period"` // 2006-01 for months, the Monday 2006-01-02 for weeks
+	TradeCount  int     `json:"tradeCount"`
+	AvgScore    float64 `json:"avgScore"`
+	AvgR        float64 `json:"avgR"`
+	Correlation float64 `json:"correlation"`
+}
+
+// ScoreReport outcome of trades by execution score
+type ScoreReport struct {
+	ByScore         []*Bucket                `json:"byScore"`         // one bucket per score from 1 to 5
+	Unscored        *Summary                 `json:"unscored"`        // trades without a valid score
+	Correlation     float64                  `json:"correlation"`     // Pearson correlation of score and r_multiple over all scored trades
+	ByPeriod        []*ScoreCorrelationPoint `json:"byPeriod"`        // in chronological order
+	UnparsableCount int                      `json:"unparsableCount"` // scored trades left out of byPeriod because of an unparsable exit time
+}
+
+// AnalyzeExecutionScore group trades by execution score and correlate the score with
+// r_multiple overall and per period (week or month) of the exit time in loc
+func AnalyzeExecutionScore(trades []*model.Trades, period string, loc *time.Location) (*ScoreReport, error) {
+	var periodKey func(t time.Time) string
+	switch period {
+	case PeriodMonth, "":
+		periodKey = func(t time.Time) string { return t.Format("2006-01") }
+	case PeriodWeek:
+		periodKey = func(t time.Time) string {
+			weekday := (int(t.Weekday()) + 6) % 7 // Monday first
+			return t.AddDate(0, 0, -weekday).Format("2006-01-02")
+		}
+	default:
+		return nil, errors.New("period must be week or month")
+	}
+
+	groups, _ := GroupBy(trades, func(t *model.Trades) int {
+		if t.ExecutionScore < MinExecutionScore || t.ExecutionScore > MaxExecutionScore {
+			return 0
+		}
+		return t.ExecutionScore
+	})
+	report := &ScoreReport{
+		Unscored: Summarize(groups[0]),
+		ByPeriod: []*ScoreCorrelationPoint{},
+	}
+	var scores, rs []float64
+	for score := MinExecutionScore; score <= MaxExecutionScore; score++ {
+		report.ByScore = append(report.ByScore, &Bucket{Key: strconv.Itoa(score), Summary: *Summarize(groups[score])})
+		for _, t := range groups[score] {
+			scores = append(scores, float64(t.ExecutionScore))
+			rs = append(rs, t.RMultiple)
+		}
+	}
+	report.Correlation = Correlation(scores, rs)
+
+	type periodValues struct{ scores, rs []float64 }
+	periods := map[string]*periodValues{}
+	for _, t := range trades {
+		if t.ExecutionScore < MinExecutionScore || t.ExecutionScore > MaxExecutionScore {
+			continue
+		}
+		exitTime, err := ParseTime(t.ActualExitTime, loc)
+		if err != nil {
+			report.UnparsableCount++
+			continue
+		}
+		key := periodKey(exitTime)
+		values, ok := periods[key]
+		if !ok {
+			values = &periodValues{}
+			periods[key] = values
+		}
+		values.scores = append(values.scores, float64(t.ExecutionScore))
+		values.rs = append(values.rs, t.RMultiple)
+	}
+	for key, values := range periods {
+		report.ByPeriod = append(report.ByPeriod, &ScoreCorrelationPoint{
+			Period:      key,
+			TradeCount:  len(values.scores),
+			AvgScore:    Mean(values.scores),
+			AvgR:        Mean(values.rs),
+			Correlation: Correlation(values.scores, values.rs),
+		})
+	}
+	sort.Slice(report.ByPeriod, func(i, j int) bool {
+		return report.ByPeriod[i].Period < report.ByPeriod[j].Period
+	})
+
+	return report, nil
+}
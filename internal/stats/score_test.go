@@ -0,0 +1,43 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestAnalyzeExecutionScore(t *testing.T) {
+	trades := []*model.Trades{
+		{ID: 1, ExecutionScore: 5, RMultiple: 2, Pnl: 200, ActualExitTime: "2024-01-03 10:00:00"},
+		{ID: 2, ExecutionScore: 4, RMultiple: 1, Pnl: 100, ActualExitTime: "2024-01-10 10:00:00"},
+		{ID: 3, ExecutionScore: 1, RMultiple: -1, Pnl: -100, ActualExitTime: "2024-01-11 10:00:00"},
+		{ID: 4, ExecutionScore: 2, RMultiple: -1, Pnl: -100, ActualExitTime: "2024-02-01 10:00:00"},
+		{ID: 5, ExecutionScore: 5, RMultiple: 1, Pnl: 100, ActualExitTime: "2024-02-02 10:00:00"},
+		{ID: 6, ExecutionScore: 0, RMultiple: 3, Pnl: 300, ActualExitTime: "2024-02-03 10:00:00"},
+		{ID: 7, ExecutionScore: 3, RMultiple: 0, ActualExitTime: "bad"},
+	}
+	report, err := AnalyzeExecutionScore(trades, "", time.UTC)
+	assert.NoError(t, err)
+	assert.Len(t, report.ByScore, 5)
+	assert.Equal(t, "5", report.ByScore[4].Key)
+	assert.Equal(t, 2, report.ByScore[4].TradeCount)
+	assert.Equal(t, 1, report.Unscored.TradeCount)
+	assert.Greater(t, report.Correlation, 0.8)
+	assert.Equal(t, 1, report.UnparsableCount)
+	assert.Len(t, report.ByPeriod, 2)
+	assert.Equal(t, "2024-01", report.ByPeriod[0].Period)
+	assert.Equal(t, 3, report.ByPeriod[0].TradeCount)
+	assert.Equal(t, "2024-02", report.ByPeriod[1].Period)
+	assert.InDelta(t, 1, report.ByPeriod[1].Correlation, 1e-9)
+
+	report, err = AnalyzeExecutionScore(trades, PeriodWeek, time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-01-01", report.ByPeriod[0].Period) // Wednesday 2024-01-03 is in the week of Monday 2024-01-01
+	assert.Equal(t, "2024-01-08", report.ByPeriod[1].Period)
+
+	_, err = AnalyzeExecutionScore(trades, "year", time.UTC)
+	assert.Error(t, err)
+}
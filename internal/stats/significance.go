@@ -0,0 +1,169 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"helmsman/internal/model"
+)
+
+// DefaultBootstrapResamples bootstrap resamples if none are given
+const DefaultBootstrapResamples = 2000
+
+// significance level of the t-test
+const significanceLevel = 0.05
+
+// SQN System Quality Number of r multiples, sqrt(n) * mean / standard deviation where n is
+// capped at 100 so that a large sample does not inflate the score, 0 if there is no variance
+func SQN(rs []float64) float64 {
+	sd := StdDev(rs)
+	if sd == 0 {
+		return 0
+	}
+	return math.Sqrt(math.Min(float64(len(rs)), 100)) * Mean(rs) / sd
+}
+
+// TTest one-sample t-test of the mean of values against zero, returning the t statistic and the
+// two-sided p-value, ok is false if there are less than 2 values or no variance
+func TTest(values []float64) (t float64, p float64, ok bool) {
+	n := len(values)
+	sd := StdDev(values)
+	if n < 2 || sd == 0 {
+		return 0, 1, false
+	}
+	t = Mean(values) / (sd / math.Sqrt(float64(n)))
+	return t, StudentTTwoSided(t, float64(n-1)), true
+}
+
+// StudentTTwoSided probability of a Student's t statistic with df degrees of freedom being at
+// least as extreme as t in either direction
+func StudentTTwoSided(t float64, df float64) float64 {
+	return regularizedIncompleteBeta(df/(df+t*t), df/2, 0.5)
+}
+
+// regularizedIncompleteBeta I_x(a, b) evaluated with the continued fraction of Numerical Recipes
+func regularizedIncompleteBeta(x float64, a float64, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	lgab, _ := math.Lgamma(a + b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+func betaContinuedFraction(x float64, a float64, b float64) float64 {
+	const (
+		maxIterations = 300
+		epsilon       = 1e-14
+		tiny          = 1e-300
+	)
+	clamp := func(v float64) float64 {
+		if math.Abs(v) < tiny {
+			return tiny
+		}
+		return v
+	}
+	c, d := 1.0, 1/clamp(1-(a+b)*x/(a+1))
+	h := d
+	for m := 1.0; m <= maxIterations; m++ {
+		aa := m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m))
+		d = 1 / clamp(1+aa*d)
+		c = clamp(1 + aa/c)
+		h *= d * c
+		aa = -(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1))
+		d = 1 / clamp(1+aa*d)
+		c = clamp(1 + aa/c)
+		h *= d * c
+		if math.Abs(d*c-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
+
+// BootstrapMeanInterval 95% percentile bootstrap interval of the mean of values
+func BootstrapMeanInterval(values []float64, resamples int, rng *rand.Rand) Interval {
+	n := len(values)
+	if n == 0 || resamples <= 0 {
+		return Interval{}
+	}
+	means := make([]float64, resamples)
+	for i := range means {
+		sum := 0.0
+		for j := 0; j < n; j++ {
+			sum += values[rng.Intn(n)]
+		}
+		means[i] = sum / float64(n)
+	}
+	means = Sorted(means)
+	return Interval{Lower: Percentile(means, 2.5), Upper: Percentile(means, 97.5)}
+}
+
+// SignificanceReport whether the edge of a group of trades is distinguishable from zero
+type SignificanceReport struct {
+	SampleSize    int      `json:"sampleSize"`
+	MeanR         float64  `json:"meanR"`
+	StdDevR       float64  `json:"stdDevR"`
+	SQN           float64  `json:"sqn"`           // System Quality Number
+	TStatistic    float64  `json:"tStatistic"`    // t statistic of mean R against zero
+	PValue        float64  `json:"pValue"`        // two-sided p-value of the t-test
+	ExpectancyCI  Interval `json:"expectancyCI"`  // 95% bootstrap interval of the net pnl per trade
+	ExpectancyRCI Interval `json:"expectancyRCI"` // 95% bootstrap interval of the mean R
+	Significant   bool     `json:"significant"`   // the t-test rejects a zero mean R at 5% and the bootstrap interval of R excludes 0
+	Warnings      []string `json:"warnings"`
+}
+
+// AnalyzeSignificance test whether the mean R of trades differs from zero, every call resamples
+// with its own generator seeded by seed so that the result does not depend on other calls.
+func AnalyzeSignificance(trades []*model.Trades, resamples int, seed int64) *SignificanceReport {
+	if resamples <= 0 {
+		resamples = DefaultBootstrapResamples
+	}
+	rs := make([]float64, 0, len(trades))
+	pnls := make([]float64, 0, len(trades))
+	for _, t := range trades {
+		rs = append(rs, t.RMultiple)
+		pnls = append(pnls, NetPnl(t))
+	}
+
+	r := &SignificanceReport{
+		SampleSize: len(trades),
+		MeanR:      Mean(rs),
+		StdDevR:    StdDev(rs),
+		SQN:        SQN(rs),
+		PValue:     1,
+		Warnings:   []string{},
+	}
+	if r.SampleSize < MinMeaningfulSample {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("sample of %d trades is too small, at least %d are needed for meaningful estimates",
+			r.SampleSize, MinMeaningfulSample))
+	}
+	if r.SampleSize == 0 {
+		return r
+	}
+
+	rng := rand.New(rand.NewSource(seed)) //nolint
+	r.ExpectancyCI = BootstrapMeanInterval(pnls, resamples, rng)
+	r.ExpectancyRCI = BootstrapMeanInterval(rs, resamples, rng)
+
+	t, p, ok := TTest(rs)
+	if !ok {
+		r.Warnings = append(r.Warnings, "t-test needs at least 2 trades with different r_multiple")
+		return r
+	}
+	r.TStatistic, r.PValue = t, p
+	r.Significant = p < significanceLevel && (r.ExpectancyRCI.Lower > 0 || r.ExpectancyRCI.Upper < 0)
+	if !r.Significant {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("edge is not statistically distinguishable from zero (p = %.3f)", p))
+	}
+	return r
+}
@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestSQN(t *testing.T) {
+	assert.InDelta(t, 2*1.0/StdDev([]float64{2, -1, 3, 0}), SQN([]float64{2, -1, 3, 0}), 1e-9)
+	assert.Equal(t, 0.0, SQN([]float64{1, 1}))
+	assert.Equal(t, 0.0, SQN(nil))
+}
+
+func TestStudentTTwoSided(t *testing.T) {
+	// critical values of the two-sided 5% test
+	assert.InDelta(t, 0.05, StudentTTwoSided(2.228, 10), 1e-4)
+	assert.InDelta(t, 0.05, StudentTTwoSided(12.706, 1), 1e-4)
+	assert.InDelta(t, 0.05, StudentTTwoSided(-1.962, 1000), 1e-3)
+	assert.InDelta(t, 1, StudentTTwoSided(0, 5), 1e-12)
+}
+
+func TestTTest(t *testing.T) {
+	tStat, p, ok := TTest([]float64{1, 2, 3, 4, 5})
+	assert.True(t, ok)
+	assert.InDelta(t, 4.2426, tStat, 1e-4)
+	assert.InDelta(t, 0.0132, p, 1e-4)
+
+	_, p, ok = TTest([]float64{1, 1, 1})
+	assert.False(t, ok)
+	assert.Equal(t, 1.0, p)
+}
+
+func TestBootstrapMeanInterval(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	ci := BootstrapMeanInterval([]float64{1, 2, 3, 4, 5}, 1000, rng)
+	assert.True(t, ci.Lower >= 1 && ci.Lower < 3)
+	assert.True(t, ci.Upper > 3 && ci.Upper <= 5)
+	assert.Equal(t, Interval{}, BootstrapMeanInterval(nil, 1000, rng))
+}
+
+func TestAnalyzeSignificance(t *testing.T) {
+	trades := []*model.Trades{}
+	for i := 0; i < 40; i++ {
+		r := 2.0
+		if i%3 == 0 {
+			r = -1
+		}
+		trades = append(trades, &model.Trades{ID: uint64(i + 1), RMultiple: r, Pnl: r * 100})
+	}
+	report := AnalyzeSignificance(trades, 500, 1)
+	assert.Equal(t, 40, report.SampleSize)
+	assert.True(t, report.Significant)
+	assert.Less(t, report.PValue, 0.001)
+	assert.Greater(t, report.ExpectancyRCI.Lower, 0.0)
+	assert.Greater(t, report.ExpectancyCI.Lower, 0.0)
+	assert.Empty(t, report.Warnings)
+	assert.Equal(t, report, AnalyzeSignificance(trades, 500, 1))
+
+	// 15 lucky trades
+	report = AnalyzeSignificance(trades[:15:15], 500, 1)
+	assert.Len(t, report.Warnings, 1)
+
+	report = AnalyzeSignificance([]*model.Trades{{RMultiple: 1}, {RMultiple: -1}, {RMultiple: 1.2}}, 0, 1)
+	assert.False(t, report.Significant)
+	assert.Len(t, report.Warnings, 2)
+
+	report = AnalyzeSignificance(nil, 0, 1)
+	assert.False(t, report.Significant)
+	assert.Equal(t, 1.0, report.PValue)
+}
@@ -0,0 +1,130 @@
+package stats
+
+import (
+	"errors"
+	"math"
+
+	"helmsman/internal/model"
+)
+
+// errors of SizePosition
+var (
+	ErrRiskSettings = errors.New("risk percent or fixed risk amount of the account is required to size a position")
+	ErrStopDistance = errors.New("stop loss must differ from the entry price")
+	ErrStopSide     = errors.New("stop loss must be below the entry for a long and above it for a short")
+	ErrBalance      = errors.New("current balance of the account must be positive to risk a percent of it")
+	ErrSizeTooSmall = errors.New("the risk budget is smaller than the risk of one quantity step")
+)
+
+// defaultPointValue value of a price move of 1 per unit if no point value is given
+const defaultPointValue = 1.0
+
+// PositionSizing position size of a planned trade derived from the risk settings of its account
+type PositionSizing struct {
+	Symbol            string  `json:"symbol"`
+	Direction         string  `json:"direction"` // implied by the side of the stop loss if not given
+	Balance           float64 `json:"balance"`   // initial balance plus the net pnl of the closed trades
+	RiskMode          string  `json:"riskMode"`
+	RiskBudget        float64 `json:"riskBudget"`        // amount the account allows to risk on the trade
+	RiskPerUnit       float64 `json:"riskPerUnit"`       // loss of one unit if the stop loss is hit
+	PositionSize      float64 `json:"positionSize"`      // rounded down to the quantity step, at most the max position size
+	PlannedRiskAmount float64 `json:"plannedRiskAmount"` // risk of the rounded position size, at most the budget
+	MaxPositionSize   float64 `json:"maxPositionSize"`   // max position size of the account, 0 means no limit
+	Capped            bool    `json:"capped"`            // the position size is limited by the max position size instead of the budget
+}
+
+// Balance current balance of an account, the initial balance plus the net pnl of its closed trades
+func Balance(initialBalance float64, trades []*model.Trades) float64 {
+	balance := initialBalance
+	for _, t := range trades {
+		balance += NetPnl(t)
+	}
+	return balance
+}
+
+// AccountRiskMode risk mode of an account, an account without an explicit mode uses the
+// fixed amount only if no percent is set, an empty string is returned if nothing is set.
+func AccountRiskMode(a *model.Accounts) string {
+	switch a.RiskMode {
+	case model.AccountsRiskModePercent, model.AccountsRiskModeAmount:
+		return a.RiskMode
+	}
+	if a.RiskPercent > 0 {
+		return model.AccountsRiskModePercent
+	}
+	if a.RiskAmount > 0 {
+		return model.AccountsRiskModeAmount
+	}
+	return ""
+}
+
+// RiskBudget amount an account allows to risk on a trade at the balance
+func RiskBudget(a *model.Accounts, balance float64) (float64, error) {
+	switch AccountRiskMode(a) {
+	case model.AccountsRiskModePercent:
+		if a.RiskPercent <= 0 {
+			return 0, ErrRiskSettings
+		}
+		if balance <= 0 {
+			return 0, ErrBalance
+		}
+		return balance * a.RiskPercent / 100, nil
+	case model.AccountsRiskModeAmount:
+		if a.RiskAmount <= 0 {
+			return 0, ErrRiskSettings
+		}
+		return a.RiskAmount, nil
+	}
+	return 0, ErrRiskSettings
+}
+
+// SizePosition largest position whose loss at the stop loss is within the risk budget of the
+// account and that does not exceed its max position size. direction may be empty, pointValue is
+// the value of a price move of 1 per unit and defaults to 1, the size is rounded down to a
+// multiple of quantityStep if it is set.
+func SizePosition(a *model.Accounts, balance float64, direction string, entry float64, stop float64,
+	pointValue float64, quantityStep float64) (*PositionSizing, error) {
+	if entry == stop {
+		return nil, ErrStopDistance
+	}
+	implied := model.TradesDirectionLong
+	if stop > entry {
+		implied = model.TradesDirectionShort
+	}
+	if direction != "" && NormalizeDirection(direction) != implied {
+		return nil, ErrStopSide
+	}
+	if pointValue <= 0 {
+		pointValue = defaultPointValue
+	}
+
+	budget, err := RiskBudget(a, balance)
+	if err != nil {
+		return nil, err
+	}
+	riskPerUnit := math.Abs(entry-stop) * pointValue
+	size := budget / riskPerUnit
+	capped := a.MaxPositionSize > 0 && size > a.MaxPositionSize
+	if capped {
+		size = a.MaxPositionSize
+	}
+	if quantityStep > 0 {
+		// the epsilon keeps exact multiples from being rounded down by floating point errors
+		size = math.Floor(size/quantityStep+1e-9) * quantityStep
+		if size == 0 {
+			return nil, ErrSizeTooSmall
+		}
+	}
+
+	return &PositionSizing{
+		Direction:         implied,
+		Balance:           balance,
+		RiskMode:          AccountRiskMode(a),
+		RiskBudget:        budget,
+		RiskPerUnit:       riskPerUnit,
+		PositionSize:      size,
+		PlannedRiskAmount: size * riskPerUnit,
+		MaxPositionSize:   a.MaxPositionSize,
+		Capped:            capped,
+	}, nil
+}
@@ -0,0 +1,84 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestBalance(t *testing.T) {
+	assert.Equal(t, 10000.0, Balance(10000, nil))
+	assert.Equal(t, 10180.0, Balance(10000, []*model.Trades{{Pnl: 200, Commission: 10}, {Pnl: -10}}))
+}
+
+func TestAccountRiskMode(t *testing.T) {
+	assert.Equal(t, "", AccountRiskMode(&model.Accounts{}))
+	assert.Equal(t, model.AccountsRiskModePercent, AccountRiskMode(&model.Accounts{RiskPercent: 1, RiskAmount: 100}))
+	assert.Equal(t, model.AccountsRiskModeAmount, AccountRiskMode(&model.Accounts{RiskAmount: 100}))
+	assert.Equal(t, model.AccountsRiskModeAmount, AccountRiskMode(&model.Accounts{RiskMode: "amount", RiskPercent: 1, RiskAmount: 100}))
+}
+
+func TestRiskBudget(t *testing.T) {
+	budget, err := RiskBudget(&model.Accounts{RiskPercent: 2}, 5000)
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, budget)
+	budget, err = RiskBudget(&model.Accounts{RiskMode: "amount", RiskPercent: 2, RiskAmount: 250}, 5000)
+	assert.NoError(t, err)
+	assert.Equal(t, 250.0, budget)
+
+	_, err = RiskBudget(&model.Accounts{}, 5000)
+	assert.ErrorIs(t, err, ErrRiskSettings)
+	_, err = RiskBudget(&model.Accounts{RiskMode: "amount", RiskPercent: 2}, 5000)
+	assert.ErrorIs(t, err, ErrRiskSettings)
+	_, err = RiskBudget(&model.Accounts{RiskPercent: 2}, -100)
+	assert.ErrorIs(t, err, ErrBalance)
+}
+
+func TestSizePosition(t *testing.T) {
+	account := &model.Accounts{RiskPercent: 1}
+
+	// long, no rounding
+	sizing, err := SizePosition(account, 10000, "", 50, 48, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TradesDirectionLong, sizing.Direction)
+	assert.Equal(t, 100.0, sizing.RiskBudget)
+	assert.Equal(t, 2.0, sizing.RiskPerUnit)
+	assert.Equal(t, 50.0, sizing.PositionSize)
+	assert.Equal(t, 100.0, sizing.PlannedRiskAmount)
+
+	// short with a contract multiplier, rounded down to whole contracts
+	sizing, err = SizePosition(account, 10000, "sell", 100, 103, 10, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TradesDirectionShort, sizing.Direction)
+	assert.Equal(t, 30.0, sizing.RiskPerUnit)
+	assert.Equal(t, 3.0, sizing.PositionSize)
+	assert.Equal(t, 90.0, sizing.PlannedRiskAmount)
+
+	// exact multiple of a fractional step
+	sizing, err = SizePosition(&model.Accounts{RiskAmount: 30}, 0, "long", 1.3, 1, 0, 0.1)
+	assert.NoError(t, err)
+	assert.InDelta(t, 100, sizing.PositionSize, 1e-9)
+
+	// capped by the max position size of the account and rounded down to the step
+	sizing, err = SizePosition(&model.Accounts{RiskPercent: 1, MaxPositionSize: 20.5}, 10000, "", 50, 48, 0, 1)
+	assert.NoError(t, err)
+	assert.True(t, sizing.Capped)
+	assert.Equal(t, 20.0, sizing.PositionSize)
+	assert.Equal(t, 40.0, sizing.PlannedRiskAmount)
+	assert.Equal(t, 20.5, sizing.MaxPositionSize)
+	sizing, err = SizePosition(&model.Accounts{RiskPercent: 1, MaxPositionSize: 60}, 10000, "", 50, 48, 0, 0)
+	assert.NoError(t, err)
+	assert.False(t, sizing.Capped)
+	assert.Equal(t, 50.0, sizing.PositionSize)
+
+	_, err = SizePosition(account, 10000, "long", 100, 103, 0, 0)
+	assert.ErrorIs(t, err, ErrStopSide)
+	_, err = SizePosition(account, 10000, "", 100, 100, 0, 0)
+	assert.ErrorIs(t, err, ErrStopDistance)
+	_, err = SizePosition(account, 10000, "", 300, 1, 0, 1)
+	assert.ErrorIs(t, err, ErrSizeTooSmall)
+	_, err = SizePosition(&model.Accounts{}, 10000, "", 100, 99, 0, 0)
+	assert.ErrorIs(t, err, ErrRiskSettings)
+}
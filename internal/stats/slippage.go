@@ -0,0 +1,173 @@
+package stats
+
+import (
+	"strconv"
+
+	"helmsman/internal/model"
+)
+
+// TradeSlippage planned vs actual execution of a trade, a metric is nil if the prices it depends on are missing
+type TradeSlippage struct {
+	TradeID        uint64   `json:"tradeID"`
+	AccountID      int      `json:"accountID"`
+	StrategyID     int      `json:"strategyID"`
+	Symbol         string   `json:"symbol"`
+	Direction      string   `json:"direction"`
+	EntrySlippage  *float64 `json:"entrySlippage"`  // actual minus planned entry price in the trade direction, positive means a worse fill
+	EntrySlippageR *float64 `json:"entrySlippageR"` // entry slippage in units of the planned risk per unit, |planned entry - stop loss|
+	CaptureRatio   *float64 `json:"captureRatio"`   // (exit - actual entry) / (take profit - actual entry), 1 means the target was fully captured
+	PlannedRisk    *float64 `json:"plannedRisk"`    // planned_risk_amount, or |planned entry - stop loss| * position size if it is not set
+	ActualRisk     *float64 `json:"actualRisk"`     // |actual entry - stop loss| * position size
+	RiskRatio      *float64 `json:"riskRatio"`      // actual risk / planned risk, above 1 means more was risked than planned
+}
+
+// Slippage compute the planned vs actual execution of a trade
+func Slippage(t *model.Trades) *TradeSlippage {
+	s := &TradeSlippage{
+		TradeID:    t.ID,
+		AccountID:  t.AccountID,
+		StrategyID: t.StrategyID,
+		Symbol:     t.Symbol,
+		Direction:  Direction(t),
+	}
+	sign := DirectionSign(t)
+	if sign == 0 {
+		return s
+	}
+
+	if t.PlannedEntryPrice > 0 && t.ActualEntryPrice > 0 {
+		slippage := sign * (t.ActualEntryPrice - t.PlannedEntryPrice)
+		s.EntrySlippage = &slippage
+		if riskPerUnit := sign * (t.PlannedEntryPrice - t.PlannedStopLoss); t.PlannedStopLoss > 0 && riskPerUnit > 0 {
+			slippageR := slippage / riskPerUnit
+			s.EntrySlippageR = &slippageR
+		}
+	}
+
+	if t.ActualEntryPrice > 0 && t.ActualExitPrice > 0 && t.PlannedTakeProfit > 0 {
+		if target := sign * (t.PlannedTakeProfit - t.ActualEntryPrice); target > 0 {
+			capture := sign * (t.ActualExitPrice - t.ActualEntryPrice) / target
+			s.CaptureRatio = &capture
+		}
+	}
+
+	if t.PlannedStopLoss > 0 && t.PositionSize > 0 {
+		plannedRisk := t.PlannedRiskAmount
+		if plannedRisk <= 0 && t.PlannedEntryPrice > 0 {
+			plannedRisk = sign * (t.PlannedEntryPrice - t.PlannedStopLoss) * t.PositionSize
+		}
+		if plannedRisk > 0 {
+			s.PlannedRisk = &plannedRisk
+		}
+		if t.ActualEntryPrice > 0 {
+			if actualRisk := sign * (t.ActualEntryPrice - t.PlannedStopLoss) * t.PositionSize; actualRisk > 0 {
+				s.ActualRisk = &actualRisk
+			}
+		}
+		if s.PlannedRisk != nil && s.ActualRisk != nil {
+			ratio := *s.ActualRisk / *s.PlannedRisk
+			s.RiskRatio = &ratio
+		}
+	}
+
+	return s
+}
+
+// SlippageSummary aggregated execution slippage of a group of trades, every average only
+// counts the trades where the metric could be computed
+type SlippageSummary struct {
+	Key                string  `json:"key"`
+	TradeCount         int     `json:"tradeCount"`
+	EntryCount         int     `json:"entryCount"`         // trades with entry slippage
+	AvgEntrySlippage   float64 `json:"avgEntrySlippage"`   // in price units, only comparable within a symbol
+	AvgEntrySlippageR  float64 `json:"avgEntrySlippageR"`  // comparable across symbols
+	MaxEntrySlippageR  float64 `json:"maxEntrySlippageR"`  // worst fill
+	WorseFillCount     int     `json:"worseFillCount"`     // filled worse than planned
+	BetterFillCount    int     `json:"betterFillCount"`    // filled better than planned
+	CaptureCount       int     `json:"captureCount"`       // trades with a capture ratio
+	AvgCaptureRatio    float64 `json:"avgCaptureRatio"`    // average share of the target captured
+	MedianCaptureRatio float64 `json:"medianCaptureRatio"` // robust to outliers
+	RiskCount          int     `json:"riskCount"`          // trades with a risk ratio
+	AvgRiskRatio       float64 `json:"avgRiskRatio"`       // average actual risk / planned risk
+	OverRiskCount      int     `json:"overRiskCount"`      // trades risking more than planned
+}
+
+// SummarizeSlippage aggregate the slippage of trades under key
+func SummarizeSlippage(key string, items []*TradeSlippage) *SlippageSummary {
+	s := &SlippageSummary{Key: key, TradeCount: len(items)}
+	var slippages, slippagesR, captures, risks []float64
+	for _, item := range items {
+		if item.EntrySlippage != nil {
+			slippages = append(slippages, *item.EntrySlippage)
+			if *item.EntrySlippage > 0 {
+				s.WorseFillCount++
+			} else if *item.EntrySlippage < 0 {
+				s.BetterFillCount++
+			}
+		}
+		if item.EntrySlippageR != nil {
+			slippagesR = append(slippagesR, *item.EntrySlippageR)
+			if len(slippagesR) == 1 || *item.EntrySlippageR > s.MaxEntrySlippageR {
+				s.MaxEntrySlippageR = *item.EntrySlippageR
+			}
+		}
+		if item.CaptureRatio != nil {
+			captures = append(captures, *item.CaptureRatio)
+		}
+		if item.RiskRatio != nil {
+			risks = append(risks, *item.RiskRatio)
+			if *item.RiskRatio > 1+1e-9 {
+				s.OverRiskCount++
+			}
+		}
+	}
+	s.EntryCount = len(slippages)
+	s.AvgEntrySlippage = Mean(slippages)
+	s.AvgEntrySlippageR = Mean(slippagesR)
+	s.CaptureCount = len(captures)
+	s.AvgCaptureRatio = Mean(captures)
+	s.MedianCaptureRatio = Percentile(Sorted(captures), 50)
+	s.RiskCount = len(risks)
+	s.AvgRiskRatio = Mean(risks)
+	return s
+}
+
+// SlippageReport planned vs actual execution of trades
+type SlippageReport struct {
+	Overall    *SlippageSummary   `json:"overall"`
+	ByAccount  []*SlippageSummary `json:"byAccount"`  // key is the account id
+	ByStrategy []*SlippageSummary `json:"byStrategy"` // key is the strategy id, 0 means no strategy
+	Trades     []*TradeSlippage   `json:"trades"`
+}
+
+// AnalyzeSlippage compute the slippage of every trade and aggregate it overall, per account and per strategy
+func AnalyzeSlippage(trades []*model.Trades) *SlippageReport {
+	report := &SlippageReport{
+		ByAccount:  []*SlippageSummary{},
+		ByStrategy: []*SlippageSummary{},
+		Trades:     make([]*TradeSlippage, 0, len(trades)),
+	}
+	slippages := make(map[*model.Trades]*TradeSlippage, len(trades))
+	for _, t := range trades {
+		slippages[t] = Slippage(t)
+		report.Trades = append(report.Trades, slippages[t])
+	}
+	summarize := func(key int, group []*model.Trades) *SlippageSummary {
+		items := make([]*TradeSlippage, 0, len(group))
+		for _, t := range group {
+			items = append(items, slippages[t])
+		}
+		return SummarizeSlippage(strconv.Itoa(key), items)
+	}
+	report.Overall = SummarizeSlippage("overall", report.Trades)
+
+	byAccount, accountIDs := GroupBy(trades, func(t *model.Trades) int { return t.AccountID })
+	for _, id := range accountIDs {
+		report.ByAccount = append(report.ByAccount, summarize(id, byAccount[id]))
+	}
+	byStrategy, strategyIDs := GroupBy(trades, func(t *model.Trades) int { return t.StrategyID })
+	for _, id := range strategyIDs {
+		report.ByStrategy = append(report.ByStrategy, summarize(id, byStrategy[id]))
+	}
+	return report
+}
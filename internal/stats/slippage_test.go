@@ -0,0 +1,69 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestSlippage(t *testing.T) {
+	long := &model.Trades{
+		ID: 1, Direction: "long", PositionSize: 10,
+		PlannedEntryPrice: 100, PlannedStopLoss: 98, PlannedTakeProfit: 106, PlannedRiskAmount: 20,
+		ActualEntryPrice: 100.5, ActualExitPrice: 103.75,
+	}
+	s := Slippage(long)
+	assert.InDelta(t, 0.5, *s.EntrySlippage, 1e-9)
+	assert.InDelta(t, 0.25, *s.EntrySlippageR, 1e-9)
+	assert.InDelta(t, 0.590909, *s.CaptureRatio, 1e-6)
+	assert.InDelta(t, 20, *s.PlannedRisk, 1e-9)
+	assert.InDelta(t, 25, *s.ActualRisk, 1e-9)
+	assert.InDelta(t, 1.25, *s.RiskRatio, 1e-9)
+
+	short := &model.Trades{
+		ID: 2, Direction: "sell", PositionSize: 1,
+		PlannedEntryPrice: 50, PlannedStopLoss: 52, PlannedTakeProfit: 44,
+		ActualEntryPrice: 50.5, ActualExitPrice: 44,
+	}
+	s = Slippage(short)
+	assert.Equal(t, model.TradesDirectionShort, s.Direction)
+	assert.InDelta(t, -0.5, *s.EntrySlippage, 1e-9) // better fill
+	assert.InDelta(t, -0.25, *s.EntrySlippageR, 1e-9)
+	assert.InDelta(t, 1, *s.CaptureRatio, 1e-9)
+	assert.InDelta(t, 2, *s.PlannedRisk, 1e-9) // derived from prices
+	assert.InDelta(t, 1.5, *s.ActualRisk, 1e-9)
+	assert.InDelta(t, 0.75, *s.RiskRatio, 1e-9)
+
+	s = Slippage(&model.Trades{ID: 3, Direction: "long", ActualEntryPrice: 10})
+	assert.Nil(t, s.EntrySlippage)
+	assert.Nil(t, s.CaptureRatio)
+	assert.Nil(t, s.RiskRatio)
+
+	s = Slippage(&model.Trades{ID: 4, Direction: "?", PlannedEntryPrice: 10, ActualEntryPrice: 11})
+	assert.Equal(t, "", s.Direction)
+	assert.Nil(t, s.EntrySlippage)
+}
+
+func TestAnalyzeSlippage(t *testing.T) {
+	trades := []*model.Trades{
+		{ID: 1, AccountID: 1, StrategyID: 7, Direction: "long", PositionSize: 1, PlannedEntryPrice: 100, PlannedStopLoss: 99, ActualEntryPrice: 100.5},
+		{ID: 2, AccountID: 1, StrategyID: 8, Direction: "long", PositionSize: 1, PlannedEntryPrice: 100, PlannedStopLoss: 99, ActualEntryPrice: 99.5},
+		{ID: 3, AccountID: 2, StrategyID: 7, Direction: "long", PositionSize: 1, PlannedEntryPrice: 100, PlannedStopLoss: 99, ActualEntryPrice: 101},
+	}
+	report := AnalyzeSlippage(trades)
+	assert.Len(t, report.Trades, 3)
+	assert.Equal(t, 3, report.Overall.EntryCount)
+	assert.Equal(t, 2, report.Overall.WorseFillCount)
+	assert.Equal(t, 1, report.Overall.BetterFillCount)
+	assert.InDelta(t, 1, report.Overall.MaxEntrySlippageR, 1e-9)
+	assert.Equal(t, 2, report.Overall.OverRiskCount)
+
+	assert.Len(t, report.ByAccount, 2)
+	assert.Equal(t, "1", report.ByAccount[0].Key)
+	assert.InDelta(t, 0, report.ByAccount[0].AvgEntrySlippage, 1e-9)
+	assert.Len(t, report.ByStrategy, 2)
+	assert.Equal(t, "7", report.ByStrategy[0].Key)
+	assert.InDelta(t, 0.75, report.ByStrategy[0].AvgEntrySlippageR, 1e-9)
+}
@@ -0,0 +1,131 @@
+// Package stats computes trading performance statistics from closed trades.
+package stats
+
+import (
+	"time"
+
+	"helmsman/internal/model"
+)
+
+// NetPnl realized result of a trade after commission
+func NetPnl(t *model.Trades) float64 {
+	return t.Pnl - t.Commission
+}
+
+// GroupBy split trades into groups by key, keys are returned in the order of first appearance
+func GroupBy[K comparable](trades []*model.Trades, key func(t *model.Trades) K) (map[K][]*model.Trades, []K) {
+	groups := map[K][]*model.Trades{}
+	keys := []K{}
+	for _, t := range trades {
+		k := key(t)
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], t)
+	}
+	return groups, keys
+}
+
+// GroupByMany split trades into groups by keys, a trade is added to the group of every one of its keys
+func GroupByMany[K comparable](trades []*model.Trades, keys func(t *model.Trades) []K) map[K][]*model.Trades {
+	groups := map[K][]*model.Trades{}
+	for _, t := range trades {
+		for _, k := range keys(t) {
+			groups[k] = append(groups[k], t)
+		}
+	}
+	return groups
+}
+
+// Pairs all unordered pairs of distinct values, the values in a pair are in ascending order
+func Pairs(values []int) [][2]int {
+	pairs := [][2]int{}
+	for i := 0; i < len(values); i++ {
+		for j := i + 1; j < len(values); j++ {
+			a, b := values[i], values[j]
+			if a == b {
+				continue
+			}
+			if a > b {
+				a, b = b, a
+			}
+			pairs = append(pairs, [2]int{a, b})
+		}
+	}
+	return pairs
+}
+
+// Summary performance metrics of a group of closed trades
+type Summary struct {
+	TradeCount      int      `json:"tradeCount"`
+	WinCount        int      `json:"winCount"`
+	LossCount       int      `json:"lossCount"`
+	BreakEvenCount  int      `json:"breakEvenCount"`
+	WinRate         float64  `json:"winRate"`         // ratio of winning trades, 0~1
+	AvgWin          float64  `json:"avgWin"`          // average net pnl of winning trades
+	AvgLoss         float64  `json:"avgLoss"`         // average net pnl of losing trades, negative value
+	Expectancy      float64  `json:"expectancy"`      // average net pnl per trade
+	ProfitFactor    *float64 `json:"profitFactor"`    // gross profit / gross loss, null if there is no losing trade
+	AvgR            float64  `json:"avgR"`            // average r_multiple, also the expectancy in R
+	TotalPnl        float64  `json:"totalPnl"`        // sum of pnl before commission
+	TotalCommission float64  `json:"totalCommission"` // sum of commission
+	NetPnl          float64  `json:"netPnl"`          // totalPnl - totalCommission
+	AvgHoldingTime  int64    `json:"avgHoldingTime"`  // seconds, trades with unparsable entry or exit time are excluded
+}
+
+// Summarize compute the performance metrics of trades, a trade is counted as a win
+// or loss by its net pnl.
+func Summarize(trades []*model.Trades) *Summary {
+	s := &Summary{}
+	var grossProfit, grossLoss, sumR float64
+	var holding time.Duration
+	holdingCount := 0
+	for _, t := range trades {
+		net := NetPnl(t)
+		s.TradeCount++
+		s.TotalPnl += t.Pnl
+		s.TotalCommission += t.Commission
+		sumR += t.RMultiple
+		if d, err := HoldingTime(t, nil); err == nil {
+			holding += d
+			holdingCount++
+		}
+		switch {
+		case net > 0:
+			s.WinCount++
+			grossProfit += net
+		case net < 0:
+			s.LossCount++
+			grossLoss += net
+		default:
+			s.BreakEvenCount++
+		}
+	}
+	if s.TradeCount == 0 {
+		return s
+	}
+
+	s.NetPnl = s.TotalPnl - s.TotalCommission
+	s.WinRate = float64(s.WinCount) / float64(s.TradeCount)
+	s.Expectancy = s.NetPnl / float64(s.TradeCount)
+	s.AvgR = sumR / float64(s.TradeCount)
+	if holdingCount > 0 {
+		s.AvgHoldingTime = int64((holding / time.Duration(holdingCount)).Seconds())
+	}
+	if s.WinCount > 0 {
+		s.AvgWin = grossProfit / float64(s.WinCount)
+	}
+	if s.LossCount > 0 {
+		s.AvgLoss = grossLoss / float64(s.LossCount)
+		profitFactor := grossProfit / -grossLoss
+		s.ProfitFactor = &profitFactor
+	}
+
+	return s
+}
+
+// Bucket performance of a group of trades identified by key
+type Bucket struct {
+	Key string `json:"key"`
+	Summary
+}
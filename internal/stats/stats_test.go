@@ -0,0 +1,68 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func newTestTrades() []*model.Trades {
+	return []*model.Trades{
+		{ID: 1, Pnl: 200, Commission: 2, RMultiple: 2, ActualExitTime: "2024-01-02 10:00:00"},
+		{ID: 2, Pnl: -100, Commission: 2, RMultiple: -1, ActualExitTime: "2024-01-03 10:00:00"},
+		{ID: 3, Pnl: 300, Commission: 2, RMultiple: 3, ActualExitTime: "2024-01-04 10:00:00"},
+		{ID: 4, Pnl: -100, Commission: 2, RMultiple: -1, ActualExitTime: "2024-01-05 10:00:00"},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := Summarize(newTestTrades())
+	assert.Equal(t, 4, s.TradeCount)
+	assert.Equal(t, 2, s.WinCount)
+	assert.Equal(t, 2, s.LossCount)
+	assert.InDelta(t, 0.5, s.WinRate, 1e-9)
+	assert.InDelta(t, 248, s.AvgWin, 1e-9)
+	assert.InDelta(t, -102, s.AvgLoss, 1e-9)
+	assert.InDelta(t, 300, s.TotalPnl, 1e-9)
+	assert.InDelta(t, 8, s.TotalCommission, 1e-9)
+	assert.InDelta(t, 292, s.NetPnl, 1e-9)
+	assert.InDelta(t, 73, s.Expectancy, 1e-9)
+	assert.InDelta(t, 496.0/204.0, *s.ProfitFactor, 1e-9)
+	assert.InDelta(t, 0.75, s.AvgR, 1e-9)
+
+	empty := Summarize(nil)
+	assert.Equal(t, 0, empty.TradeCount)
+	assert.Zero(t, empty.WinRate)
+	assert.Nil(t, empty.ProfitFactor)
+
+	noLoss := Summarize([]*model.Trades{{Pnl: 100}})
+	assert.Nil(t, noLoss.ProfitFactor)
+}
+
+func TestGroupBy(t *testing.T) {
+	groups, keys := GroupBy(newTestTrades(), func(t *model.Trades) bool { return t.Pnl > 0 })
+	assert.Equal(t, []bool{true, false}, keys)
+	assert.Len(t, groups[true], 2)
+	assert.Len(t, groups[false], 2)
+}
+
+func TestGroupByMany(t *testing.T) {
+	tags := map[uint64][]int{1: {1, 2}, 2: {2}, 3: {1, 2, 3}}
+	groups := GroupByMany(newTestTrades(), func(t *model.Trades) []int { return tags[t.ID] })
+	assert.Len(t, groups[1], 2)
+	assert.Len(t, groups[2], 3)
+	assert.Len(t, groups[3], 1)
+
+	pairs := GroupByMany(newTestTrades(), func(t *model.Trades) [][2]int { return Pairs(tags[t.ID]) })
+	assert.Len(t, pairs[[2]int{1, 2}], 2)
+	assert.Len(t, pairs[[2]int{2, 3}], 1)
+	assert.Len(t, pairs, 3)
+}
+
+func TestPairs(t *testing.T) {
+	assert.Equal(t, [][2]int{{1, 3}, {2, 3}, {1, 2}}, Pairs([]int{3, 1, 2}))
+	assert.Empty(t, Pairs([]int{1}))
+	assert.Empty(t, Pairs([]int{1, 1}))
+}
@@ -0,0 +1,132 @@
+package stats
+
+import (
+	"helmsman/internal/model"
+)
+
+// streak types
+const (
+	StreakWin  = "win"
+	StreakLoss = "loss"
+)
+
+// Streak consecutive trades with the same outcome, a break-even trade ends any streak
+type Streak struct {
+	Type         string  `json:"type"` // win or loss
+	Length       int     `json:"length"`
+	StartTradeID uint64  `json:"startTradeID"`
+	EndTradeID   uint64  `json:"endTradeID"`
+	NetPnl       float64 `json:"netPnl"`
+}
+
+// StreakCount number of streaks of a length, a streak is counted once at its final length
+type StreakCount struct {
+	Length    int `json:"length"`
+	WinCount  int `json:"winCount"`
+	LossCount int `json:"lossCount"`
+}
+
+// AfterLossesBucket performance of the trades taken right after at least Losses consecutive losses
+type AfterLossesBucket struct {
+	Losses int `json:"losses"`
+	Summary
+	ExpectancyDelta float64 `json:"expectancyDelta"` // expectancy minus the overall expectancy, negative means results degrade after the streak
+}
+
+// StreakReport win/loss streaks of trades in order
+type StreakReport struct {
+	Current     *Streak              `json:"current"`     // streak still running at the last trade, nil if the last trade broke even or there are no trades
+	LongestWin  *Streak              `json:"longestWin"`  // nil if there is no win
+	LongestLoss *Streak              `json:"longestLoss"` // nil if there is no loss
+	Counts      []*StreakCount       `json:"counts"`      // by ascending length
+	AfterLosses []*AfterLossesBucket `json:"afterLosses"` // from 1 to maxLosses consecutive losses
+	Overall     *Summary             `json:"overall"`
+}
+
+func outcome(t *model.Trades) string {
+	net := NetPnl(t)
+	if net > 0 {
+		return StreakWin
+	} else if net < 0 {
+		return StreakLoss
+	}
+	return ""
+}
+
+// AnalyzeStreaks find the win/loss streaks of trades ordered by exit time, and summarize the trades
+// following a streak of at least n losses for n from 1 to maxLosses
+func AnalyzeStreaks(trades []*model.Trades, maxLosses int) *StreakReport {
+	if maxLosses <= 0 {
+		maxLosses = 5
+	}
+	report := &StreakReport{
+		Counts:      []*StreakCount{},
+		AfterLosses: make([]*AfterLossesBucket, 0, maxLosses),
+		Overall:     Summarize(trades),
+	}
+
+	var streaks []*Streak
+	var current *Streak
+	afterLosses := make([][]*model.Trades, maxLosses+1)
+	for _, t := range trades {
+		if current != nil && current.Type == StreakLoss {
+			for n := 1; n <= maxLosses && n <= current.Length; n++ {
+				afterLosses[n] = append(afterLosses[n], t)
+			}
+		}
+
+		typ := outcome(t)
+		if typ == "" {
+			current = nil
+			continue
+		}
+		if current == nil || current.Type != typ {
+			current = &Streak{Type: typ, StartTradeID: t.ID}
+			streaks = append(streaks, current)
+		}
+		current.Length++
+		current.EndTradeID = t.ID
+		current.NetPnl += NetPnl(t)
+	}
+	report.Current = current
+
+	counts := map[int]*StreakCount{}
+	maxLength := 0
+	for _, s := range streaks {
+		count, ok := counts[s.Length]
+		if !ok {
+			count = &StreakCount{Length: s.Length}
+			counts[s.Length] = count
+		}
+		if s.Type == StreakWin {
+			count.WinCount++
+			if report.LongestWin == nil || s.Length > report.LongestWin.Length {
+				report.LongestWin = s
+			}
+		} else {
+			count.LossCount++
+			if report.LongestLoss == nil || s.Length > report.LongestLoss.Length {
+				report.LongestLoss = s
+			}
+		}
+		if s.Length > maxLength {
+			maxLength = s.Length
+		}
+	}
+	for length := 1; length <= maxLength; length++ {
+		if count, ok := counts[length]; ok {
+			report.Counts = append(report.Counts, count)
+		}
+	}
+
+	for n := 1; n <= maxLosses; n++ {
+		summary := Summarize(afterLosses[n])
+		bucket := &AfterLossesBucket{Losses: n, Summary: *summary}
+		if summary.TradeCount > 0 {
+			bucket.ExpectancyDelta = summary.Expectancy - report.Overall.Expectancy
+		}
+		report.AfterLosses = append(report.AfterLosses, bucket)
+	}
+
+	return report
+}
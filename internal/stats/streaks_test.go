@@ -0,0 +1,49 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestAnalyzeStreaks(t *testing.T) {
+	pnls := []float64{100, 100, -50, -50, -50, -100, 0, -50, -50, 100, 100, 100}
+	trades := make([]*model.Trades, 0, len(pnls))
+	for i, pnl := range pnls {
+		trades = append(trades, &model.Trades{ID: uint64(i + 1), Pnl: pnl})
+	}
+
+	report := AnalyzeStreaks(trades, 3)
+	assert.Equal(t, StreakWin, report.Current.Type)
+	assert.Equal(t, 3, report.Current.Length)
+	assert.Equal(t, uint64(10), report.Current.StartTradeID)
+
+	assert.Equal(t, 3, report.LongestWin.Length)
+	assert.Equal(t, 4, report.LongestLoss.Length)
+	assert.Equal(t, uint64(3), report.LongestLoss.StartTradeID)
+	assert.Equal(t, uint64(6), report.LongestLoss.EndTradeID)
+	assert.Equal(t, -250.0, report.LongestLoss.NetPnl)
+
+	assert.Equal(t, []*StreakCount{
+		{Length: 2, WinCount: 1, LossCount: 1},
+		{Length: 3, WinCount: 1},
+		{Length: 4, LossCount: 1},
+	}, report.Counts)
+
+	assert.Len(t, report.AfterLosses, 3)
+	// after 1 loss: trades 4, 5, 6, 7, 9, 10
+	assert.Equal(t, 6, report.AfterLosses[0].TradeCount)
+	// after 2 losses: trades 5, 6, 7, 10
+	assert.Equal(t, 4, report.AfterLosses[1].TradeCount)
+	assert.Equal(t, 1, report.AfterLosses[1].WinCount)
+	// after 3 losses: trades 6, 7
+	assert.Equal(t, 2, report.AfterLosses[2].TradeCount)
+	assert.Less(t, report.AfterLosses[2].ExpectancyDelta, 0.0)
+
+	report = AnalyzeStreaks(nil, 0)
+	assert.Nil(t, report.Current)
+	assert.Nil(t, report.LongestWin)
+	assert.Len(t, report.AfterLosses, 5)
+}
@@ -0,0 +1,95 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"helmsman/internal/model"
+)
+
+// SymbolStats performance of the trades of a symbol
+type SymbolStats struct {
+	Symbol string `json:"symbol"`
+	Summary
+	Long         *Summary `json:"long"`
+	Short        *Summary `json:"short"`
+	BestTradeID  uint64   `json:"bestTradeID"`  // trade with the highest net pnl
+	WorstTradeID uint64   `json:"worstTradeID"` // trade with the lowest net pnl
+}
+
+// SymbolLeaderboard aggregate trades per symbol, symbols are compared case-insensitively
+func SymbolLeaderboard(trades []*model.Trades) []*SymbolStats {
+	groups, symbols := GroupBy(trades, func(t *model.Trades) string {
+		return strings.ToUpper(strings.TrimSpace(t.Symbol))
+	})
+
+	items := make([]*SymbolStats, 0, len(symbols))
+	for _, symbol := range symbols {
+		group := groups[symbol]
+		directions, _ := GroupBy(group, Direction)
+		item := &SymbolStats{
+			Symbol:  symbol,
+			Summary: *Summarize(group),
+			Long:    Summarize(directions[model.TradesDirectionLong]),
+			Short:   Summarize(directions[model.TradesDirectionShort]),
+		}
+		best, worst := group[0], group[0]
+		for _, t := range group[1:] {
+			if NetPnl(t) > NetPnl(best) {
+				best = t
+			}
+			if NetPnl(t) < NetPnl(worst) {
+				worst = t
+			}
+		}
+		item.BestTradeID, item.WorstTradeID = best.ID, worst.ID
+		items = append(items, item)
+	}
+	return items
+}
+
+// SortSymbolStats sort by a metric name or symbol, a leading "-" means descending order, e.g. -netPnl,
+// symbols whose metric is undefined are placed last
+func SortSymbolStats(items []*SymbolStats, field string) error {
+	desc := strings.HasPrefix(field, "-")
+	field = strings.TrimPrefix(field, "-")
+
+	var less func(a, b *SymbolStats) bool
+	defined := func(*SymbolStats) bool { return true }
+	if field == "symbol" {
+		less = func(a, b *SymbolStats) bool { return a.Symbol < b.Symbol }
+	} else {
+		var value func(s *Summary) (float64, bool)
+		for _, metric := range SummaryMetrics {
+			if metric.Name == field {
+				value = metric.Value
+				break
+			}
+		}
+		if value == nil {
+			return fmt.Errorf("unknown sort field %q", field)
+		}
+		less = func(a, b *SymbolStats) bool {
+			va, _ := value(&a.Summary)
+			vb, _ := value(&b.Summary)
+			return va < vb
+		}
+		defined = func(item *SymbolStats) bool {
+			_, ok := value(&item.Summary)
+			return ok
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		// items whose metric is undefined are placed last in either order
+		if di, dj := defined(items[i]), defined(items[j]); di != dj {
+			return di
+		}
+		if desc {
+			return less(items[j], items[i])
+		}
+		return less(items[i], items[j])
+	})
+	return nil
+}
@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestSymbolLeaderboard(t *testing.T) {
+	trades := []*model.Trades{
+		{ID: 1, Symbol: "btc/usd", Direction: "long", Pnl: 300, RMultiple: 3},
+		{ID: 2, Symbol: "BTC/USD", Direction: "Short", Pnl: -100, RMultiple: -1},
+		{ID: 3, Symbol: "ETH/USD", Direction: "buy", Pnl: -50, RMultiple: -0.5},
+		{ID: 4, Symbol: "BTC/USD", Direction: "long", Pnl: 100, RMultiple: 1},
+	}
+	items := SymbolLeaderboard(trades)
+	assert.Len(t, items, 2)
+	btc := items[0]
+	assert.Equal(t, "BTC/USD", btc.Symbol)
+	assert.Equal(t, 3, btc.TradeCount)
+	assert.Equal(t, 2, btc.Long.TradeCount)
+	assert.Equal(t, 1, btc.Short.TradeCount)
+	assert.Equal(t, uint64(1), btc.BestTradeID)
+	assert.Equal(t, uint64(2), btc.WorstTradeID)
+
+	assert.NoError(t, SortSymbolStats(items, "netPnl"))
+	assert.Equal(t, "ETH/USD", items[0].Symbol)
+	assert.NoError(t, SortSymbolStats(items, "-winRate"))
+	assert.Equal(t, "BTC/USD", items[0].Symbol)
+	assert.NoError(t, SortSymbolStats(items, "-symbol"))
+	assert.Equal(t, "ETH/USD", items[0].Symbol)
+	assert.Error(t, SortSymbolStats(items, "unknown"))
+
+	// no losing trade of SOL/USD, its profit factor is undefined
+	items = SymbolLeaderboard(append(trades, &model.Trades{ID: 5, Symbol: "SOL/USD", Pnl: 10}))
+	assert.NoError(t, SortSymbolStats(items, "profitFactor"))
+	assert.Equal(t, []string{"ETH/USD", "BTC/USD", "SOL/USD"}, []string{items[0].Symbol, items[1].Symbol, items[2].Symbol})
+	assert.NoError(t, SortSymbolStats(items, "-profitFactor"))
+	assert.Equal(t, []string{"BTC/USD", "ETH/USD", "SOL/USD"}, []string{items[0].Symbol, items[1].Symbol, items[2].Symbol})
+}
+
+func TestDirection(t *testing.T) {
+	assert.Equal(t, model.TradesDirectionLong, Direction(&model.Trades{Direction: " BUY "}))
+	assert.Equal(t, model.TradesDirectionShort, Direction(&model.Trades{Direction: "short"}))
+	assert.Equal(t, "", Direction(&model.Trades{Direction: "sideways"}))
+}
@@ -0,0 +1,179 @@
+package stats
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"helmsman/internal/model"
+)
+
+// ErrInvalidTime the time string cannot be parsed by any supported layout
+var ErrInvalidTime = errors.New("invalid time format")
+
+// layouts with zone information, the parsed time is converted to the requested location
+var zonedLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -0700 MST",
+}
+
+// layouts without zone information, the wall clock is interpreted in the requested location
+var localLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+}
+
+// ParseTime parse a time string stored in the database, supported formats are datetime
+// strings with or without zone and unix timestamps in seconds or milliseconds. Times
+// without zone are interpreted in loc, if loc is nil, time.Local is used.
+func ParseTime(s string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, ErrInvalidTime
+	}
+
+	for _, layout := range zonedLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.In(loc), nil
+		}
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && n > 0 {
+		if n > 1e11 { // milliseconds
+			return time.UnixMilli(n).In(loc), nil
+		}
+		return time.Unix(n, 0).In(loc), nil
+	}
+
+	return time.Time{}, ErrInvalidTime
+}
+
+// HoldingTime duration between the actual entry and exit time of a trade
+func HoldingTime(t *model.Trades, loc *time.Location) (time.Duration, error) {
+	entryTime, err := ParseTime(t.ActualEntryTime, loc)
+	if err != nil {
+		return 0, err
+	}
+	exitTime, err := ParseTime(t.ActualExitTime, loc)
+	if err != nil {
+		return 0, err
+	}
+	if exitTime.Before(entryTime) {
+		return 0, errors.New("exit time is before entry time")
+	}
+	return exitTime.Sub(entryTime), nil
+}
+
+// TimeRange closed-open interval [Start, End), a zero value means unbounded
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains whether t is in the range
+func (r TimeRange) Contains(t time.Time) bool {
+	if !r.Start.IsZero() && t.Before(r.Start) {
+		return false
+	}
+	if !r.End.IsZero() && !t.Before(r.End) {
+		return false
+	}
+	return true
+}
+
+// IsZero whether the range is unbounded on both sides
+func (r TimeRange) IsZero() bool {
+	return r.Start.IsZero() && r.End.IsZero()
+}
+
+// ParseTimeRange parse the start and end of a range, an empty string means unbounded.
+// If end is a date without time of day, the whole day is included.
+func ParseTimeRange(start string, end string, loc *time.Location) (TimeRange, error) {
+	r := TimeRange{}
+	var err error
+	if start != "" {
+		r.Start, err = ParseTime(start, loc)
+		if err != nil {
+			return r, err
+		}
+	}
+	if end != "" {
+		r.End, err = ParseTime(end, loc)
+		if err != nil {
+			return r, err
+		}
+		if isDateOnly(end) {
+			r.End = r.End.AddDate(0, 0, 1)
+		}
+	}
+	if !r.Start.IsZero() && !r.End.IsZero() && !r.Start.Before(r.End) {
+		return r, errors.New("start time must be before end time")
+	}
+	return r, nil
+}
+
+func isDateOnly(s string) bool {
+	s = strings.TrimSpace(s)
+	return len(s) == len("2006-01-02") && !strings.ContainsAny(s, " T:")
+}
+
+// FilterByExitTime keep the trades whose actual_exit_time is in the range, if the range
+// is unbounded all trades are returned, otherwise trades with an unparsable exit time
+// are dropped.
+func FilterByExitTime(trades []*model.Trades, r TimeRange, loc *time.Location) []*model.Trades {
+	if r.IsZero() {
+		return trades
+	}
+	records := make([]*model.Trades, 0, len(trades))
+	for _, t := range trades {
+		exitTime, err := ParseTime(t.ActualExitTime, loc)
+		if err != nil || !r.Contains(exitTime) {
+			continue
+		}
+		records = append(records, t)
+	}
+	return records
+}
+
+// SortByExitTime sort trades by actual_exit_time in ascending order, trades with an
+// unparsable exit time are placed at the end, ties are ordered by id.
+func SortByExitTime(trades []*model.Trades, loc *time.Location) {
+	times := make(map[uint64]time.Time, len(trades))
+	for _, t := range trades {
+		if exitTime, err := ParseTime(t.ActualExitTime, loc); err == nil {
+			times[t.ID] = exitTime
+		}
+	}
+	sort.SliceStable(trades, func(i, j int) bool {
+		ti, oki := times[trades[i].ID]
+		tj, okj := times[trades[j].ID]
+		if oki != okj {
+			return oki
+		}
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return trades[i].ID < trades[j].ID
+	})
+}
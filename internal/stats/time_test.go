@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestParseTime(t *testing.T) {
+	values := []string{
+		"2024-01-02 10:00:00",
+		"2024-01-02T10:00:00Z",
+		"2024-01-02T10:00:00+00:00",
+		"2024-01-02T10:00",
+		"2024/01/02 10:00:00",
+		"1704189600",
+		"1704189600000",
+	}
+	for _, v := range values {
+		tm, err := ParseTime(v, nil)
+		if assert.NoError(t, err, v) {
+			assert.Equal(t, 2024, tm.Year(), v)
+		}
+	}
+
+	_, err := ParseTime("", nil)
+	assert.ErrorIs(t, err, ErrInvalidTime)
+	_, err = ParseTime("yesterday", nil)
+	assert.ErrorIs(t, err, ErrInvalidTime)
+}
+
+func TestFilterByExitTime(t *testing.T) {
+	r, err := ParseTimeRange("2024-01-03", "2024-01-04", nil)
+	assert.NoError(t, err)
+	trades := FilterByExitTime(newTestTrades(), r, nil)
+	assert.Len(t, trades, 2)
+	assert.Equal(t, uint64(2), trades[0].ID)
+	assert.Equal(t, uint64(3), trades[1].ID)
+
+	_, err = ParseTimeRange("2024-01-04", "2024-01-03", nil)
+	assert.Error(t, err)
+}
+
+func TestSortByExitTime(t *testing.T) {
+	trades := []*model.Trades{
+		{ID: 1, ActualExitTime: "bad"},
+		{ID: 2, ActualExitTime: "2024-01-03T10:00:00Z"},
+		{ID: 3, ActualExitTime: "2024-01-02 10:00:00"},
+	}
+	SortByExitTime(trades, nil)
+	assert.Equal(t, []uint64{3, 2, 1}, []uint64{trades[0].ID, trades[1].ID, trades[2].ID})
+}
+
+func TestHoldingTime(t *testing.T) {
+	d, err := HoldingTime(&model.Trades{ActualEntryTime: "2024-01-02 09:30:00", ActualExitTime: "2024-01-02T10:00:00"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, d)
+
+	_, err = HoldingTime(&model.Trades{ActualEntryTime: "2024-01-02 10:00:00", ActualExitTime: "2024-01-02 09:00:00"}, nil)
+	assert.Error(t, err)
+	_, err = HoldingTime(&model.Trades{ActualExitTime: "2024-01-02 09:00:00"}, nil)
+	assert.Error(t, err)
+
+	s := Summarize([]*model.Trades{
+		{ActualEntryTime: "2024-01-02 09:00:00", ActualExitTime: "2024-01-02 10:00:00"},
+		{ActualEntryTime: "2024-01-02 09:00:00", ActualExitTime: "2024-01-02 12:00:00"},
+		{ActualExitTime: "2024-01-02 12:00:00"},
+	})
+	assert.Equal(t, int64(2*3600), s.AvgHoldingTime)
+}
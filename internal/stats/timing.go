@@ -0,0 +1,202 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"helmsman/internal/model"
+)
+
+// Session a market session window in the wall clock of its time zone
+type Session struct {
+	Name     string
+	Location *time.Location
+	Start    int // minutes of day when the session opens
+	End      int // minutes of day when the session closes, less than Start if the session crosses midnight
+}
+
+// SessionConfig definition of a session window
+type SessionConfig struct {
+	Name     string `json:"name"`
+	Timezone string `json:"timezone"` // IANA time zone of open and close, empty means the time zone of the analysis
+	Open     string `json:"open"`     // HH:MM
+	Close    string `json:"close"`    // HH:MM, earlier than open if the session crosses midnight
+}
+
+// DefaultSessions common market sessions used when the user does not define any
+var DefaultSessions = []SessionConfig{
+	{Name: "Asia", Timezone: "Asia/Tokyo", Open: "09:00", Close: "15:00"},
+	{Name: "London", Timezone: "Europe/London", Open: "08:00", Close: "16:30"},
+	{Name: "New York", Timezone: "America/New_York", Open: "09:30", Close: "16:00"},
+}
+
+// NewSession create a session from its config, an empty timezone means defaultLoc
+func NewSession(config SessionConfig, defaultLoc *time.Location) (*Session, error) {
+	if config.Name == "" {
+		return nil, errors.New("session name cannot be empty")
+	}
+	loc := defaultLoc
+	if config.Timezone != "" {
+		var err error
+		loc, err = LoadLocation(config.Timezone)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	start, err := parseClock(config.Open)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseClock(config.Close)
+	if err != nil {
+		return nil, err
+	}
+	if start == end {
+		return nil, fmt.Errorf("session %s opens and closes at the same time", config.Name)
+	}
+	return &Session{Name: config.Name, Location: loc, Start: start, End: end}, nil
+}
+
+// NewSessions create sessions from configs, if configs is empty DefaultSessions is used
+func NewSessions(configs []SessionConfig, defaultLoc *time.Location) ([]*Session, error) {
+	if len(configs) == 0 {
+		configs = DefaultSessions
+	}
+	sessions := make([]*Session, 0, len(configs))
+	for _, config := range configs {
+		s, err := NewSession(config, defaultLoc)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid clock time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// MinutesSinceOpen minutes elapsed since the session opened, ok is false if t is outside the session
+func (s *Session) MinutesSinceOpen(t time.Time) (int, bool) {
+	t = t.In(s.Location)
+	minute := t.Hour()*60 + t.Minute()
+	if s.Start < s.End {
+		if minute >= s.Start && minute < s.End {
+			return minute - s.Start, true
+		}
+		return 0, false
+	}
+	// crosses midnight
+	if minute >= s.Start {
+		return minute - s.Start, true
+	}
+	if minute < s.End {
+		return minute + 24*60 - s.Start, true
+	}
+	return 0, false
+}
+
+// SessionOffsetBucket performance of trades entered in a time slot after a session opens
+type SessionOffsetBucket struct {
+	Session    string `json:"session"`
+	FromMinute int    `json:"fromMinute"` // minutes since the session opened, inclusive
+	ToMinute   int    `json:"toMinute"`   // exclusive
+	Summary
+}
+
+// TimeAnalysis performance of trades bucketed by the time of their actual entry
+type TimeAnalysis struct {
+	ByHour          []*Bucket              `json:"byHour"`          // 24 buckets, key is the hour 00~23
+	ByWeekday       []*Bucket              `json:"byWeekday"`       // 7 buckets from Monday to Sunday
+	BySession       []*Bucket              `json:"bySession"`       // in the order of sessions, the last bucket is outside of all sessions
+	BySessionOffset []*SessionOffsetBucket `json:"bySessionOffset"` // slots without trades are omitted
+	UnparsableCount int                    `json:"unparsableCount"` // trades whose actual entry time cannot be parsed
+}
+
+// OutsideSessions key of the bucket of trades entered outside of all sessions
+const OutsideSessions = "outside sessions"
+
+// AnalyzeTime bucket trades by the hour and weekday of their entry time in loc, by the sessions
+// they were entered in (a trade is counted in every overlapping session) and by the slot of
+// intervalMinutes since the session opened.
+func AnalyzeTime(trades []*model.Trades, loc *time.Location, sessions []*Session, intervalMinutes int) *TimeAnalysis {
+	if intervalMinutes <= 0 {
+		intervalMinutes = 30
+	}
+	type offsetKey struct {
+		session int
+		slot    int
+	}
+
+	result := &TimeAnalysis{}
+	byHour := make([][]*model.Trades, 24)
+	byWeekday := make([][]*model.Trades, 7)
+	bySession := make([][]*model.Trades, len(sessions)+1)
+	byOffset := map[offsetKey][]*model.Trades{}
+	for _, t := range trades {
+		entryTime, err := ParseTime(t.ActualEntryTime, loc)
+		if err != nil {
+			result.UnparsableCount++
+			continue
+		}
+		byHour[entryTime.Hour()] = append(byHour[entryTime.Hour()], t)
+		weekday := (int(entryTime.Weekday()) + 6) % 7 // Monday first
+		byWeekday[weekday] = append(byWeekday[weekday], t)
+
+		inSession := false
+		for i, s := range sessions {
+			minutes, ok := s.MinutesSinceOpen(entryTime)
+			if !ok {
+				continue
+			}
+			inSession = true
+			bySession[i] = append(bySession[i], t)
+			key := offsetKey{session: i, slot: minutes / intervalMinutes}
+			byOffset[key] = append(byOffset[key], t)
+		}
+		if !inSession {
+			bySession[len(sessions)] = append(bySession[len(sessions)], t)
+		}
+	}
+
+	for hour, group := range byHour {
+		result.ByHour = append(result.ByHour, &Bucket{Key: fmt.Sprintf("%02d", hour), Summary: *Summarize(group)})
+	}
+	for i, group := range byWeekday {
+		weekday := time.Weekday((i + 1) % 7)
+		result.ByWeekday = append(result.ByWeekday, &Bucket{Key: weekday.String(), Summary: *Summarize(group)})
+	}
+	for i, group := range bySession {
+		key := OutsideSessions
+		if i < len(sessions) {
+			key = sessions[i].Name
+		}
+		result.BySession = append(result.BySession, &Bucket{Key: key, Summary: *Summarize(group)})
+	}
+	result.BySessionOffset = []*SessionOffsetBucket{}
+	for i, s := range sessions {
+		for slot := 0; slot*intervalMinutes < 24*60; slot++ {
+			group, ok := byOffset[offsetKey{session: i, slot: slot}]
+			if !ok {
+				continue
+			}
+			result.BySessionOffset = append(result.BySessionOffset, &SessionOffsetBucket{
+				Session:    s.Name,
+				FromMinute: slot * intervalMinutes,
+				ToMinute:   (slot + 1) * intervalMinutes,
+				Summary:    *Summarize(group),
+			})
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"helmsman/internal/model"
+)
+
+func TestNewSession(t *testing.T) {
+	s, err := NewSession(SessionConfig{Name: "night", Open: "22:00", Close: "02:00"}, time.UTC)
+	assert.NoError(t, err)
+	minutes, ok := s.MinutesSinceOpen(time.Date(2024, 1, 2, 1, 15, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, 195, minutes)
+	_, ok = s.MinutesSinceOpen(time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+
+	_, err = NewSession(SessionConfig{Name: "bad", Open: "9:00am", Close: "10:00"}, time.UTC)
+	assert.Error(t, err)
+	_, err = NewSession(SessionConfig{Name: "empty", Open: "10:00", Close: "10:00"}, time.UTC)
+	assert.Error(t, err)
+	_, err = NewSession(SessionConfig{Open: "09:00", Close: "10:00"}, time.UTC)
+	assert.Error(t, err)
+
+	sessions, err := NewSessions(nil, time.UTC)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, len(DefaultSessions))
+}
+
+func TestAnalyzeTime(t *testing.T) {
+	sessions, err := NewSessions([]SessionConfig{
+		{Name: "London", Timezone: "Europe/London", Open: "08:00", Close: "16:30"},
+		{Name: "New York", Timezone: "America/New_York", Open: "09:30", Close: "16:00"},
+	}, time.UTC)
+	assert.NoError(t, err)
+
+	trades := []*model.Trades{
+		// Tuesday, London 08:10
+		{ID: 1, Pnl: -100, RMultiple: -1, ActualEntryTime: "2024-01-02 08:10:00"},
+		// Tuesday, London 14:45 and New York 09:45
+		{ID: 2, Pnl: 200, RMultiple: 2, ActualEntryTime: "2024-01-02 14:45:00"},
+		// Saturday, outside sessions
+		{ID: 3, Pnl: 50, RMultiple: 0.5, ActualEntryTime: "2024-01-06 03:00:00"},
+		{ID: 4, Pnl: 50, ActualEntryTime: ""},
+	}
+	a := AnalyzeTime(trades, time.UTC, sessions, 30)
+	assert.Equal(t, 1, a.UnparsableCount)
+	assert.Len(t, a.ByHour, 24)
+	assert.Equal(t, 1, a.ByHour[8].TradeCount)
+	assert.Len(t, a.ByWeekday, 7)
+	assert.Equal(t, "Tuesday", a.ByWeekday[1].Key)
+	assert.Equal(t, 2, a.ByWeekday[1].TradeCount)
+	assert.Equal(t, "Saturday", a.ByWeekday[5].Key)
+
+	assert.Len(t, a.BySession, 3)
+	assert.Equal(t, 2, a.BySession[0].TradeCount)
+	assert.Equal(t, 1, a.BySession[1].TradeCount)
+	assert.Equal(t, OutsideSessions, a.BySession[2].Key)
+	assert.Equal(t, 1, a.BySession[2].TradeCount)
+
+	assert.Len(t, a.BySessionOffset, 3)
+	first := a.BySessionOffset[0]
+	assert.Equal(t, "London", first.Session)
+	assert.Equal(t, 0, first.FromMinute)
+	assert.Equal(t, 30, first.ToMinute)
+	assert.InDelta(t, -1, first.AvgR, 1e-9)
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/go-dev-frame/sponge/pkg/sgorm/query"
+
+	"helmsman/internal/stats"
 )
 
 var _ time.Time
@@ -12,32 +14,44 @@ var _ time.Time
 
 // CreateAccountsRequest request params
 type CreateAccountsRequest struct {
-	UserID         int     `json:"userID" binding:""`
-	Name           string  `json:"name" binding:""`
-	InitialBalance float64 `json:"initialBalance" binding:""`
-	Currency       string  `json:"currency" binding:""`
+	UserID          int     `json:"userID" binding:""`
+	Name            string  `json:"name" binding:""`
+	InitialBalance  float64 `json:"initialBalance" binding:""`
+	Currency        string  `json:"currency" binding:""`
+	RiskMode        string  `json:"riskMode" binding:"omitempty,oneof=percent amount"` // percent or amount, default is the one that is set
+	RiskPercent     float64 `json:"riskPercent" binding:"gte=0,lte=100"`               // percent of the current balance to risk per trade
+	RiskAmount      float64 `json:"riskAmount" binding:"gte=0"`                        // fixed amount to risk per trade
+	MaxPositionSize float64 `json:"maxPositionSize" binding:"gte=0"`                   // maximum planned position size of the trades of the account, 0 means no maximum
 }
 
 // UpdateAccountsByIDRequest request params
 type UpdateAccountsByIDRequest struct {
 	ID uint64 `json:"id" binding:""` // uint64 id
 
-	UserID         int     `json:"userID" binding:""`
-	Name           string  `json:"name" binding:""`
-	InitialBalance float64 `json:"initialBalance" binding:""`
-	Currency       string  `json:"currency" binding:""`
+	UserID          int     `json:"userID" binding:""`
+	Name            string  `json:"name" binding:""`
+	InitialBalance  float64 `json:"initialBalance" binding:""`
+	Currency        string  `json:"currency" binding:""`
+	RiskMode        string  `json:"riskMode" binding:"omitempty,oneof=percent amount"` // percent or amount, default is the one that is set
+	RiskPercent     float64 `json:"riskPercent" binding:"gte=0,lte=100"`               // percent of the current balance to risk per trade
+	RiskAmount      float64 `json:"riskAmount" binding:"gte=0"`                        // fixed amount to risk per trade
+	MaxPositionSize float64 `json:"maxPositionSize" binding:"gte=0"`                   // maximum planned position size of the trades of the account, 0 means no maximum
 }
 
 // AccountsObjDetail detail
 type AccountsObjDetail struct {
 	ID uint64 `json:"id"` // convert to uint64 id
 
-	UserID         int     `json:"userID"`
-	Name           string  `json:"name"`
-	InitialBalance float64 `json:"initialBalance"`
-	Currency       string  `json:"currency"`
-	CreatedAt      string  `json:"createdAt"`
-	UpdatedAt      string  `json:"updatedAt"`
+	UserID          int     `json:"userID"`
+	Name            string  `json:"name"`
+	InitialBalance  float64 `json:"initialBalance"`
+	Currency        string  `json:"currency"`
+	RiskMode        string  `json:"riskMode"`
+	RiskPercent     float64 `json:"riskPercent"`
+	RiskAmount      float64 `json:"riskAmount"`
+	MaxPositionSize float64 `json:"maxPositionSize"`
+	CreatedAt       string  `json:"createdAt"`
+	UpdatedAt       string  `json:"updatedAt"`
 }
 
 // CreateAccountsReply only for api docs
@@ -85,3 +99,99 @@ type ListAccountssReply struct {
 		Accountss []AccountsObjDetail `json:"accountss"`
 	} `json:"data"` // return data
 }
+
+// GetAccountsStatsRequest request params
+type GetAccountsStatsRequest struct {
+	TradesStatsParams
+}
+
+// GetAccountsStatsReply only for api docs
+type GetAccountsStatsReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Stats stats.Summary `json:"stats"`
+	} `json:"data"` // return data
+}
+
+// GetAccountsEquityCurveRequest request params
+type GetAccountsEquityCurveRequest struct {
+	TradesStatsParams
+}
+
+// GetAccountsEquityCurveReply only for api docs
+type GetAccountsEquityCurveReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		EquityCurve stats.EquityCurve `json:"equityCurve"`
+	} `json:"data"` // return data
+}
+
+// GetAccountsCalendarRequest request params
+type GetAccountsCalendarRequest struct {
+	Period     string `json:"period" form:"period"`         // month like 2024-03 or year like 2024, default is the current month
+	Timezone   string `json:"timezone" form:"timezone"`     // IANA time zone, e.g. Asia/Shanghai, default is the server time zone
+	StrategyID int    `json:"strategyID" form:"strategyID"` // filter by strategy id, 0 means all strategies
+}
+
+// GetAccountsCalendarReply only for api docs
+type GetAccountsCalendarReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Period   string              `json:"period"`
+		Timezone string              `json:"timezone"`
+		Days     []stats.CalendarDay `json:"days"`  // days without trades are omitted
+		Stats    stats.Summary       `json:"stats"` // summary of the whole period
+	} `json:"data"` // return data
+}
+
+// GetAccountsTimeAnalysisRequest request params
+type GetAccountsTimeAnalysisRequest struct {
+	TradesStatsParams
+	Timezone        string `json:"timezone" form:"timezone"`                                        // IANA time zone of entry hour and weekday, default is the server time zone
+	IntervalMinutes int    `json:"intervalMinutes" form:"intervalMinutes" binding:"gte=0,lte=1440"` // size of the time slots since session open, default 30
+}
+
+// GetAccountsTimeAnalysisReply only for api docs
+type GetAccountsTimeAnalysisReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		TimeAnalysis stats.TimeAnalysis `json:"timeAnalysis"`
+	} `json:"data"` // return data
+}
+
+// GetAccountsStreaksRequest request params
+type GetAccountsStreaksRequest struct {
+	TradesStatsParams
+	MaxLosses int `json:"maxLosses" form:"maxLosses" binding:"gte=0,lte=20"` // longest losing streak to report the following trade for, default 5
+}
+
+// GetAccountsStreaksReply only for api docs
+type GetAccountsStreaksReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Streaks stats.StreakReport `json:"streaks"`
+	} `json:"data"` // return data
+}
+
+// GetAccountsCompareRequest request params
+type GetAccountsCompareRequest struct {
+	StrategyID        int    `json:"strategyID" form:"strategyID"`                                  // filter by strategy id, 0 means all strategies
+	CurrentStartTime  string `json:"currentStartTime" form:"currentStartTime" binding:"required"`   // start of the current period, inclusive, e.g. 2024-02-01
+	CurrentEndTime    string `json:"currentEndTime" form:"currentEndTime" binding:"required"`       // end of the current period, a date without time includes the whole day
+	PreviousStartTime string `json:"previousStartTime" form:"previousStartTime" binding:"required"` // start of the period to compare with, inclusive, e.g. 2024-01-01
+	PreviousEndTime   string `json:"previousEndTime" form:"previousEndTime" binding:"required"`     // end of the period to compare with, a date without time includes the whole day
+}
+
+// GetAccountsCompareReply only for api docs
+type GetAccountsCompareReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Comparison stats.PeriodComparison `json:"comparison"`
+	} `json:"data"` // return data
+}
@@ -0,0 +1,81 @@
+package types
+
+import (
+	"time"
+
+	"helmsman/internal/stats"
+)
+
+var _ time.Time
+
+// Tip: suggested filling in the binding rules https://github.com/go-playground/validator in request struct fields tag.
+
+// CreateExecutionsRequest request params
+type CreateExecutionsRequest struct {
+	Side       string  `json:"side" binding:"required"`          // buy or sell
+	Quantity   float64 `json:"quantity" binding:"required,gt=0"` // filled quantity
+	Price      float64 `json:"price" binding:"required,gt=0"`    // fill price
+	Fee        float64 `json:"fee" binding:"gte=0"`              // commission and fees of the fill
+	ExecutedAt string  `json:"executedAt" binding:"required"`    // time of the fill
+}
+
+// UpdateExecutionsByIDRequest request params, empty fields are kept
+type UpdateExecutionsByIDRequest struct {
+	Side       string  `json:"side" binding:""`
+	Quantity   float64 `json:"quantity" binding:"gte=0"`
+	Price      float64 `json:"price" binding:"gte=0"`
+	Fee        float64 `json:"fee" binding:"gte=0"`
+	ExecutedAt string  `json:"executedAt" binding:""`
+}
+
+// ExecutionsObjDetail detail
+type ExecutionsObjDetail struct {
+	ID uint64 `json:"id"` // convert to uint64 id
+
+	TradeID    int     `json:"tradeID"`
+	Side       string  `json:"side"`
+	Quantity   float64 `json:"quantity"`
+	Price      float64 `json:"price"`
+	Fee        float64 `json:"fee"`
+	ExecutedAt string  `json:"executedAt"`
+	CreatedAt  string  `json:"createdAt"`
+	UpdatedAt  string  `json:"updatedAt"`
+}
+
+// CreateExecutionsReply only for api docs
+type CreateExecutionsReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		ID       uint64         `json:"id"`       // id
+		Position stats.Position `json:"position"` // position of the trades derived from its executions
+	} `json:"data"` // return data
+}
+
+// DeleteExecutionsByIDReply only for api docs
+type DeleteExecutionsByIDReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Position stats.Position `json:"position"` // position of the trades derived from its executions
+	} `json:"data"` // return data
+}
+
+// UpdateExecutionsByIDReply only for api docs
+type UpdateExecutionsByIDReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Position stats.Position `json:"position"` // position of the trades derived from its executions
+	} `json:"data"` // return data
+}
+
+// ListExecutionssReply only for api docs
+type ListExecutionssReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Executions []ExecutionsObjDetail `json:"executions"`
+		Position   stats.Position        `json:"position"` // position of the trades derived from its executions
+	} `json:"data"` // return data
+}
@@ -0,0 +1,109 @@
+package types
+
+import (
+	"time"
+
+	"github.com/go-dev-frame/sponge/pkg/sgorm/query"
+
+	"helmsman/internal/stats"
+)
+
+var _ time.Time
+
+// Tip: suggested filling in the binding rules https://github.com/go-playground/validator in request struct fields tag.
+
+// CreateExitReasonsRequest request params
+type CreateExitReasonsRequest struct {
+	UserID int    `json:"userID" binding:""`
+	Code   string `json:"code" binding:""` // e.g. news_exit, normalized to lower snake case, derived from name if empty
+	Name   string `json:"name" binding:"required"`
+}
+
+// UpdateExitReasonsByIDRequest request params
+type UpdateExitReasonsByIDRequest struct {
+	ID uint64 `json:"id" binding:""` // uint64 id
+
+	Code string `json:"code" binding:""` // a changed code is also changed in the trades of the user
+	Name string `json:"name" binding:""`
+}
+
+// ExitReasonsObjDetail detail
+type ExitReasonsObjDetail struct {
+	ID uint64 `json:"id"` // convert to uint64 id
+
+	UserID    int    `json:"userID"`
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// CreateExitReasonsReply only for api docs
+type CreateExitReasonsReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		ID uint64 `json:"id"` // id
+	} `json:"data"` // return data
+}
+
+// DeleteExitReasonsByIDReply only for api docs
+type DeleteExitReasonsByIDReply struct {
+	Code int      `json:"code"` // return code
+	Msg  string   `json:"msg"`  // return information description
+	Data struct{} `json:"data"` // return data
+}
+
+// UpdateExitReasonsByIDReply only for api docs
+type UpdateExitReasonsByIDReply struct {
+	Code int      `json:"code"` // return code
+	Msg  string   `json:"msg"`  // return information description
+	Data struct{} `json:"data"` // return data
+}
+
+// GetExitReasonsByIDReply only for api docs
+type GetExitReasonsByIDReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		ExitReasons ExitReasonsObjDetail `json:"exitReasons"`
+	} `json:"data"` // return data
+}
+
+// ListExitReasonssRequest request params
+type ListExitReasonssRequest struct {
+	query.Params
+}
+
+// ListExitReasonssReply only for api docs
+type ListExitReasonssReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		ExitReasonss []ExitReasonsObjDetail `json:"exitReasonss"`
+	} `json:"data"` // return data
+}
+
+// GetExitReasonsPerformanceRequest request params
+type GetExitReasonsPerformanceRequest struct {
+	TradesStatsParams
+	AccountID uint64 `json:"accountID" form:"accountID"` // filter by account id, 0 means all accounts of the user
+}
+
+// ExitReasonsPerformanceItem performance of the trades closed for an exit reason
+type ExitReasonsPerformanceItem struct {
+	ExitReasonID uint64 `json:"exitReasonID"`
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+	stats.Summary
+}
+
+// GetExitReasonsPerformanceReply only for api docs
+type GetExitReasonsPerformanceReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		ExitReasons []ExitReasonsPerformanceItem `json:"exitReasons"` // in the order of the vocabulary, default exit reasons first
+		Unmapped    []stats.Bucket               `json:"unmapped"`    // trades whose exit_reason is not in the vocabulary, key is the stored value
+	} `json:"data"` // return data
+}
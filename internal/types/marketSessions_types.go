@@ -0,0 +1,88 @@
+package types
+
+import (
+	"time"
+
+	"github.com/go-dev-frame/sponge/pkg/sgorm/query"
+)
+
+var _ time.Time
+
+// Tip: suggested filling in the binding rules https://github.com/go-playground/validator in request struct fields tag.
+
+// CreateMarketSessionsRequest request params
+type CreateMarketSessionsRequest struct {
+	Name      string `json:"name" binding:"required"`      // e.g. London
+	Timezone  string `json:"timezone" binding:""`          // IANA time zone of open and close, empty means the time zone of the analysis
+	OpenTime  string `json:"openTime" binding:"required"`  // HH:MM
+	CloseTime string `json:"closeTime" binding:"required"` // HH:MM, earlier than open if the session crosses midnight
+}
+
+// UpdateMarketSessionsByIDRequest request params
+type UpdateMarketSessionsByIDRequest struct {
+	ID uint64 `json:"id" binding:""` // uint64 id
+
+	Name      string `json:"name" binding:""`
+	Timezone  string `json:"timezone" binding:""`
+	OpenTime  string `json:"openTime" binding:""`
+	CloseTime string `json:"closeTime" binding:""`
+}
+
+// MarketSessionsObjDetail detail
+type MarketSessionsObjDetail struct {
+	ID uint64 `json:"id"` // convert to uint64 id
+
+	UserID    int    `json:"userID"`
+	Name      string `json:"name"`
+	Timezone  string `json:"timezone"`
+	OpenTime  string `json:"openTime"`
+	CloseTime string `json:"closeTime"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// CreateMarketSessionsReply only for api docs
+type CreateMarketSessionsReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		ID uint64 `json:"id"` // id
+	} `json:"data"` // return data
+}
+
+// DeleteMarketSessionsByIDReply only for api docs
+type DeleteMarketSessionsByIDReply struct {
+	Code int      `json:"code"` // return code
+	Msg  string   `json:"msg"`  // return information description
+	Data struct{} `json:"data"` // return data
+}
+
+// UpdateMarketSessionsByIDReply only for api docs
+type UpdateMarketSessionsByIDReply struct {
+	Code int      `json:"code"` // return code
+	Msg  string   `json:"msg"`  // return information description
+	Data struct{} `json:"data"` // return data
+}
+
+// GetMarketSessionsByIDReply only for api docs
+type GetMarketSessionsByIDReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		MarketSessions MarketSessionsObjDetail `json:"marketSessions"`
+	} `json:"data"` // return data
+}
+
+// ListMarketSessionssRequest request params
+type ListMarketSessionssRequest struct {
+	query.Params
+}
+
+// ListMarketSessionssReply only for api docs
+type ListMarketSessionssReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		MarketSessionss []MarketSessionsObjDetail `json:"marketSessionss"`
+	} `json:"data"` // return data
+}
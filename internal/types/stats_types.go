@@ -0,0 +1,13 @@
+package types
+
+// TimeRangeParams range of actual exit time of closed trades
+type TimeRangeParams struct {
+	StartTime string `json:"startTime" form:"startTime"` // start of actual exit time, inclusive, e.g. 2024-01-01 or 2024-01-01 09:30:00
+	EndTime   string `json:"endTime" form:"endTime"`     // end of actual exit time, exclusive, a date without time includes the whole day
+}
+
+// TradesStatsParams common filters of statistics over closed trades
+type TradesStatsParams struct {
+	TimeRangeParams
+	StrategyID int `json:"strategyID" form:"strategyID"` // filter by strategy id, 0 means all strategies
+}
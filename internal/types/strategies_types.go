@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/go-dev-frame/sponge/pkg/sgorm/query"
+
+	"helmsman/internal/stats"
 )
 
 var _ time.Time
@@ -12,29 +14,32 @@ var _ time.Time
 
 // CreateStrategiesRequest request params
 type CreateStrategiesRequest struct {
-	UserID      int    `json:"userID" binding:""`
-	Name        string `json:"name" binding:""`
-	Description string `json:"description" binding:""`
+	UserID        int     `json:"userID" binding:""`
+	Name          string  `json:"name" binding:""`
+	Description   string  `json:"description" binding:""`
+	MinRewardRisk float64 `json:"minRewardRisk" binding:"gte=0"` // minimum planned reward:risk of the trades of the strategy, 0 means no minimum
 }
 
 // UpdateStrategiesByIDRequest request params
 type UpdateStrategiesByIDRequest struct {
 	ID uint64 `json:"id" binding:""` // uint64 id
 
-	UserID      int    `json:"userID" binding:""`
-	Name        string `json:"name" binding:""`
-	Description string `json:"description" binding:""`
+	UserID        int     `json:"userID" binding:""`
+	Name          string  `json:"name" binding:""`
+	Description   string  `json:"description" binding:""`
+	MinRewardRisk float64 `json:"minRewardRisk" binding:"gte=0"` // minimum planned reward:risk of the trades of the strategy, 0 means no minimum
 }
 
 // StrategiesObjDetail detail
 type StrategiesObjDetail struct {
 	ID uint64 `json:"id"` // convert to uint64 id
 
-	UserID      int    `json:"userID"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	CreatedAt   string `json:"createdAt"`
-	UpdatedAt   string `json:"updatedAt"`
+	UserID        int     `json:"userID"`
+	Name          string  `json:"name"`
+	Description   string  `json:"description"`
+	MinRewardRisk float64 `json:"minRewardRisk"`
+	CreatedAt     string  `json:"createdAt"`
+	UpdatedAt     string  `json:"updatedAt"`
 }
 
 // CreateStrategiesReply only for api docs
@@ -95,3 +100,51 @@ type GetAllStrategiesReply struct {
 		Strategiess []StrategiesObjDetail `json:"strategiess"`
 	} `json:"data"` // return data
 }
+
+// GetStrategiesReportRequest request params
+type GetStrategiesReportRequest struct {
+	TimeRangeParams
+	AccountID uint64 `json:"accountID" form:"accountID"` // filter by account id, 0 means all accounts of the user
+}
+
+// StrategiesReportItem performance of a strategy
+type StrategiesReportItem struct {
+	StrategyID uint64 `json:"strategyID"` // 0 means trades without a strategy of the user
+	Name       string `json:"name"`
+	stats.Summary
+}
+
+// GetStrategiesReportReply only for api docs
+type GetStrategiesReportReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Strategies []StrategiesReportItem `json:"strategies"` // sorted by net pnl in descending order
+		Unassigned StrategiesReportItem   `json:"unassigned"`
+	} `json:"data"` // return data
+}
+
+// GetStrategiesSignificanceRequest request params
+type GetStrategiesSignificanceRequest struct {
+	TimeRangeParams
+	AccountID uint64 `json:"accountID" form:"accountID"`                           // filter by account id, 0 means all accounts of the user
+	Resamples int    `json:"resamples" form:"resamples" binding:"gte=0,lte=10000"` // bootstrap resamples, default 2000
+	Seed      int64  `json:"seed" form:"seed"`                                     // random seed for reproducible results, 0 means random
+}
+
+// StrategiesSignificanceItem statistical significance of the edge of a strategy
+type StrategiesSignificanceItem struct {
+	StrategyID uint64 `json:"strategyID"` // 0 means trades without a strategy of the user
+	Name       string `json:"name"`
+	stats.SignificanceReport
+}
+
+// GetStrategiesSignificanceReply only for api docs
+type GetStrategiesSignificanceReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Strategies []StrategiesSignificanceItem `json:"strategies"` // sorted by SQN in descending order
+		Unassigned StrategiesSignificanceItem   `json:"unassigned"`
+	} `json:"data"` // return data
+}
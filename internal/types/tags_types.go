@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/go-dev-frame/sponge/pkg/sgorm/query"
+
+	"helmsman/internal/stats"
 )
 
 var _ time.Time
@@ -82,3 +84,35 @@ type ListTagssReply struct {
 		Tagss []TagsObjDetail `json:"tagss"`
 	} `json:"data"` // return data
 }
+
+// GetTagsPerformanceRequest request params
+type GetTagsPerformanceRequest struct {
+	TradesStatsParams
+	AccountID     uint64 `json:"accountID" form:"accountID"`         // filter by account id, 0 means all accounts of the user
+	MinPairTrades int    `json:"minPairTrades" form:"minPairTrades"` // minimum number of trades of a reported tag pair, default 1
+}
+
+// TagsPerformanceItem performance of the trades with a tag
+type TagsPerformanceItem struct {
+	TagID uint64 `json:"tagID"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+	stats.Summary
+}
+
+// TagPairsPerformanceItem performance of the trades with both tags
+type TagPairsPerformanceItem struct {
+	TagIDs [2]uint64 `json:"tagIDs"`
+	Names  [2]string `json:"names"`
+	stats.Summary
+}
+
+// GetTagsPerformanceReply only for api docs
+type GetTagsPerformanceReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Tags  []TagsPerformanceItem     `json:"tags"`  // sorted by net pnl in descending order
+		Pairs []TagPairsPerformanceItem `json:"pairs"` // sorted by net pnl in descending order
+	} `json:"data"` // return data
+}
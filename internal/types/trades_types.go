@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/go-dev-frame/sponge/pkg/sgorm/query"
+
+	"helmsman/internal/stats"
 )
 
 var _ time.Time
@@ -12,54 +14,62 @@ var _ time.Time
 
 // CreateTradesRequest request params
 type CreateTradesRequest struct {
-	AccountID         int     `json:"accountID" binding:""`
-	StrategyID        int     `json:"strategyID" binding:""`
-	Status            string  `json:"status" binding:""`
-	Symbol            string  `json:"symbol" binding:""`
-	Direction         string  `json:"direction" binding:""`
-	PlannedEntryPrice float64 `json:"plannedEntryPrice" binding:""`
-	PlannedStopLoss   float64 `json:"plannedStopLoss" binding:""`
-	PlannedTakeProfit float64 `json:"plannedTakeProfit" binding:""`
-	PositionSize      float64 `json:"positionSize" binding:""`
-	PlannedRiskAmount float64 `json:"plannedRiskAmount" binding:""`
-	PlanNotes         string  `json:"planNotes" binding:""`
-	ActualEntryTime   string  `json:"actualEntryTime" binding:""`
-	ActualEntryPrice  float64 `json:"actualEntryPrice" binding:""`
-	ActualExitTime    string  `json:"actualExitTime" binding:""`
-	ActualExitPrice   float64 `json:"actualExitPrice" binding:""`
-	Commission        float64 `json:"commission" binding:""`
-	Pnl               float64 `json:"pnl" binding:""`
-	RMultiple         float64 `json:"rMultiple" binding:""`
-	ExitReason        string  `json:"exitReason" binding:""`
-	ExecutionScore    int     `json:"executionScore" binding:""`
-	ReflectionNotes   string  `json:"reflectionNotes" binding:""`
+	AccountID         int      `json:"accountID" binding:""`
+	StrategyID        int      `json:"strategyID" binding:""`
+	Status            string   `json:"status" binding:""` // planned (default), active with the actual entry or closed with the actual entry and exit
+	Symbol            string   `json:"symbol" binding:""`
+	Direction         string   `json:"direction" binding:""` // long or short, buy and sell are accepted as aliases
+	PlannedEntryPrice float64  `json:"plannedEntryPrice" binding:""`
+	PlannedStopLoss   float64  `json:"plannedStopLoss" binding:""`
+	PlannedTakeProfit float64  `json:"plannedTakeProfit" binding:""`
+	PositionSize      float64  `json:"positionSize" binding:""`
+	PlannedRiskAmount float64  `json:"plannedRiskAmount" binding:""`
+	PlanNotes         string   `json:"planNotes" binding:""`
+	ActualEntryTime   string   `json:"actualEntryTime" binding:""`
+	ActualEntryPrice  float64  `json:"actualEntryPrice" binding:""`
+	ActualExitTime    string   `json:"actualExitTime" binding:""`
+	ActualExitPrice   float64  `json:"actualExitPrice" binding:""`
+	MaePrice          float64  `json:"maePrice" binding:""` // worst price reached while the trade was open
+	MfePrice          float64  `json:"mfePrice" binding:""` // best price reached while the trade was open
+	Commission        float64  `json:"commission" binding:""`
+	Pnl               *float64 `json:"pnl" binding:""`        // gross, before commission, computed by the server unless overridePnl is set, then required, 0 is a break-even result
+	RMultiple         float64  `json:"rMultiple" binding:""`  // computed by the server unless overridePnl is set, then computed from pnl if empty
+	ExitReason        string   `json:"exitReason" binding:""` // code of the exit reason vocabulary of the user, a name or known free text is mapped to its code
+	ExecutionScore    int      `json:"executionScore" binding:""`
+	ReflectionNotes   string   `json:"reflectionNotes" binding:""`
+	OverridePnl       bool     `json:"overridePnl" binding:""` // use the pnl and r_multiple in the request, e.g. reported by the broker, the pnl must be before commission
+	Draft             bool     `json:"draft" binding:""`       // save the plan as a draft without checking the plan rules until it is finalized or activated
 }
 
 // UpdateTradesByIDRequest request params
 type UpdateTradesByIDRequest struct {
 	ID uint64 `json:"id" binding:""` // uint64 id
 
-	AccountID         int     `json:"accountID" binding:""`
-	StrategyID        int     `json:"strategyID" binding:""`
-	Status            string  `json:"status" binding:""`
-	Symbol            string  `json:"symbol" binding:""`
-	Direction         string  `json:"direction" binding:""`
-	PlannedEntryPrice float64 `json:"plannedEntryPrice" binding:""`
-	PlannedStopLoss   float64 `json:"plannedStopLoss" binding:""`
-	PlannedTakeProfit float64 `json:"plannedTakeProfit" binding:""`
-	PositionSize      float64 `json:"positionSize" binding:""`
-	PlannedRiskAmount float64 `json:"plannedRiskAmount" binding:""`
-	PlanNotes         string  `json:"planNotes" binding:""`
-	ActualEntryTime   string  `json:"actualEntryTime" binding:""`
-	ActualEntryPrice  float64 `json:"actualEntryPrice" binding:""`
-	ActualExitTime    string  `json:"actualExitTime" binding:""`
-	ActualExitPrice   float64 `json:"actualExitPrice" binding:""`
-	Commission        float64 `json:"commission" binding:""`
-	Pnl               float64 `json:"pnl" binding:""`
-	RMultiple         float64 `json:"rMultiple" binding:""`
-	ExitReason        string  `json:"exitReason" binding:""`
-	ExecutionScore    int     `json:"executionScore" binding:""`
-	ReflectionNotes   string  `json:"reflectionNotes" binding:""`
+	AccountID         int      `json:"accountID" binding:""`
+	StrategyID        int      `json:"strategyID" binding:""`
+	Status            string   `json:"status" binding:""` // cannot be changed, use the transition endpoints
+	Symbol            string   `json:"symbol" binding:""`
+	Direction         string   `json:"direction" binding:""` // long or short, buy and sell are accepted as aliases
+	PlannedEntryPrice float64  `json:"plannedEntryPrice" binding:""`
+	PlannedStopLoss   float64  `json:"plannedStopLoss" binding:""`
+	PlannedTakeProfit float64  `json:"plannedTakeProfit" binding:""`
+	PositionSize      float64  `json:"positionSize" binding:""`
+	PlannedRiskAmount float64  `json:"plannedRiskAmount" binding:""`
+	PlanNotes         string   `json:"planNotes" binding:""`
+	ActualEntryTime   string   `json:"actualEntryTime" binding:""`
+	ActualEntryPrice  float64  `json:"actualEntryPrice" binding:""`
+	ActualExitTime    string   `json:"actualExitTime" binding:""`
+	ActualExitPrice   float64  `json:"actualExitPrice" binding:""`
+	MaePrice          float64  `json:"maePrice" binding:""` // worst price reached while the trade was open
+	MfePrice          float64  `json:"mfePrice" binding:""` // best price reached while the trade was open
+	Commission        float64  `json:"commission" binding:""`
+	Pnl               *float64 `json:"pnl" binding:""`        // gross, before commission, computed by the server unless overridePnl is set, then required, 0 is a break-even result
+	RMultiple         float64  `json:"rMultiple" binding:""`  // computed by the server unless overridePnl is set, then computed from pnl if empty
+	ExitReason        string   `json:"exitReason" binding:""` // code of the exit reason vocabulary of the user, a name or known free text is mapped to its code
+	ExecutionScore    int      `json:"executionScore" binding:""`
+	ReflectionNotes   string   `json:"reflectionNotes" binding:""`
+	OverridePnl       bool     `json:"overridePnl" binding:""` // use the pnl and r_multiple in the request, e.g. reported by the broker, the pnl must be before commission
+	Draft             *bool    `json:"draft" binding:""`       // true keeps or makes the trades a draft, false finalizes a draft and checks its plan against the plan rules, empty keeps the current state
 }
 
 // TradesObjDetail detail
@@ -77,16 +87,22 @@ type TradesObjDetail struct {
 	PositionSize      float64 `json:"positionSize"`
 	PlannedRiskAmount float64 `json:"plannedRiskAmount"`
 	PlanNotes         string  `json:"planNotes"`
+	Draft             bool    `json:"draft"` // the plan has not been checked against the plan rules yet
 	ActualEntryTime   string  `json:"actualEntryTime"`
 	ActualEntryPrice  float64 `json:"actualEntryPrice"`
 	ActualExitTime    string  `json:"actualExitTime"`
 	ActualExitPrice   float64 `json:"actualExitPrice"`
+	MaePrice          float64 `json:"maePrice"`
+	MfePrice          float64 `json:"mfePrice"`
 	Commission        float64 `json:"commission"`
-	Pnl               float64 `json:"pnl"`
+	Pnl               float64 `json:"pnl"` // gross, before commission, the net pnl is pnl - commission
 	RMultiple         float64 `json:"rMultiple"`
 	ExitReason        string  `json:"exitReason"`
 	ExecutionScore    int     `json:"executionScore"`
 	ReflectionNotes   string  `json:"reflectionNotes"`
+	ActivatedAt       string  `json:"activatedAt"`
+	ClosedAt          string  `json:"closedAt"`
+	CancelledAt       string  `json:"cancelledAt"`
 	CreatedAt         string  `json:"createdAt"`
 	UpdatedAt         string  `json:"updatedAt"`
 }
@@ -136,3 +152,259 @@ type ListTradessReply struct {
 		Tradess []TradesObjDetail `json:"tradess"`
 	} `json:"data"` // return data
 }
+
+// GetTradesSymbolStatsRequest request params
+type GetTradesSymbolStatsRequest struct {
+	TradesStatsParams
+	AccountID uint64 `json:"accountID" form:"accountID"` // filter by account id, 0 means all accounts of the user
+	Sort      string `json:"sort" form:"sort"`           // metric to sort by, e.g. netPnl, winRate, avgR, tradeCount, a leading "-" means descending, default -netPnl
+}
+
+// GetTradesSymbolStatsReply only for api docs
+type GetTradesSymbolStatsReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Symbols []stats.SymbolStats `json:"symbols"`
+	} `json:"data"` // return data
+}
+
+// GetTradesRDistributionRequest request params
+type GetTradesRDistributionRequest struct {
+	TradesStatsParams
+	Columns     []query.Column `json:"columns"`                     // custom conditions in the same format as /trades/list, page and sort are not used
+	Edges       []float64      `json:"edges"`                       // explicit ascending bucket edges in R, overrides bucketSize, min and max
+	BucketSize  float64        `json:"bucketSize"`                  // width of a bucket in R, default 0.5
+	Min         float64        `json:"min"`                         // first bucket edge in R, default -3 if both min and max are 0
+	Max         float64        `json:"max"`                         // last bucket edge in R, default 5 if both min and max are 0
+	Percentiles []float64      `json:"percentiles"`                 // percentiles to report (0~100), default 5, 10, 25, 50, 75, 90, 95
+	Top         int            `json:"top" binding:"gte=0,lte=100"` // number of largest winners and losers, default 5
+}
+
+// GetTradesRDistributionReply only for api docs
+type GetTradesRDistributionReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Distribution stats.RDistribution `json:"distribution"`
+	} `json:"data"` // return data
+}
+
+// GetTradesSlippageRequest request params
+type GetTradesSlippageRequest struct {
+	TradesStatsParams
+	AccountID uint64 `json:"accountID" form:"accountID"` // filter by account id, 0 means all accounts of the user
+}
+
+// GetTradesSlippageReply only for api docs
+type GetTradesSlippageReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Slippage stats.SlippageReport `json:"slippage"`
+	} `json:"data"` // return data
+}
+
+// GetTradesExecutionScoreRequest request params
+type GetTradesExecutionScoreRequest struct {
+	TradesStatsParams
+	AccountID uint64 `json:"accountID" form:"accountID"` // filter by account id, 0 means all accounts of the user
+	Period    string `json:"period" form:"period"`       // period of the correlation series, week or month, default month
+	Timezone  string `json:"timezone" form:"timezone"`   // IANA time zone to split periods, e.g. America/New_York, default server local time
+}
+
+// GetTradesExecutionScoreReply only for api docs
+type GetTradesExecutionScoreReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		ExecutionScore stats.ScoreReport `json:"executionScore"`
+	} `json:"data"` // return data
+}
+
+// GetTradesHoldingPeriodsRequest request params
+type GetTradesHoldingPeriodsRequest struct {
+	TradesStatsParams
+	AccountID uint64 `json:"accountID" form:"accountID"` // filter by account id, 0 means all accounts of the user
+	Edges     []int  `json:"edges" form:"edges"`         // custom ascending bucket edges in minutes, e.g. edges=15&edges=240, default scalp/intraday/swing/position
+	Timezone  string `json:"timezone" form:"timezone"`   // IANA time zone of the stored entry and exit time, default server local time
+}
+
+// GetTradesHoldingPeriodsReply only for api docs
+type GetTradesHoldingPeriodsReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		HoldingPeriods stats.HoldingAnalysis `json:"holdingPeriods"`
+	} `json:"data"` // return data
+}
+
+// GetTradesMonteCarloRequest request params
+type GetTradesMonteCarloRequest struct {
+	TradesStatsParams
+	AccountID           uint64    `json:"accountID"`           // resample the trades of an account, 0 means all accounts of the user
+	Runs                int       `json:"runs"`                // number of simulated paths, default 1000, max 10000
+	Trades              int       `json:"trades"`              // future trades per path, default 100, max 1000
+	RiskPercent         float64   `json:"riskPercent"`         // percent of current equity risked per trade, default 1
+	DrawdownPercent     float64   `json:"drawdownPercent"`     // drawdown threshold in percent, default 20
+	ProfitTargetPercent float64   `json:"profitTargetPercent"` // profit target in percent, default 20
+	Percentiles         []float64 `json:"percentiles"`         // percentiles of the equity bands, default 5, 25, 50, 75, 95
+	Seed                int64     `json:"seed"`                // random seed for reproducible results, 0 means random
+}
+
+// GetTradesMonteCarloReply only for api docs
+type GetTradesMonteCarloReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		MonteCarlo stats.MonteCarloResult `json:"monteCarlo"`
+	} `json:"data"` // return data
+}
+
+// GetTradesRiskRequest request params
+type GetTradesRiskRequest struct {
+	TradesStatsParams
+	AccountID   uint64  `json:"accountID" form:"accountID"`                             // filter by account id, 0 means all accounts of the user
+	RiskPercent float64 `json:"riskPercent" form:"riskPercent" binding:"gte=0,lte=100"` // percent of equity risked per trade, default 1
+	RuinPercent float64 `json:"ruinPercent" form:"ruinPercent" binding:"gte=0,lte=100"` // drawdown percent considered ruin, default 50
+}
+
+// GetTradesRiskReply only for api docs
+type GetTradesRiskReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Risk stats.RiskReport `json:"risk"`
+	} `json:"data"` // return data
+}
+
+// GetTradesExcursionsRequest request params
+type GetTradesExcursionsRequest struct {
+	TradesStatsParams
+	AccountID uint64 `json:"accountID" form:"accountID"` // filter by account id, 0 means all accounts of the user
+}
+
+// GetTradesExcursionsReply only for api docs
+type GetTradesExcursionsReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Excursions stats.ExcursionReport `json:"excursions"`
+	} `json:"data"` // return data
+}
+
+// UpdateTradesExcursionRequest request params
+type UpdateTradesExcursionRequest struct {
+	Bars     []*stats.Bar `json:"bars" binding:"max=100000"` // bars of the symbol covering the trade, bars outside the actual entry and exit time are ignored
+	Timezone string       `json:"timezone"`                  // IANA time zone of the trade and bar times, e.g. America/New_York, default server local time
+}
+
+// UpdateTradesExcursionReply only for api docs
+type UpdateTradesExcursionReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		MaePrice  float64              `json:"maePrice"`
+		MfePrice  float64              `json:"mfePrice"`
+		Excursion stats.TradeExcursion `json:"excursion"`
+	} `json:"data"` // return data
+}
+
+// GetTradesDirectionsRequest request params
+type GetTradesDirectionsRequest struct {
+	TradesStatsParams
+	AccountID uint64 `json:"accountID" form:"accountID"` // filter by account id, 0 means all accounts of the user
+}
+
+// GetTradesDirectionsReply only for api docs
+type GetTradesDirectionsReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Directions stats.DirectionReport `json:"directions"`
+	} `json:"data"` // return data
+}
+
+// GetTradesRollingRequest request params
+type GetTradesRollingRequest struct {
+	TradesStatsParams
+	AccountID uint64 `json:"accountID" form:"accountID"`                   // filter by account id, 0 means all accounts of the user
+	Trades    int    `json:"trades" form:"trades" binding:"gte=0,lte=500"` // size of the rolling trade window, default 20
+	Days      int    `json:"days" form:"days" binding:"gte=0,lte=365"`     // size of the rolling day window, default 30
+	Timezone  string `json:"timezone" form:"timezone"`                     // IANA time zone to split days, e.g. America/New_York, default server local time
+}
+
+// GetTradesRollingReply only for api docs
+type GetTradesRollingReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Rolling stats.RollingReport `json:"rolling"`
+	} `json:"data"` // return data
+}
+
+// ActivateTradesRequest request params
+type ActivateTradesRequest struct {
+	ActualEntryPrice float64 `json:"actualEntryPrice"` // required
+	ActualEntryTime  string  `json:"actualEntryTime"`  // required, e.g. 2024-01-01 09:30:00
+}
+
+// CloseTradesRequest request params
+type CloseTradesRequest struct {
+	ActualExitPrice float64  `json:"actualExitPrice"` // required
+	ActualExitTime  string   `json:"actualExitTime"`  // required, not before the actual entry time
+	Commission      float64  `json:"commission"`
+	Pnl             *float64 `json:"pnl"`         // gross, before commission, computed by the server unless overridePnl is set, then required, 0 is a break-even result
+	RMultiple       float64  `json:"rMultiple"`   // computed by the server unless overridePnl is set, then computed from pnl if empty
+	ExitReason      string   `json:"exitReason"`  // code of the exit reason vocabulary of the user, a name or known free text is mapped to its code
+	OverridePnl     bool     `json:"overridePnl"` // use the pnl and r_multiple in the request, e.g. reported by the broker, the pnl must be before commission
+}
+
+// TransitionTradesReply only for api docs
+type TransitionTradesReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Status       string `json:"status"`       // status after the transition
+		TransitionAt string `json:"transitionAt"` // time of the transition
+	} `json:"data"` // return data
+}
+
+// GetTradesPositionSizeRequest request params
+type GetTradesPositionSizeRequest struct {
+	AccountID    uint64  `json:"accountID" binding:"required"` // account whose risk settings and current balance are used
+	Symbol       string  `json:"symbol" binding:"required"`
+	Direction    string  `json:"direction" binding:""`         // long or short, implied by the side of the stop loss if empty
+	EntryPrice   float64 `json:"entryPrice" binding:"gt=0"`    // planned entry price
+	StopLoss     float64 `json:"stopLoss" binding:"gt=0"`      // planned stop loss
+	PointValue   float64 `json:"pointValue" binding:"gte=0"`   // value of a price move of 1 per unit, e.g. the contract multiplier, default 1
+	QuantityStep float64 `json:"quantityStep" binding:"gte=0"` // the size is rounded down to a multiple of it, e.g. 1 for whole shares, 0 means no rounding
+}
+
+// GetTradesPositionSizeReply only for api docs
+type GetTradesPositionSizeReply struct {
+	Code int    `json:"code"` // return code
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Sizing stats.PositionSizing `json:"sizing"`
+	} `json:"data"` // return data
+}
+
+// TradesPlanViolation a plan rule violated by a field of the request
+type TradesPlanViolation struct {
+	Field string  `json:"field"` // json name of the field
+	Rule  string  `json:"rule"`  // stop_side, take_profit_side, reward_risk or max_position_size
+	Code  int     `json:"code"`  // error code of the rule
+	Msg   string  `json:"msg"`   // error message of the rule
+	Value float64 `json:"value"` // value of the field, the planned reward:risk for the reward_risk rule
+	Limit float64 `json:"limit"` // entry price for the side rules, the minimum or maximum otherwise
+}
+
+// TradesPlanErrorReply only for api docs, returned by create and update if the plan violates the plan rules
+type TradesPlanErrorReply struct {
+	Code int    `json:"code"` // error code of the plan rules
+	Msg  string `json:"msg"`  // return information description
+	Data struct {
+		Violations []TradesPlanViolation `json:"violations"`
+	} `json:"data"` // return data
+}
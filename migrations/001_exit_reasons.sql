@@ -0,0 +1,66 @@
+-- 出场原因词表：将 trades.exit_reason 从自由文本迁移为用户管理的出场原因代码
+-- 执行方式：sqlite3 helmsman.db < migrations/001_exit_reasons.sql
+BEGIN TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS exit_reasons (
+                              id INTEGER PRIMARY KEY AUTOINCREMENT,        -- 出场原因唯一ID
+                              user_id INTEGER NOT NULL,                    -- 关联的用户ID，0 表示所有用户共享的默认出场原因
+                              code TEXT NOT NULL,                          -- 出场原因代码（小写下划线，如 stop_hit），存储在 trades.exit_reason
+                              name TEXT NOT NULL,                          -- 出场原因名称
+                              created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- 创建时间
+                              updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- 最后更新时间
+                              UNIQUE(user_id, code)                       -- 确保用户下出场原因代码唯一
+);
+
+-- 默认出场原因
+INSERT OR IGNORE INTO exit_reasons (user_id, code, name) VALUES
+    (0, 'stop_hit', 'Stop hit'),
+    (0, 'target_hit', 'Target hit'),
+    (0, 'manual', 'Manual'),
+    (0, 'time_stop', 'Time stop'),
+    (0, 'trailing_stop', 'Trailing stop'),
+    (0, 'break_even', 'Break even'),
+    (0, 'other', 'Other');
+
+-- 与 stats.NormalizeExitReason 相同的分词：转小写后所有非 [a-z0-9] 字符都视为分隔符，sl/tp 等缩写必须是完整的词
+CREATE TEMP TABLE exit_reason_words AS
+WITH RECURSIVE chars(id, pos, words) AS (
+    SELECT id, 1, '' FROM trades WHERE exit_reason IS NOT NULL AND exit_reason <> ''
+    UNION ALL
+    SELECT c.id, c.pos + 1, c.words || CASE WHEN lower(substr(t.exit_reason, c.pos, 1)) GLOB '[a-z0-9]'
+                                            THEN lower(substr(t.exit_reason, c.pos, 1)) ELSE ' ' END
+    FROM chars c JOIN trades t ON t.id = c.id
+    WHERE c.pos <= length(t.exit_reason)
+)
+SELECT c.id, ' ' || c.words || ' ' AS words FROM chars c JOIN trades t ON t.id = c.id
+WHERE c.pos = length(t.exit_reason) + 1;
+
+-- 按关键词把已有的自由文本映射为默认代码，顺序与 stats.NormalizeExitReason 一致，LIKE 中的 _ 需要转义
+UPDATE trades SET exit_reason = CASE
+    WHEN exit_reason LIKE '%trail%' OR exit_reason LIKE '%移动止损%' OR exit_reason LIKE '%跟踪止损%' THEN 'trailing_stop'
+    WHEN exit_reason LIKE '%time%' OR exit_reason LIKE '%expire%' OR exit_reason LIKE '%eod%' OR exit_reason LIKE '%end of day%'
+        OR exit_reason LIKE '%时间%' THEN 'time_stop'
+    WHEN exit_reason LIKE '%break even%' OR exit_reason LIKE '%breakeven%' OR exit_reason LIKE '%break\_even%' ESCAPE '\'
+        OR exit_reason LIKE '%保本%' THEN 'break_even'
+    WHEN exit_reason LIKE '%stop%' OR (SELECT words FROM exit_reason_words w WHERE w.id = trades.id) LIKE '% sl %'
+        OR exit_reason LIKE '%止损%' THEN 'stop_hit'
+    WHEN exit_reason LIKE '%target%' OR (SELECT words FROM exit_reason_words w WHERE w.id = trades.id) LIKE '% tp %'
+        OR exit_reason LIKE '%take profit%' OR exit_reason LIKE '%止盈%' OR exit_reason LIKE '%目标%' THEN 'target_hit'
+    WHEN exit_reason LIKE '%manual%' OR exit_reason LIKE '%discretion%' OR exit_reason LIKE '%手动%'
+        OR exit_reason LIKE '%主动%' THEN 'manual'
+    ELSE exit_reason
+END
+WHERE exit_reason IS NOT NULL AND exit_reason <> ''
+  AND exit_reason NOT IN (SELECT code FROM exit_reasons WHERE user_id = 0);
+
+DROP TABLE exit_reason_words;
+
+-- 无法识别的原因归为 other，原文追加到复盘笔记中以免丢失
+UPDATE trades SET
+    reflection_notes = CASE WHEN reflection_notes IS NULL OR reflection_notes = '' THEN '' ELSE reflection_notes || char(10) END
+        || 'exit reason: ' || exit_reason,
+    exit_reason = 'other'
+WHERE exit_reason IS NOT NULL AND exit_reason <> ''
+  AND exit_reason NOT IN (SELECT code FROM exit_reasons WHERE user_id = 0);
+
+COMMIT;
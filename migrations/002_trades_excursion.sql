@@ -0,0 +1,4 @@
+-- 交易日志表增加 MAE/MFE 价格字段
+-- 执行方式：sqlite3 helmsman.db < migrations/002_trades_excursion.sql
+ALTER TABLE trades ADD COLUMN mae_price REAL;  -- 最大不利偏移价格（MAE），持仓期间最差价格
+ALTER TABLE trades ADD COLUMN mfe_price REAL;  -- 最大有利偏移价格（MFE），持仓期间最好价格
@@ -0,0 +1,11 @@
+-- 规范交易方向：将 trades.direction 统一为 long/short
+-- 执行方式：sqlite3 helmsman.db < migrations/003_trades_direction.sql
+BEGIN TRANSACTION;
+
+UPDATE trades SET direction = 'long' WHERE lower(trim(direction)) IN ('long', 'buy');
+UPDATE trades SET direction = 'short' WHERE lower(trim(direction)) IN ('short', 'sell');
+
+COMMIT;
+
+-- 无法识别的方向需要人工修正
+SELECT id, direction FROM trades WHERE direction NOT IN ('long', 'short');
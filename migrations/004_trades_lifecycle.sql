@@ -0,0 +1,22 @@
+-- 交易状态机：增加状态流转时间字段
+-- 执行方式：sqlite3 helmsman.db < migrations/004_trades_lifecycle.sql
+BEGIN TRANSACTION;
+
+ALTER TABLE trades ADD COLUMN activated_at TIMESTAMP;  -- 变为 active 的时间
+ALTER TABLE trades ADD COLUMN closed_at TIMESTAMP;     -- 变为 closed 的时间
+ALTER TABLE trades ADD COLUMN cancelled_at TIMESTAMP;  -- 变为 cancelled 的时间
+
+-- 规范交易状态：将自由文本的 status 统一为 planned/active/closed/cancelled
+UPDATE trades SET status = 'planned' WHERE lower(trim(status)) IN ('planned', 'plan', 'pending');
+UPDATE trades SET status = 'active' WHERE lower(trim(status)) IN ('active', 'open', 'opened', 'running', 'in progress');
+UPDATE trades SET status = 'closed' WHERE lower(trim(status)) IN ('closed', 'close', 'done', 'finished', 'exited');
+UPDATE trades SET status = 'cancelled' WHERE lower(trim(status)) IN ('cancelled', 'canceled', 'cancel', 'void');
+
+-- 已有交易没有流转记录，以实际入场/出场时间近似
+UPDATE trades SET activated_at = actual_entry_time WHERE status IN ('active', 'closed');
+UPDATE trades SET closed_at = actual_exit_time WHERE status = 'closed';
+
+COMMIT;
+
+-- 无法识别的状态需要人工修正，修正为 active/closed 后需补充 activated_at/closed_at
+SELECT id, status FROM trades WHERE status NOT IN ('planned', 'active', 'closed', 'cancelled');
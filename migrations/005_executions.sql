@@ -0,0 +1,19 @@
+-- 成交记录：增加交易的成交明细表，交易的入场/出场、仓位、盈亏和状态由成交推导
+-- 执行方式：sqlite3 helmsman.db < migrations/005_executions.sql
+BEGIN TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS executions (
+                            id INTEGER PRIMARY KEY AUTOINCREMENT,        -- 成交唯一ID
+                            trade_id INTEGER NOT NULL,                   -- 关联的交易ID
+                            side TEXT NOT NULL,                          -- 成交方向：buy/sell，与交易方向相同为入场，相反为出场
+                            quantity REAL NOT NULL,                      -- 成交数量
+                            price REAL NOT NULL,                         -- 成交价格
+                            fee REAL DEFAULT 0,                          -- 手续费
+                            executed_at TIMESTAMP NOT NULL,              -- 成交时间
+                            created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- 记录创建时间
+                            updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP   -- 记录最后更新时间
+);
+
+-- 已有交易没有成交明细，保留手工录入的入场/出场，添加第一笔成交后由成交推导
+
+COMMIT;
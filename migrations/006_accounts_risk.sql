@@ -0,0 +1,9 @@
+-- 仓位计算：增加账户的单笔风险设置
+-- 执行方式：sqlite3 helmsman.db < migrations/006_accounts_risk.sql
+BEGIN TRANSACTION;
+
+ALTER TABLE accounts ADD COLUMN risk_mode TEXT;     -- 单笔风险方式：percent/amount，为空时取已设置的一项
+ALTER TABLE accounts ADD COLUMN risk_percent REAL;  -- 单笔风险占当前余额的百分比
+ALTER TABLE accounts ADD COLUMN risk_amount REAL;   -- 单笔固定风险金额
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- 交易计划校验：增加策略的最小盈亏比和账户的单笔最大持仓
+-- 执行方式：sqlite3 helmsman.db < migrations/007_trades_plan_rules.sql
+BEGIN TRANSACTION;
+
+ALTER TABLE strategies ADD COLUMN min_reward_risk REAL;   -- 计划的最小盈亏比，为空或 0 表示不限制
+ALTER TABLE accounts ADD COLUMN max_position_size REAL;   -- 单笔最大持仓大小，为空或 0 表示不限制
+
+COMMIT;
@@ -0,0 +1,16 @@
+-- 市场时段：增加用户定义的交易时段表，按入场时间分析使用用户的时段，没有定义时使用亚洲/伦敦/纽约默认时段
+-- 执行方式：sqlite3 helmsman.db < migrations/008_market_sessions.sql
+BEGIN TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS market_sessions (
+                            id INTEGER PRIMARY KEY AUTOINCREMENT,        -- 时段唯一ID
+                            user_id INTEGER NOT NULL,                    -- 关联的用户ID
+                            name TEXT NOT NULL,                          -- 时段名称（如 London）
+                            timezone TEXT,                               -- 开盘/收盘时间的 IANA 时区，为空表示分析使用的时区
+                            open_time TEXT NOT NULL,                     -- 开盘时间 HH:MM
+                            close_time TEXT NOT NULL,                    -- 收盘时间 HH:MM，早于开盘时间表示跨越午夜
+                            created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- 创建时间
+                            updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP   -- 最后更新时间
+);
+
+COMMIT;
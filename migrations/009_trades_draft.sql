@@ -0,0 +1,7 @@
+-- 交易计划草稿：保存草稿标记，定稿或激活时再按计划规则校验
+-- 执行方式：sqlite3 helmsman.db < migrations/009_trades_draft.sql
+BEGIN TRANSACTION;
+
+ALTER TABLE trades ADD COLUMN draft INTEGER NOT NULL DEFAULT 0;   -- 1 表示计划未经规则校验，已有交易视为已定稿
+
+COMMIT;